@@ -0,0 +1,525 @@
+package asciidoc
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// TemplateIssue is a problem found in an override template by CheckTemplates.
+type TemplateIssue struct {
+	// Template is the name of the template e.g. struct.
+	Template string
+	// Line is the line, starting from 1, in the template or zero when unknown.
+	Line int
+	// Column is the column, starting from 1, in the template or zero when unknown.
+	Column int
+	// Message describes the problem.
+	Message string
+}
+
+// String renders the issue as template:line:column: message.
+func (ti TemplateIssue) String() string {
+	switch {
+	case ti.Line > 0 && ti.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", ti.Template, ti.Line, ti.Column, ti.Message)
+	case ti.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", ti.Template, ti.Line, ti.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", ti.Template, ti.Message)
+}
+
+// TemplateTypes returns all template types, in render order.
+func TemplateTypes() []TemplateType {
+	return []TemplateType{
+		IndexTemplate,
+		PackageTemplate,
+		PackageRefTemplate,
+		PackageRefsTemplate,
+		ImportTemplate,
+		FunctionsTemplate,
+		FunctionTemplate,
+		InterfacesTemplate,
+		InterfaceTemplate,
+		StructsTemplate,
+		StructTemplate,
+		ReceiversTemplate,
+		TypesTemplate,
+		MembersTemplate,
+		TestReportTemplate,
+		DiagnosticsTemplate,
+		CustomVarTypeDefsTemplate,
+		CustomVarTypeDefTemplate,
+		CustomFuncTypeDefsTemplate,
+		CustomFuncTypeDefTemplate,
+		VarDeclarationsTemplate,
+		VarDeclarationTemplate,
+		ConstDeclarationsTemplate,
+		ConstDeclarationTemplate,
+	}
+}
+
+// syntheticModule is the module of the synthetic model templates are checked against.
+const syntheticModule = "module example.com/synthetic\n\ngo 1.22\n"
+
+// syntheticSource is the synthetic model templates are checked against. It contains all
+// kinds of declarations, including generics, anonymous structs and interfaces with type sets.
+const syntheticSource = `// Package synthetic is a synthetic model used to check templates.
+package synthetic
+
+// Number is a type set constraint.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+// Reader reads values.
+type Reader interface {
+	// Read reads a value.
+	Read(p []byte) (n int, err error)
+	Number
+}
+
+// Pair is a generic pair.
+type Pair[K comparable, V Number] struct {
+	// Key is the key.
+	Key K ` + "`json:\"key\" yaml:\"key\" xml:\"key\" toml:\"key\"`" + `
+	// Value is the value.
+	Value V ` + "`json:\"value,omitempty\"`" + `
+	// Meta is an anonymous struct.
+	Meta struct {
+		// Tags are tags.
+		Tags []string
+	}
+	Reader
+	hidden int
+}
+
+// Swap swaps the key and value.
+func (p *Pair[K, V]) Swap() *Pair[K, V] { return p }
+
+// Level is a custom type.
+type Level int
+
+// String renders the level.
+func (l Level) String() string { return "" }
+
+// Handler is a function type.
+type Handler func(level Level, pairs ...Pair[string, int]) error
+
+// Levels are the known levels.
+const (
+	// Debug level.
+	Debug Level = iota
+	// Info level.
+	Info
+)
+
+// Default is the default handler.
+var Default Handler
+
+// Sum sums the numbers.
+func Sum[T Number](values ...T) (total T) { return total }
+
+func helper() {}
+`
+
+// syntheticEmptySource is a synthetic empty package.
+const syntheticEmptySource = `// Package empty has no declarations.
+package empty
+`
+
+// templateLocation matches the location part of text/template errors e.g.
+// template: struct:12:3: executing "struct" at <.Struct.Foo>: ...
+var templateLocation = regexp.MustCompile(`template: ([\w.-]+):(\d+)(?::(\d+))?: `)
+
+// undefinedFunction matches the text/template parse error of an undefined function.
+var undefinedFunction = regexp.MustCompile(`function "([^"]+)" not defined`)
+
+// CheckTemplates validates the override templates, extensions and partials without parsing
+// any source code. It checks that the names are known template types, that each template
+// parses, and executes each template against a built-in synthetic model (generics, anonymous
+// structs, interfaces with type sets and an empty package). All issues are returned, sorted
+// by template and location, an empty result means that all templates are valid.
+func (p *Producer) CheckTemplates() []TemplateIssue {
+	var issues []TemplateIssue
+	add := func(issue TemplateIssue) {
+		if !containsIssue(issues, issue) {
+			issues = append(issues, issue)
+		}
+	}
+
+	known := map[string]bool{}
+	for _, tt := range TemplateTypes() {
+		known[tt.String()] = true
+	}
+
+	// texts are the template texts, by the name used in error messages
+	texts := map[string]string{}
+	for name, text := range p.partials {
+		texts[name] = text
+	}
+	for name, text := range p.overrides {
+		texts[name] = text
+	}
+	for name, extensions := range p.extensions {
+		if len(extensions) > 0 {
+			texts[name+".extension"] = extensions[len(extensions)-1]
+		}
+	}
+
+	// Parse each partial on its own, a broken partial would otherwise fail every template. The
+	// functions are checked when the partial is parsed into the templates.
+	partials := map[string]string{}
+	for name, text := range p.partials {
+		tree := parse.New(name)
+		tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+		if _, err := tree.Parse(text, "", "", map[string]*parse.Tree{}); err != nil {
+			add(templateIssue(name, texts, err))
+			continue
+		}
+
+		partials[name] = text
+	}
+
+	set := map[string]bool{}
+	for name := range p.overrides {
+		set[name] = true
+	}
+	for name := range p.extensions {
+		set[name] = true
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Parse each template in isolation, a broken template is not executed.
+	valid := &TemplateSources{
+		Overrides:  map[string]string{},
+		Extensions: map[string][]string{},
+		Partials:   partials,
+		Funcs:      p.templateFuncs,
+	}
+	for _, name := range names {
+		if !known[name] {
+			add(TemplateIssue{
+				Template: name,
+				Message:  "unknown template name, use --list-template to get the valid names",
+			})
+			continue
+		}
+
+		src := &TemplateSources{
+			Overrides:  map[string]string{},
+			Extensions: map[string][]string{name: p.extensions[name]},
+			Partials:   partials,
+			Funcs:      p.templateFuncs,
+		}
+		if text, ok := p.overrides[name]; ok {
+			src.Overrides[name] = text
+		}
+
+		if _, err := newTemplate(src); err != nil {
+			add(templateIssue(name, texts, err))
+			continue
+		}
+
+		for k, v := range src.Overrides {
+			valid.Overrides[k] = v
+		}
+		valid.Extensions[name] = src.Extensions[name]
+	}
+
+	t, err := newTemplate(valid)
+	if err != nil {
+		// e.g. a partial that uses an undefined function
+		add(templateIssue("partial", texts, err))
+		return sortIssues(issues)
+	}
+
+	mod, packages, err := syntheticModel()
+	if err != nil {
+		return append(issues, TemplateIssue{Template: "synthetic", Message: err.Error()})
+	}
+
+	for _, name := range names {
+		if _, ok := valid.Extensions[name]; !ok {
+			continue
+		}
+
+		for _, err := range p.executeSynthetic(t, TemplateType(name), mod, packages) {
+			add(templateIssue(name, texts, err))
+		}
+	}
+
+	return sortIssues(issues)
+}
+
+// sortIssues sorts the issues by template and location.
+func sortIssues(issues []TemplateIssue) []TemplateIssue {
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Template != b.Template {
+			return a.Template < b.Template
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return issues
+}
+
+// newTemplate creates the templates from the sources and returns the parse error instead of
+// panicking.
+func newTemplate(src *TemplateSources) (t *Template, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+				return
+			}
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return NewTemplateFromSources(src), nil
+}
+
+// syntheticModel parses the synthetic model into its module and packages.
+func syntheticModel() (*goparser.GoModule, []*goparser.GoPackage, error) {
+	mod, err := goparser.NewModuleFromBuff("/synthetic/go.mod", []byte(syntheticModule))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var packages []*goparser.GoPackage
+	for _, src := range []struct{ path, code string }{
+		{"/synthetic/synthetic/synthetic.go", syntheticSource},
+		{"/synthetic/empty/empty.go", syntheticEmptySource},
+	} {
+		file, err := goparser.ParseInlineFile(mod, src.path, src.code)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		packages = append(packages, &goparser.GoPackage{GoFile: *file, Files: []*goparser.GoFile{file}})
+	}
+
+	return mod, packages, nil
+}
+
+// executeSynthetic executes the template against all matching nodes of the synthetic model
+// and returns the errors.
+func (p *Producer) executeSynthetic(
+	t *Template,
+	tt TemplateType,
+	mod *goparser.GoModule,
+	packages []*goparser.GoPackage,
+) []error {
+	config := &TemplateContextConfig{
+		IncludeMethodCode: true,
+		Private:           true,
+		TypeLinks:         TypeLinksInternalExternal,
+		RenderOptions:     map[string]bool{},
+		Data:              p.templateData,
+	}
+	for _, option := range []string{"struct-json", "struct-yaml", "struct-xml", "struct-toml", "struct-jsonschema"} {
+		config.RenderOptions[option] = true
+	}
+
+	var errs []error
+	execute := func(ctx *TemplateContext, name string) {
+		tmpl := t.Templates[tt.String()].Template
+		if name != "" {
+			if tmpl = tmpl.Lookup(name); tmpl == nil {
+				return
+			}
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				errs = append(errs, fmt.Errorf("%v", r))
+			}
+		}()
+
+		if err := tmpl.Execute(io.Discard, ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, pkg := range packages {
+		ctx := t.NewContextWithConfig(&pkg.GoFile, pkg, config)
+		ctx.Workspace = &goparser.GoWorkspace{Modules: []*goparser.GoModule{mod}}
+		ctx.PackageRefs = &PackageReferences{
+			Internal: []PackageRef{{Name: "example.com/synthetic/empty", Anchor: "example-com-synthetic-empty"}},
+			External: []PackageRef{{Name: "github.com/acme/lib", Doc: "An external package."}},
+		}
+
+		switch tt {
+		case IndexTemplate:
+			q := ctx.Clone(true)
+			q.Index = ctx.DefaultIndexConfig("")
+			q.Workspace = ctx.Workspace
+			execute(q, "")
+
+			q.Module, q.ModuleFile, q.ModuleAnchor = mod, "synthetic.adoc", "module-1"
+			execute(q, "module")
+		case PackageRefTemplate:
+			q := ctx.Clone(true)
+			q.PackageFile, q.PackageAnchor = "packages/synthetic.adoc", "example-com-synthetic"
+			execute(q, "package-ref")
+		case PackageTemplate, PackageRefsTemplate, ImportTemplate, FunctionsTemplate,
+			InterfacesTemplate, StructsTemplate, CustomVarTypeDefsTemplate,
+			CustomFuncTypeDefsTemplate, VarDeclarationsTemplate, ConstDeclarationsTemplate,
+			TypesTemplate:
+			q := ctx.Clone(true)
+			q.PackageRefs = ctx.PackageRefs
+			execute(q, "")
+		case FunctionTemplate:
+			for _, fn := range pkg.StructMethods {
+				q := ctx.Clone(true)
+				q.Function = fn
+				execute(q, "")
+			}
+		case InterfaceTemplate:
+			for _, i := range pkg.Interfaces {
+				q := ctx.Clone(true)
+				q.Interface = i
+				execute(q, "")
+			}
+		case StructTemplate:
+			for _, s := range pkg.Structs {
+				q := ctx.Clone(true)
+				q.Struct = s
+				execute(q, "")
+			}
+		case ReceiversTemplate:
+			for _, s := range pkg.Structs {
+				q := ctx.Clone(true)
+				q.Receiver = pkg.FindMethodsByReceiver(s.Name)
+				execute(q, "")
+			}
+			for _, c := range pkg.CustomTypes {
+				q := ctx.Clone(true)
+				q.Receiver = pkg.FindMethodsByReceiver(c.Name)
+				execute(q, "")
+			}
+		case MembersTemplate:
+			for _, node := range typesOf(&pkg.GoFile) {
+				q := ctx.Clone(true)
+				q.Members = ctx.typeMembers(symbolName(node))
+				execute(q, "")
+			}
+		case TestReportTemplate:
+			report := &TestReport{
+				GoTestSuite: &goparser.GoTestSuite{Package: pkg},
+				Document:    "synthetic.adoc",
+			}
+			for i, fn := range pkg.StructMethods {
+				report.Tests = append(report.Tests, &goparser.GoTest{
+					Kind:      []goparser.GoTestKind{goparser.GoTestKindTest, goparser.GoTestKindBenchmark, goparser.GoTestKindFuzz}[i%3],
+					Function:  fn,
+					Cases:     []string{"case"},
+					Exercises: []string{"Pair", "Pair.Swap", "Debug"},
+				})
+			}
+
+			q := ctx.Clone(true)
+			q.TestReport = report
+			execute(q, "")
+		case DiagnosticsTemplate:
+			q := ctx.Clone(true)
+			q.Diagnostics = []Diagnostic{
+				{SeverityWarning, string(goparser.UnresolvedTypeCheck), "synthetic.go", 3, 4, "undefined: Missing"},
+				{SeverityInfo, RuleUnresolved, "", 0, 0, "a | b"},
+			}
+			execute(q, "")
+		case CustomVarTypeDefTemplate:
+			for _, c := range pkg.CustomTypes {
+				q := ctx.Clone(true)
+				q.TypeDefVar = c
+				execute(q, "")
+			}
+		case CustomFuncTypeDefTemplate:
+			for _, f := range pkg.CustomFuncs {
+				q := ctx.Clone(true)
+				q.TypeDefFunc = f
+				execute(q, "")
+			}
+		case VarDeclarationTemplate:
+			for _, a := range pkg.VarAssignments {
+				q := ctx.Clone(true)
+				q.VarAssignment = a
+				execute(q, "")
+			}
+		case ConstDeclarationTemplate:
+			for _, a := range pkg.ConstAssignments {
+				q := ctx.Clone(true)
+				q.ConstAssignment = a
+				execute(q, "")
+			}
+		}
+	}
+
+	return errs
+}
+
+// templateIssue converts a text/template error into an issue. Errors from nested templates,
+// e.g. a struct rendered by the structs template, are located in the innermost template.
+// When an undefined function is reported without column, the column is searched for in the
+// template text, texts are the template texts by name.
+func templateIssue(name string, texts map[string]string, err error) TemplateIssue {
+	msg := err.Error()
+	issue := TemplateIssue{Template: name, Message: msg}
+
+	matches := templateLocation.FindAllStringSubmatchIndex(msg, -1)
+	if len(matches) == 0 {
+		return issue
+	}
+
+	m := matches[len(matches)-1]
+	issue.Template = msg[m[2]:m[3]]
+	issue.Line, _ = strconv.Atoi(msg[m[4]:m[5]])
+	if m[6] != -1 {
+		// text/template columns are zero based byte offsets
+		column, _ := strconv.Atoi(msg[m[6]:m[7]])
+		issue.Column = column + 1
+	}
+	issue.Message = msg[m[1]:]
+
+	if text, ok := texts[issue.Template]; ok && issue.Column == 0 {
+		if fn := undefinedFunction.FindStringSubmatch(issue.Message); fn != nil {
+			lines := strings.Split(text, "\n")
+			if issue.Line <= len(lines) {
+				if idx := strings.Index(lines[issue.Line-1], fn[1]); idx != -1 {
+					issue.Column = idx + 1
+				}
+			}
+		}
+	}
+
+	return issue
+}
+
+// containsIssue returns true if the issue is already in issues.
+func containsIssue(issues []TemplateIssue, issue TemplateIssue) bool {
+	for _, i := range issues {
+		if i == issue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+package asciidoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTemplatesDefaultsAreValid(t *testing.T) {
+	assert.Empty(t, overrideAllDefaults(t, NewProducer()).CheckTemplates())
+}
+
+func TestCheckTemplatesReportsIssues(t *testing.T) {
+	p := overrideAllDefaults(t, NewProducer())
+
+	p.Override("strcut", "=== {{.Struct.Name}}")
+	p.Override(InterfaceTemplate.String(), "=== {{.Interface.Name}}\n{{ shout .Interface.Name }}")
+	p.Override(StructTemplate.String(), "=== {{.Struct.Name}}\n\n  {{.Struct.Title}}")
+	p.Override(ConstDeclarationTemplate.String(), "{{.ConstAssignment.Name}}\n{{.ConstAssignment.Nmae}}")
+
+	issues := p.CheckTemplates()
+	require.Len(t, issues, 4)
+
+	assert.Equal(t, TemplateIssue{
+		Template: ConstDeclarationTemplate.String(),
+		Line:     2,
+		Column:   19,
+		Message:  `executing "const" at <.ConstAssignment.Nmae>: can't evaluate field Nmae in type *goparser.GoAssignment`,
+	}, issues[0])
+	assert.Equal(t, TemplateIssue{
+		Template: InterfaceTemplate.String(),
+		Line:     2,
+		Column:   4,
+		Message:  `function "shout" not defined`,
+	}, issues[1])
+	assert.Equal(t, "strcut", issues[2].Template)
+	assert.Contains(t, issues[2].Message, "unknown template name")
+	assert.Equal(t, TemplateIssue{
+		Template: StructTemplate.String(),
+		Line:     3,
+		Column:   12,
+		Message:  `executing "struct" at <.Struct.Title>: can't evaluate field Title in type *goparser.GoStruct`,
+	}, issues[3])
+
+	assert.Equal(t, `interface:2:4: function "shout" not defined`, issues[1].String())
+}
@@ -0,0 +1,188 @@
+package asciidoc
+
+import (
+	"go/scanner"
+	"go/token"
+	"html"
+	htmltemplate "html/template"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// CodeDoc is the implementation of a function or method to render.
+type CodeDoc struct {
+	// Start is the line, starting from 1, of the first line of the code in its source file.
+	Start int
+	// Source is the code as written in the source file.
+	Source string
+	// Lines are the lines of the code as HTML where the identifiers of the package, and the
+	// imported packages, are linked as done in signatures.
+	Lines []CodeLine
+}
+
+// CodeLine is a single line of a CodeDoc.
+type CodeLine struct {
+	// Number is the line number in the source file.
+	Number int
+	// Content is the HTML of the line, without a trailing newline.
+	Content htmltemplate.HTML
+}
+
+// includeCode returns true if the implementation of the function, or method, shall be rendered.
+//
+// A //goasciidoc:code directive on the function wins over one outside of the declarations,
+// e.g. in the package doc comment, of a file in the package, which wins over the
+// IncludeMethodCode configuration.
+func (t *TemplateContext) includeCode(fn *goparser.GoStructMethod) bool {
+	if fn == nil {
+		return false
+	}
+
+	if enabled, ok := fn.Directives.Code(); ok {
+		return enabled
+	}
+
+	files := []*goparser.GoFile{fn.File}
+	if t.Package != nil && len(t.Package.Files) > 0 {
+		files = t.Package.Files
+	}
+
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+
+		if enabled, ok := file.Directives.Code(); ok {
+			return enabled
+		}
+	}
+
+	return t.Config != nil && t.Config.IncludeMethodCode
+}
+
+// implementation returns the implementation of the function, or method, when it shall be
+// rendered (see includeCode) otherwise nil.
+func (t *TemplateContext) implementation(fn *goparser.GoStructMethod) *CodeDoc {
+	if !t.includeCode(fn) || strings.TrimSpace(fn.FullDecl) == "" {
+		return nil
+	}
+
+	start := fn.Line
+	if start < 1 {
+		start = 1
+	}
+
+	code := &CodeDoc{Start: start, Source: fn.FullDecl}
+	for i, line := range strings.Split(t.linkCode(fn), "\n") {
+		code.Lines = append(code.Lines, CodeLine{
+			Number:  start + i,
+			Content: htmltemplate.HTML(line),
+		})
+	}
+
+	return code
+}
+
+// linkCode returns the source of the function as HTML where the identifiers declared in the
+// package, and the qualified identifiers of the imported packages, are linked.
+func (t *TemplateContext) linkCode(fn *goparser.GoStructMethod) string {
+	src := []byte(fn.FullDecl)
+	locals := t.linkableNames(fn.File)
+	scope := t.typeParamSet(fn.TypeParams, t.receiverOwnerTypeParams(fn))
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	s.Init(file, src, nil /* errors are rendered as is */, scanner.ScanComments)
+
+	type item struct {
+		offset int
+		tok    token.Token
+		lit    string
+	}
+
+	var items []item
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok == token.SEMICOLON && lit == "\n" {
+			// Automatically inserted, it is not part of the source.
+			continue
+		}
+
+		items = append(items, item{offset: file.Offset(pos), tok: tok, lit: lit})
+	}
+
+	var (
+		b    strings.Builder
+		last int
+	)
+
+	for i := 0; i < len(items); i++ {
+		it := items[i]
+		if it.tok != token.IDENT {
+			continue
+		}
+
+		name, end := it.lit, it.offset+len(it.lit)
+		qualified := i+2 < len(items) &&
+			items[i+1].tok == token.PERIOD && items[i+1].offset == end &&
+			items[i+2].tok == token.IDENT && items[i+2].offset == end+1 &&
+			t.importPathForAlias(name, fn.File) != ""
+
+		selected := i > 0 && items[i-1].tok == token.PERIOD
+
+		var linked string
+		switch {
+		case qualified:
+			name = name + "." + items[i+2].lit
+			end = items[i+2].offset + len(items[i+2].lit)
+			linked = t.htmlIdentifier(name, fn.File, scope)
+			i += 2
+		case !selected:
+			if _, ok := locals[name]; ok {
+				linked = t.htmlIdentifier(name, fn.File, scope)
+			}
+		}
+
+		if linked == "" {
+			continue
+		}
+
+		b.WriteString(html.EscapeString(string(src[last:it.offset])))
+		b.WriteString(linked)
+		last = end
+	}
+
+	b.WriteString(html.EscapeString(string(src[last:])))
+	return b.String()
+}
+
+// linkableNames returns the names of the rendered types and functions, i.e. the symbols that
+// have an anchor, declared in the package of the file.
+func (t *TemplateContext) linkableNames(file *goparser.GoFile) map[string]struct{} {
+	decls := file
+	if t.Package != nil {
+		decls = &t.Package.GoFile
+	}
+
+	names := map[string]struct{}{}
+	if decls == nil {
+		return names
+	}
+
+	private := t.Config != nil && t.Config.Private
+	for _, node := range append(typesOf(decls), toList(functionsOf(decls.StructMethods))...) {
+		if ignored(node) || !(private || symbolExported(node)) {
+			continue
+		}
+
+		names[symbolName(node)] = struct{}{}
+	}
+
+	return names
+}
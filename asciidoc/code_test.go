@@ -0,0 +1,105 @@
+package asciidoc
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncludeCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgDoc  string
+		fnDoc   string
+		global  bool
+		include bool
+	}{
+		{"default", "", "", false, false},
+		{"global", "", "", true, true},
+		{"package", "//goasciidoc:code\n", "", false, true},
+		{"package off", "//goasciidoc:code off\n", "", true, false},
+		{"symbol", "", "//goasciidoc:code\n", false, true},
+		{"symbol off", "//goasciidoc:code\n", "//goasciidoc:code off\n", true, false},
+		{"invalid", "", "//goasciidoc:code maybe\n", false, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			file, err := goparser.ParseInlineFile(nil, "shop.go",
+				"// Package shop sells.\n"+tc.pkgDoc+"package shop\n\n// Buy buys.\n"+tc.fnDoc+"func Buy() {}\n")
+			require.NoError(t, err)
+
+			pkg := &goparser.GoPackage{GoFile: *file, Files: []*goparser.GoFile{file}}
+			ctx := NewTemplateWithOverrides(nil).NewContextWithConfig(file, pkg, &TemplateContextConfig{
+				IncludeMethodCode: tc.global,
+			})
+
+			assert.Equal(t, tc.include, ctx.includeCode(file.StructMethods[0]))
+		})
+	}
+}
+
+func TestImplementationRendering(t *testing.T) {
+	modDir := t.TempDir()
+	writeFiles(t, modDir, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": `package shop
+
+import "strings"
+
+// Item is an item.
+type Item struct {
+	Name string
+}
+
+// NewItem creates an item.
+func NewItem(name string) *Item {
+	return &Item{Name: strings.TrimSpace(name)}
+}
+
+// Rename renames the item.
+//
+//goasciidoc:code
+func (i *Item) Rename(name string) *Item {
+	return NewItem(name + " <" + i.Name + ">")
+}
+
+// Skipped is not rendered with code.
+//
+//goasciidoc:code off
+func Skipped() {}
+`,
+	})
+
+	render := func(style string) string {
+		var buff bytes.Buffer
+		p := NewProducer().
+			Writer(&buff).
+			Module(modDir).
+			Include(filepath.Join(modDir, "shop")).
+			TypeLinks(TypeLinksInternalExternal).
+			SignatureStyle(style).
+			IncludeMethodCode().
+			NoIndex()
+
+		overrideAllDefaults(t, p)
+		p.Generate()
+		return buff.String()
+	}
+
+	doc := render("goasciidoc")
+	assert.Equal(t, 2, bytes.Count([]byte(doc), []byte(".Implementation")))
+	assert.Contains(t, doc, "[%collapsible]\n.Implementation\n====\n++++\n")
+	assert.Contains(t, doc, `<span class="linenum">11</span>  func <a href="#example-com-shop-shop-NewItem">NewItem</a>(name string) *<a href="#example-com-shop-shop-Item">Item</a> {`)
+	assert.Contains(t, doc, `<span class="linenum">12</span>      return &amp;<a href="#example-com-shop-shop-Item">Item</a>{Name: strings.<a href="https://pkg.go.dev/strings@go1.21#TrimSpace">TrimSpace</a>(name)}`)
+	assert.Contains(t, doc, `<span class="linenum">19</span>      return <a href="#example-com-shop-shop-NewItem">NewItem</a>(name + &#34; &lt;&#34; + i.Name + &#34;&gt;&#34;)`)
+
+	doc = render("source")
+	assert.Contains(t, doc, "[%collapsible]\n.Implementation\n====\n[source, go, linenums, start=18]\n----\nfunc (i *Item) Rename(name string) *Item {\n")
+	assert.Contains(t, doc, "[source, go, linenums, start=11]\n----\nfunc NewItem(name string) *Item {\n")
+	assert.NotContains(t, doc, "func Skipped() {}")
+}
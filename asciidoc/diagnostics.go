@@ -0,0 +1,285 @@
+package asciidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity string
+
+const (
+	// SeverityError is a problem that makes the documentation wrong.
+	SeverityError Severity = "error"
+	// SeverityWarning is a problem that degrades the documentation e.g. a missing snippet.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is a notice that does not affect the documentation.
+	SeverityInfo Severity = "info"
+	// SeverityOff disables a lint rule.
+	SeverityOff Severity = "off"
+)
+
+// Diagnostic is a problem found while generating the documentation.
+type Diagnostic struct {
+	// Severity is the severity of the problem.
+	Severity Severity `json:"severity"`
+	// Rule identifies the kind of problem e.g. snippet.
+	Rule string `json:"rule"`
+	// File is the path of the file the problem is in, empty when unknown.
+	File string `json:"file,omitempty"`
+	// Line is the line, starting from 1, in the file or zero when unknown.
+	Line int `json:"line,omitempty"`
+	// Column is the column, starting from 1, in the file or zero when unknown.
+	Column int `json:"column,omitempty"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// Location returns the file:line:column, file:line or file of the diagnostic, empty when the
+// file is unknown.
+func (d Diagnostic) Location() string {
+	switch {
+	case d.File == "":
+		return ""
+	case d.Line > 0 && d.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+	case d.Line > 0:
+		return fmt.Sprintf("%s:%d", d.File, d.Line)
+	}
+
+	return d.File
+}
+
+// String renders the diagnostic as file:line:column: severity: message [rule].
+func (d Diagnostic) String() string {
+	location := d.Location()
+	if location == "" {
+		return fmt.Sprintf("%s: %s [%s]", d.Severity, d.Message, d.Rule)
+	}
+
+	return fmt.Sprintf("%s: %s: %s [%s]", location, d.Severity, d.Message, d.Rule)
+}
+
+// Diagnostics returns the problems found by the last Generate, in the order they were found.
+func (p *Producer) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// report records the diagnostic.
+func (p *Producer) report(d Diagnostic) {
+	p.debugf("Diagnostic: %s", d.String())
+	p.diagnostics = append(p.diagnostics, d)
+}
+
+// RelativeDiagnostics returns a copy of the diagnostics where the files below dir are relative
+// dir.
+func RelativeDiagnostics(dir string, diagnostics []Diagnostic) []Diagnostic {
+	if diagnostics == nil {
+		return nil
+	}
+
+	relative := make([]Diagnostic, len(diagnostics))
+	for i, d := range diagnostics {
+		if d.File == "" {
+			relative[i] = d
+			continue
+		}
+
+		if rel, err := filepath.Rel(dir, d.File); err == nil && !strings.HasPrefix(rel, "..") {
+			d.File = rel
+		}
+		relative[i] = d
+	}
+
+	return relative
+}
+
+// SummarizeDiagnostics returns a one line summary, with the number of problems per rule, e.g.
+// "3 problem(s): type-check 2, example 1". It is empty when there are no diagnostics.
+func SummarizeDiagnostics(diagnostics []Diagnostic) string {
+	if len(diagnostics) == 0 {
+		return ""
+	}
+
+	var rules []string
+	counts := map[string]int{}
+	for _, d := range diagnostics {
+		if counts[d.Rule] == 0 {
+			rules = append(rules, d.Rule)
+		}
+		counts[d.Rule]++
+	}
+
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = fmt.Sprintf("%s %d", rule, counts[rule])
+	}
+
+	return fmt.Sprintf("%d problem(s): %s", len(diagnostics), strings.Join(parts, ", "))
+}
+
+// DiagnosticFormat is the format diagnostics are written in, see WriteDiagnostics.
+type DiagnosticFormat string
+
+const (
+	// DiagnosticsText writes a diagnostic per line, see Diagnostic.String.
+	DiagnosticsText DiagnosticFormat = "text"
+	// DiagnosticsJSON writes the diagnostics as a JSON array.
+	DiagnosticsJSON DiagnosticFormat = "json"
+	// DiagnosticsSARIF writes the diagnostics as a SARIF 2.1.0 log e.g. for code scanning.
+	DiagnosticsSARIF DiagnosticFormat = "sarif"
+)
+
+// WriteDiagnostics writes the diagnostics in the format.
+func WriteDiagnostics(w io.Writer, format DiagnosticFormat, diagnostics []Diagnostic) error {
+	switch format {
+	case DiagnosticsText, "":
+		for _, d := range diagnostics {
+			if _, err := fmt.Fprintln(w, d.String()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case DiagnosticsJSON:
+		if diagnostics == nil {
+			diagnostics = []Diagnostic{}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diagnostics)
+	case DiagnosticsSARIF:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(sarifLogOf(diagnostics))
+	}
+
+	return fmt.Errorf("unknown diagnostics format %q (valid: text, json, sarif)", format)
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription *sarifText   `json:"shortDescription,omitempty"`
+	DefaultConfig    *sarifConfig `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// sarifLevel returns the SARIF level of the severity.
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityInfo:
+		return "note"
+	}
+
+	return "warning"
+}
+
+// sarifLogOf returns the SARIF log of the diagnostics. The rules are the lint rules, see
+// LintRules, and the rules of the diagnostics in the order they first appear.
+func sarifLogOf(diagnostics []Diagnostic) sarifLog {
+	driver := sarifDriver{
+		Name:           "goasciidoc",
+		InformationURI: "https://github.com/mariotoffia/goasciidoc",
+		Rules:          []sarifRule{},
+	}
+
+	known := map[string]bool{}
+	for _, rule := range LintRules() {
+		known[rule.Name] = true
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               rule.Name,
+			ShortDescription: &sarifText{Text: rule.Description},
+			DefaultConfig:    &sarifConfig{Level: sarifLevel(rule.Severity)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, d := range diagnostics {
+		if !known[d.Rule] {
+			known[d.Rule] = true
+			driver.Rules = append(driver.Rules, sarifRule{ID: d.Rule})
+		}
+
+		result := sarifResult{
+			RuleID:  d.Rule,
+			Level:   sarifLevel(d.Severity),
+			Message: sarifText{Text: d.Message},
+		}
+
+		if d.File != "" {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: filepath.ToSlash(d.File)}}
+			if d.Line > 0 {
+				location.Region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+			}
+
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+
+		results = append(results, result)
+	}
+
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
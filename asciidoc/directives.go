@@ -0,0 +1,134 @@
+package asciidoc
+
+import (
+	"sort"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// SymbolGroup is a set of symbols rendered under a common //goasciidoc:group heading.
+type SymbolGroup struct {
+	// Name is the group name, empty for the symbols that are not part of a group.
+	Name string
+	// Symbols are the visible symbols of the group in rendering order.
+	Symbols []interface{}
+}
+
+// symbolDirectives returns the directives of a struct, interface, custom type, function type,
+// function, method, field, variable or constant.
+func symbolDirectives(node interface{}) goparser.GoDirectives {
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		return n.Directives
+	case *goparser.GoInterface:
+		return n.Directives
+	case *goparser.GoCustomType:
+		return n.Directives
+	case *goparser.GoMethod:
+		return n.Directives
+	case *goparser.GoStructMethod:
+		return n.Directives
+	case *goparser.GoField:
+		return n.Directives
+	case *goparser.GoAssignment:
+		return n.Directives
+	}
+
+	return nil
+}
+
+// symbolExported returns true if the symbol is exported, unknown nodes are treated as exported.
+func symbolExported(node interface{}) bool {
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		return n.Exported
+	case *goparser.GoInterface:
+		return n.Exported
+	case *goparser.GoCustomType:
+		return n.Exported
+	case *goparser.GoMethod:
+		return n.Exported
+	case *goparser.GoStructMethod:
+		return n.Exported
+	case *goparser.GoField:
+		return n.Exported
+	case *goparser.GoAssignment:
+		return n.Exported
+	}
+
+	return true
+}
+
+// ignored returns true if the symbol has a //goasciidoc:ignore directive.
+func ignored(node interface{}) bool {
+	return symbolDirectives(node).Ignored()
+}
+
+// hasDocumentedSymbols returns true if any of the symbols in the list is not ignored by a
+// //goasciidoc:ignore directive.
+func hasDocumentedSymbols(list interface{}) bool {
+	for _, node := range toList(list) {
+		if !ignored(node) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groups returns the visible symbols of the list, i.e. exported (or private is enabled) and
+// not ignored, grouped by their //goasciidoc:group directive.
+//
+// Symbols with a //goasciidoc:order directive come first, in ascending order, followed by
+// the rest in the configured sort order. The symbols without a group are always the first group, the
+// named groups follow in the order their first symbol appears.
+func (t *TemplateContext) groups(list interface{}) []SymbolGroup {
+	private := t.Config != nil && t.Config.Private
+
+	var symbols []interface{}
+	for _, node := range toList(list) {
+		if ignored(node) || !(private || symbolExported(node)) {
+			continue
+		}
+
+		symbols = append(symbols, node)
+	}
+
+	if t.Config != nil && t.File != nil {
+		sortSymbols(symbols, t.Config.SortOrder, localTypes(t.File))
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool {
+		oi, iok := symbolDirectives(symbols[i]).Order()
+		oj, jok := symbolDirectives(symbols[j]).Order()
+		if iok != jok {
+			return iok
+		}
+
+		return oi < oj
+	})
+
+	var (
+		groups    = []SymbolGroup{{}}
+		positions = map[string]int{"": 0}
+	)
+
+	for _, node := range symbols {
+		name := symbolDirectives(node).Group()
+
+		idx, ok := positions[name]
+		if !ok {
+			idx = len(groups)
+			positions[name] = idx
+			groups = append(groups, SymbolGroup{Name: name})
+		}
+
+		groups[idx].Symbols = append(groups[idx].Symbols, node)
+	}
+
+	if len(groups[0].Symbols) == 0 {
+		return groups[1:]
+	}
+
+	return groups
+}
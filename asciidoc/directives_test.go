@@ -0,0 +1,163 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectivesRendering(t *testing.T) {
+	modDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/sample\n\ngo 1.21\n",
+		"sample/kind.go": `package sample
+
+import "embed"
+
+//go:generate stringer -type=Kind
+
+// Kind is the kind of server.
+type Kind int
+
+// Templates are the page templates.
+//
+//go:embed templates/*.html
+var Templates embed.FS
+`,
+		"sample/templates/index.html": "<html></html>\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(modDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(filepath.Join(modDir, "sample")).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+
+	p.Generate()
+
+	doc := buff.String()
+	assert.Contains(t, doc, "=== Code Generation")
+	assert.Contains(t, doc, "|`kind.go` |`+stringer -type=Kind+`")
+	assert.Contains(t, doc, "*Embedded files:* `+templates/*.html+`")
+	assert.Contains(t, doc, "Templates are the page templates.")
+	assert.NotContains(t, doc, "go:embed")
+}
+
+func TestGoasciidocDirectivesRendering(t *testing.T) {
+	modDir := t.TempDir()
+	writeFiles(t, modDir, map[string]string{
+		"go.mod": "module example.com/auth\n\ngo 1.21\n",
+		"auth/auth.go": `package auth
+
+// Logout ends the session.
+//
+//goasciidoc:group "Authentication"
+func Logout() {}
+
+// Login starts the session.
+//
+//goasciidoc:group "Authentication"
+//goasciidoc:order 1
+func Login() {}
+
+// Version returns the version.
+func Version() string { return "" }
+
+// Init initializes the package.
+//
+//goasciidoc:order 0
+func Init() {}
+
+// Helper must stay exported for tests.
+//
+//goasciidoc:ignore
+func Helper() {}
+
+// Internal is exported for the code generator only.
+//
+//goasciidoc:ignore
+type Internal struct{}
+
+// Session is a session.
+type Session struct {
+	// ID is the session id.
+	ID string
+	// Token is hidden.
+	//
+	//goasciidoc:ignore
+	Token string
+}
+
+// Touch refreshes the session.
+func (s *Session) Touch() {}
+
+// Reset is hidden.
+//
+//goasciidoc:ignore
+func (s *Session) Reset() {}
+`,
+	})
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(filepath.Join(modDir, "auth")).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+
+	p.Generate()
+
+	doc := buff.String()
+	assert.NotContains(t, doc, "Helper")
+	assert.NotContains(t, doc, "Internal")
+	assert.NotContains(t, doc, "Token")
+	assert.NotContains(t, doc, "Reset")
+	assert.NotContains(t, doc, "goasciidoc:")
+	assert.Contains(t, doc, "===== Touch")
+
+	functions := doc[strings.Index(doc, "== Functions"):]
+	order := []string{
+		"=== Init",
+		"=== Version",
+		"=== Authentication\n\n:leveloffset: +1\n",
+		"=== Login",
+		"=== Logout",
+		":leveloffset: -1\n",
+	}
+
+	last := -1
+	for _, heading := range order {
+		idx := strings.Index(functions, heading)
+		require.Greater(t, idx, last, "expected %q after the previous heading", heading)
+		last = idx
+	}
+}
+
+func TestGroupsAllIgnored(t *testing.T) {
+	file, err := goparser.ParseInlineFile(nil, "app.go", `package app
+
+//goasciidoc:ignore
+type Hidden struct{}
+`)
+	require.NoError(t, err)
+
+	tc := NewTemplateWithOverrides(nil).NewContext(file)
+	assert.Empty(t, tc.groups(file.Structs))
+	assert.False(t, hasDocumentedSymbols(file.Structs))
+}
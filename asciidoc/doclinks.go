@@ -301,7 +301,7 @@ func (t *TemplateContext) generateDocLink(ref *DocReference) string {
 	if ref.Kind == RefPackage {
 		if ref.IsExternal {
 			if t.Config.TypeLinks == TypeLinksInternalExternal {
-				return fmt.Sprintf("link:https://pkg.go.dev/%s[%s]", ref.PackagePath, linkText)
+				return t.generateExternalLink(ref, linkText)
 			}
 			return "`" + linkText + "`"
 		}
@@ -329,31 +329,36 @@ func (t *TemplateContext) generateDocLink(ref *DocReference) string {
 		return "`" + linkText + "`"
 	}
 
-	// Internal reference
+	// Internal reference, symbols removed by the symbol filter are rendered as plain text
+	filtered := t.isFiltered(ref.PackagePath, ref.Identifier)
+	if ref.Receiver != "" {
+		filtered = t.isFiltered(ref.PackagePath, ref.Receiver) ||
+			t.isFiltered(ref.PackagePath, ref.Receiver+"."+ref.Identifier)
+	}
+	if filtered {
+		return "`" + linkText + "`"
+	}
+
 	return t.generateInternalLink(ref, anchor, linkText)
 }
 
-// generateExternalLink creates a pkg.go.dev link
+// generateExternalLink creates a link using the external link rules (default pkg.go.dev)
 func (t *TemplateContext) generateExternalLink(ref *DocReference, linkText string) string {
 	switch ref.Kind {
 	case RefPackage:
-		return fmt.Sprintf("link:https://pkg.go.dev/%s[%s]", ref.PackagePath, linkText)
+		return fmt.Sprintf("link:%s[%s]", t.externalURL(ref.PackagePath, ""), linkText)
 
 	case RefMethod:
 		if ref.Receiver != "" {
-			return fmt.Sprintf("link:https://pkg.go.dev/%s#%s.%s[%s]",
-				ref.PackagePath, ref.Receiver, ref.Identifier, linkText)
+			return fmt.Sprintf("link:%s[%s]",
+				t.externalURL(ref.PackagePath, ref.Receiver+"."+ref.Identifier), linkText)
 		}
-		return fmt.Sprintf("link:https://pkg.go.dev/%s#%s[%s]",
-			ref.PackagePath, ref.Identifier, linkText)
-
-	case RefType, RefFunction, RefConstant, RefVariable:
-		return fmt.Sprintf("link:https://pkg.go.dev/%s#%s[%s]",
-			ref.PackagePath, ref.Identifier, linkText)
+		return fmt.Sprintf("link:%s[%s]",
+			t.externalURL(ref.PackagePath, ref.Identifier), linkText)
 
 	default:
-		return fmt.Sprintf("link:https://pkg.go.dev/%s#%s[%s]",
-			ref.PackagePath, ref.Identifier, linkText)
+		return fmt.Sprintf("link:%s[%s]",
+			t.externalURL(ref.PackagePath, ref.Identifier), linkText)
 	}
 }
 
@@ -0,0 +1,45 @@
+package asciidoc
+
+import (
+	"encoding/json"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// applyExamples installs the example registry, with the values registered using
+// ExampleValue and the examples from the index configuration JSON, onto the
+// module(s) to be rendered.
+func (p *Producer) applyExamples() {
+	registry := p.examples
+
+	if p.indexconfig != "" {
+		var ic IndexConfig
+		if err := json.Unmarshal([]byte(p.indexconfig), &ic); err != nil {
+			p.debugf("Examples: ignoring index configuration: %v", err)
+		} else if len(ic.Examples) > 0 {
+			if registry == nil {
+				registry = goparser.NewExampleRegistry()
+			}
+
+			for name, value := range ic.Examples {
+				registry.Register(name, value)
+			}
+		}
+	}
+
+	if registry == nil {
+		return
+	}
+
+	p.examples = registry
+
+	if p.parseconfig.Module != nil {
+		p.parseconfig.Module.Examples = registry
+	}
+
+	if p.parseconfig.Workspace != nil {
+		for _, module := range p.parseconfig.Workspace.Modules {
+			module.Examples = registry
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleValuesFromConfigAndProducer(t *testing.T) {
+	modDir := t.TempDir()
+	pkgDir := filepath.Join(modDir, "sample")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+
+	goMod := "module example.com/sample\n\ngo 1.21\n"
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "go.mod"), []byte(goMod), 0o644))
+
+	src := `package sample
+
+import "time"
+
+// Money is an amount in cents.
+type Money struct {
+	Cents int64
+}
+
+// Invoice is sent to customers.
+type Invoice struct {
+	Total Money         ` + "`json:\"total\"`" + `
+	Due   time.Duration ` + "`json:\"due\"`" + `
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "invoice.go"), []byte(src), 0o644))
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(pkgDir).
+		NoIndex().
+		IndexConfig(`{"examples": {"example.com/sample/sample.Money": "12.34"}}`).
+		ExampleValue("time.Duration", goparser.ExampleValue{Value: "30s"}).
+		RenderOptions(map[string]bool{"struct-json": true})
+
+	overrideAllDefaults(t, p)
+
+	p.Generate()
+
+	doc := buff.String()
+	assert.Contains(t, doc, `"total": "12.34"`)
+	assert.Contains(t, doc, `"due": "30s"`)
+}
@@ -0,0 +1,219 @@
+package asciidoc
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// DefaultExternalLinkTemplate is the URL template used for external import paths that
+// no ExternalLinkRule matches.
+const DefaultExternalLinkTemplate = "https://pkg.go.dev/{pinned}#{symbol}"
+
+// ExternalLinkRule maps external import paths onto a URL template.
+//
+// The Pattern is matched against the import path:
+//
+// .Patterns
+// |===
+// |Pattern |Matches
+//
+// |corp.example.com/*
+// |corp.example.com and all packages below it
+//
+// |corp.example.com/lib*
+// |All import paths starting with corp.example.com/lib
+//
+// |std
+// |The standard library, i.e. import paths without a dot in the first element
+//
+// |*
+// |All import paths
+//
+// |corp.example.com/lib
+// |Only the exact import path
+//
+// |===
+//
+// The Template may use the placeholders:
+//
+// .Placeholders
+// |===
+// |Placeholder |Value
+//
+// |{path}
+// |The import path
+//
+// |{package}
+// |The last element of the import path
+//
+// |{symbol}
+// |The symbol e.g. Reader or Reader.Read, empty when linking a package
+//
+// |{module}
+// |The module that provides the import path, e.g. std for the standard library
+//
+// |{version}
+// |The module version from go.mod, e.g. v1.8.4, or the go directive e.g. go1.22 for the standard library
+//
+// |{pinned}
+// |The import path pinned to the module version, e.g. github.com/acme/lib@v1.2.0/sub or net/http@go1.22
+//
+// |===
+//
+// The module, version and pinned import path are resolved using the require and replace
+// directives of go.mod. When the version is unknown, {module} and {version} are empty and
+// {pinned} is the import path. When linking a package, a _#{symbol}_ fragment is removed.
+type ExternalLinkRule struct {
+	Pattern  string
+	Template string
+}
+
+// ParseExternalLinkRule parses a rule written as _pattern -> template_ or _pattern=template_,
+// e.g. corp.example.com/* -> https://godoc.corp/{path}#{symbol}.
+func ParseExternalLinkRule(rule string) (ExternalLinkRule, error) {
+	pattern, template, ok := strings.Cut(rule, "->")
+	if !ok {
+		pattern, template, ok = strings.Cut(rule, "=")
+	}
+
+	pattern = strings.TrimSpace(pattern)
+	template = strings.TrimSpace(template)
+	if !ok || pattern == "" || template == "" {
+		return ExternalLinkRule{}, fmt.Errorf(
+			"invalid external link rule %q (expected pattern -> url template)", rule,
+		)
+	}
+
+	return ExternalLinkRule{Pattern: pattern, Template: template}, nil
+}
+
+// Matches returns true if the import path matches the pattern of the rule.
+func (r ExternalLinkRule) Matches(importPath string) bool {
+	switch {
+	case r.Pattern == "*":
+		return true
+	case r.Pattern == "std":
+		return isStdlibImport(importPath)
+	case strings.HasSuffix(r.Pattern, "/*"):
+		prefix := strings.TrimSuffix(r.Pattern, "/*")
+		return importPath == prefix || strings.HasPrefix(importPath, prefix+"/")
+	case strings.HasSuffix(r.Pattern, "*"):
+		return strings.HasPrefix(importPath, strings.TrimSuffix(r.Pattern, "*"))
+	}
+
+	return importPath == r.Pattern
+}
+
+// ExternalLinkResolver resolves the URL of external packages and symbols. Symbols in loaded
+// symbol manifests link to the documentation of those projects, all other symbols are
+// resolved by a chain of rules. The first matching rule is used, if none match,
+// DefaultExternalLinkTemplate is used.
+type ExternalLinkResolver struct {
+	rules []ExternalLinkRule
+	// symbols maps fully qualified symbols, from symbol manifests, onto their URL.
+	symbols map[string]string
+}
+
+// NewExternalLinkResolver creates a resolver that tries the rules in order.
+func NewExternalLinkResolver(rules ...ExternalLinkRule) *ExternalLinkResolver {
+	return &ExternalLinkResolver{rules: rules}
+}
+
+// Rules returns the rules of the chain, in order.
+func (r *ExternalLinkResolver) Rules() []ExternalLinkRule {
+	if r == nil {
+		return nil
+	}
+
+	return r.rules
+}
+
+// URL returns the URL of a symbol, e.g. Reader.Read, in the external import path. If symbol
+// is empty, the URL of the package is returned. The version is resolved using the go.mod of
+// mod, a nil mod renders links without version. It is safe to call on a nil resolver.
+func (r *ExternalLinkResolver) URL(mod *goparser.GoModule, importPath, symbol string) string {
+	importPath = strings.TrimPrefix(importPath, "/")
+
+	if r != nil && symbol != "" {
+		if link, ok := r.symbols[importPath+"."+symbol]; ok {
+			return link
+		}
+	}
+
+	pinned, module, version := importPath, "", ""
+	if mv, ok := mod.ResolveVersion(importPath); ok {
+		module, version = mv.Module, mv.Version
+		if mv.Module == "std" {
+			pinned = mv.Package + "@" + mv.Version
+		} else {
+			pinned = mv.Module + "@" + mv.Version + strings.TrimPrefix(mv.Package, mv.Module)
+		}
+	}
+
+	template := DefaultExternalLinkTemplate
+	for _, rule := range r.Rules() {
+		if rule.Matches(importPath) {
+			template = rule.Template
+			break
+		}
+	}
+
+	if symbol == "" {
+		template = strings.ReplaceAll(template, "#{symbol}", "")
+	}
+
+	return strings.NewReplacer(
+		"{path}", importPath,
+		"{package}", path.Base(importPath),
+		"{symbol}", symbol,
+		"{module}", module,
+		"{version}", version,
+		"{pinned}", pinned,
+	).Replace(template)
+}
+
+// withManifests returns a resolver with the same rules that resolves the symbols in the
+// manifests first. Relative document URLs are made relative to dir.
+func (r *ExternalLinkResolver) withManifests(manifests []*SymbolManifest, dir string) *ExternalLinkResolver {
+	resolver := NewExternalLinkResolver(r.Rules()...)
+	if len(manifests) == 0 {
+		return resolver
+	}
+
+	resolver.symbols = map[string]string{}
+	for _, m := range manifests {
+		for name, link := range m.resolvedSymbols(dir) {
+			if _, ok := resolver.symbols[name]; !ok {
+				resolver.symbols[name] = link
+			}
+		}
+	}
+
+	return resolver
+}
+
+// isStdlibImport returns true if the import path is in the standard library, i.e. the
+// first path element has no dot.
+func isStdlibImport(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return first != "" && !strings.Contains(first, ".")
+}
+
+// externalURL resolves the URL of an external symbol (or package if symbol is empty) using
+// the configured external link rules, pinned to the version required by the current module.
+func (t *TemplateContext) externalURL(importPath, symbol string) string {
+	var resolver *ExternalLinkResolver
+	if t.Config != nil {
+		resolver = t.Config.ExternalLinks
+	}
+
+	mod := t.Module
+	if mod == nil && t.File != nil {
+		mod = t.File.Module
+	}
+
+	return resolver.URL(mod, importPath, symbol)
+}
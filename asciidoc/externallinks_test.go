@@ -0,0 +1,139 @@
+package asciidoc
+
+import (
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExternalLinkRule(t *testing.T) {
+	tests := []struct {
+		input      string
+		expect     ExternalLinkRule
+		shouldFail bool
+	}{
+		{
+			input:  "corp.example.com/* -> https://godoc.corp/{path}#{symbol}",
+			expect: ExternalLinkRule{Pattern: "corp.example.com/*", Template: "https://godoc.corp/{path}#{symbol}"},
+		},
+		{
+			input:  "std=https://pkg.go.dev/{path}@go1.22.0#{symbol}",
+			expect: ExternalLinkRule{Pattern: "std", Template: "https://pkg.go.dev/{path}@go1.22.0#{symbol}"},
+		},
+		{input: "corp.example.com/*", shouldFail: true},
+		{input: " -> https://godoc.corp/{path}", shouldFail: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseExternalLinkRule(tc.input)
+			if tc.shouldFail {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestExternalLinkResolverURL(t *testing.T) {
+	resolver := NewExternalLinkResolver(
+		ExternalLinkRule{Pattern: "corp.example.com/*", Template: "https://godoc.corp/{path}#{symbol}"},
+		ExternalLinkRule{Pattern: "gitlab.vendor.io/sdk*", Template: "https://vendor.gitlab.io/{package}/#{symbol}"},
+		ExternalLinkRule{Pattern: "std", Template: "https://pkg.go.dev/{path}@go1.22.0#{symbol}"},
+	)
+
+	tests := []struct {
+		importPath string
+		symbol     string
+		expect     string
+	}{
+		{"corp.example.com/lib/auth", "Token", "https://godoc.corp/corp.example.com/lib/auth#Token"},
+		{"corp.example.com", "", "https://godoc.corp/corp.example.com"},
+		{"corp.example.comx/lib", "Token", "https://pkg.go.dev/corp.example.comx/lib#Token"},
+		{"gitlab.vendor.io/sdk-go/client", "Client.Do", "https://vendor.gitlab.io/client/#Client.Do"},
+		{"net/http", "Client", "https://pkg.go.dev/net/http@go1.22.0#Client"},
+		{"github.com/acme/lib", "Bar", "https://pkg.go.dev/github.com/acme/lib#Bar"},
+		{"github.com/acme/lib", "", "https://pkg.go.dev/github.com/acme/lib"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.importPath+"#"+tc.symbol, func(t *testing.T) {
+			assert.Equal(t, tc.expect, resolver.URL(nil, tc.importPath, tc.symbol))
+		})
+	}
+
+	var none *ExternalLinkResolver
+	assert.Equal(t, "https://pkg.go.dev/fmt#Stringer", none.URL(nil, "fmt", "Stringer"))
+}
+
+func TestExternalLinksUsedBySignatureAndDocumentation(t *testing.T) {
+	ctx := testContextWithMode(TypeLinksInternalExternal)
+	ctx.Config.ExternalLinks = NewExternalLinkResolver(
+		ExternalLinkRule{Pattern: "corp.example.com/*", Template: "https://godoc.corp/{path}#{symbol}"},
+	)
+	ctx.File.Imports = []*goparser.GoImport{{Path: "corp.example.com/lib"}}
+
+	field := &goparser.GoField{
+		Struct:   &goparser.GoStruct{},
+		File:     ctx.File,
+		Name:     "Client",
+		Type:     "lib.Client",
+		Decl:     "Client lib.Client",
+		TypeInfo: &goparser.GoType{File: ctx.File, Type: "lib.Client", Kind: goparser.TypeKindSelector},
+	}
+
+	assert.Equal(
+		t,
+		"Client\tlink:https://godoc.corp/corp.example.com/lib#Client[lib.Client]",
+		ctx.fieldSummary(field),
+	)
+	assert.Equal(
+		t,
+		`lib.<a href="https://godoc.corp/corp.example.com/lib#Client">Client</a>`,
+		ctx.htmlIdentifier("lib.Client", ctx.File, nil),
+	)
+	assert.Contains(
+		t,
+		ctx.processDocumentation("Uses `lib.Client` to call the service."),
+		"link:https://godoc.corp/corp.example.com/lib#Client[lib.Client]",
+	)
+}
+
+func TestExternalLinksPinnedToModuleVersion(t *testing.T) {
+	mod, err := goparser.NewModuleFromBuff("/src/app/go.mod", []byte(`module example.com/mod
+
+go 1.22.3
+
+require github.com/acme/lib v1.2.0
+`))
+	require.NoError(t, err)
+
+	ctx := testContextWithMode(TypeLinksInternalExternal)
+	ctx.Module = mod
+	ctx.File.Imports = []*goparser.GoImport{{Path: "github.com/acme/lib/sub"}, {Path: "context"}}
+
+	assert.Equal(
+		t,
+		"link:https://pkg.go.dev/github.com/acme/lib@v1.2.0/sub#Bar[sub.Bar]",
+		ctx.linkIdentifier("sub.Bar", ctx.File, nil),
+	)
+	assert.Equal(
+		t,
+		"link:https://pkg.go.dev/context@go1.22.3#Context[context.Context]",
+		ctx.linkIdentifier("context.Context", ctx.File, nil),
+	)
+
+	resolver := NewExternalLinkResolver(
+		ExternalLinkRule{Pattern: "github.com/acme/*", Template: "https://docs.acme.io/{module}/{version}/{path}#{symbol}"},
+	)
+	assert.Equal(
+		t,
+		"https://docs.acme.io/github.com/acme/lib/v1.2.0/github.com/acme/lib/sub#Bar",
+		resolver.URL(mod, "github.com/acme/lib/sub", "Bar"),
+	)
+	assert.Equal(t, "https://pkg.go.dev/github.com/other/lib#Baz", resolver.URL(mod, "github.com/other/lib", "Baz"))
+}
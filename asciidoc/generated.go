@@ -0,0 +1,40 @@
+package asciidoc
+
+import (
+	"fmt"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// generatedNote returns the "generated by" note for a generated package (*goparser.GoPackage)
+// or file (*goparser.GoFile) when the generated policy is goparser.GeneratedBadge, otherwise
+// an empty string. Symbols in a package where all files are generated get no note of their
+// own since the package already has one.
+func (t *TemplateContext) generatedNote(node interface{}) string {
+	if t.Config == nil || t.Config.Generated != goparser.GeneratedBadge {
+		return ""
+	}
+
+	var file *goparser.GoFile
+	switch n := node.(type) {
+	case *goparser.GoPackage:
+		if n != nil {
+			file = &n.GoFile
+		}
+	case *goparser.GoFile:
+		if n != nil && t.Package != nil && t.Package.Generated {
+			return ""
+		}
+		file = n
+	}
+
+	if file == nil || !file.Generated {
+		return ""
+	}
+
+	if file.Generator == "" {
+		return "[.generated]#Generated code, do not edit.#"
+	}
+
+	return fmt.Sprintf("[.generated]#Generated by `%s`, do not edit.#", file.Generator)
+}
@@ -0,0 +1,83 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedPolicyRendering(t *testing.T) {
+	modDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/sample\n\ngo 1.21\n",
+		"sample/server.go": `package sample
+
+// Server serves requests.
+type Server struct {
+	Port int
+}
+`,
+		"sample/kind_string.go": `// Code generated by "stringer -type=Kind"; DO NOT EDIT.
+
+package sample
+
+// Kind is the kind of server.
+type Kind int
+
+// KindNames are the names of all kinds.
+var KindNames = []string{"primary"}
+`,
+		"pb/msg.pb.go": `// Code generated by protoc-gen-go. DO NOT EDIT.
+
+package pb
+
+// Msg is a message.
+type Msg struct {
+	ID string
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(modDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	render := func(policy goparser.GeneratedPolicy) string {
+		var buff bytes.Buffer
+		p := NewProducer().
+			Writer(&buff).
+			Module(modDir).
+			Include(modDir).
+			NoIndex().
+			Generated(policy)
+
+		overrideAllDefaults(t, p)
+
+		p.Generate()
+		return buff.String()
+	}
+
+	doc := render(goparser.GeneratedInclude)
+	assert.Contains(t, doc, "=== Msg")
+	assert.Contains(t, doc, "=== Kind")
+	assert.NotContains(t, doc, "Generated by")
+
+	doc = render(goparser.GeneratedBadge)
+	assert.Contains(t, doc, "[.generated]#Generated by `stringer`, do not edit.#")
+	assert.Contains(t, doc, "[.generated]#Generated by `protoc-gen-go`, do not edit.#")
+	assert.Equal(t, 2, bytes.Count([]byte(doc), []byte("Generated by `stringer`")), "type and var")
+	assert.Equal(t, 1, bytes.Count([]byte(doc), []byte("Generated by `protoc-gen-go`")), "package only")
+	assert.NotContains(t, doc, "Generated by `stringer`, do not edit.#\n\nServer")
+
+	doc = render(goparser.GeneratedExclude)
+	assert.Contains(t, doc, "=== Server")
+	assert.NotContains(t, doc, "=== Msg")
+	assert.NotContains(t, doc, "=== Kind")
+	assert.NotContains(t, doc, "KindNames")
+}
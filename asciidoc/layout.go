@@ -0,0 +1,365 @@
+package asciidoc
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// LayoutMode determines how the symbols of a package are organised in the documentation.
+type LayoutMode int
+
+const (
+	// LayoutKind renders the symbols in sections per kind, i.e. interfaces, structs, type
+	// definitions, constants, variables and functions (default).
+	LayoutKind LayoutMode = iota
+	// LayoutGodoc renders as godoc does, i.e. constants, variables and functions that are not
+	// associated with a type first. Then each type followed by its constants, variables,
+	// constructors and methods.
+	LayoutGodoc
+)
+
+// SortOrder determines the order of the symbols within a section.
+type SortOrder int
+
+const (
+	// SortSource renders the symbols in the order they are declared (default).
+	SortSource SortOrder = iota
+	// SortAlphabetical renders the symbols sorted by name.
+	SortAlphabetical
+	// SortGrouped renders the symbols associated with the same type, e.g. the constants of a
+	// type or the constructors of a type, together in the order their type first appears.
+	SortGrouped
+)
+
+// TypeMembers are the constants, variables and constructors associated with a type in the
+// godoc layout.
+type TypeMembers struct {
+	// Type is the name of the type.
+	Type string
+	// Consts are the constants of the type.
+	Consts []*goparser.GoAssignment
+	// Vars are the variables of the type.
+	Vars []*goparser.GoAssignment
+	// Constructors are the functions returning the type.
+	Constructors []*goparser.GoStructMethod
+}
+
+// localTypes returns the names of the types declared in the file.
+func localTypes(file *goparser.GoFile) map[string]struct{} {
+	names := map[string]struct{}{}
+	for _, s := range file.Structs {
+		names[s.Name] = struct{}{}
+	}
+	for _, i := range file.Interfaces {
+		names[i.Name] = struct{}{}
+	}
+	for _, c := range file.CustomTypes {
+		names[c.Name] = struct{}{}
+	}
+	for _, f := range file.CustomFuncs {
+		names[f.Name] = struct{}{}
+	}
+
+	return names
+}
+
+// associatedTypeName returns the name of the type T in a T or *T type expression, with any
+// type arguments removed, or an empty string if the expression is not a local named type.
+func associatedTypeName(expr string, locals map[string]struct{}) string {
+	name := strings.TrimPrefix(strings.TrimSpace(expr), "*")
+	if i := strings.Index(name, "["); i > 0 {
+		name = name[:i]
+	}
+
+	if _, ok := locals[name]; !ok {
+		return ""
+	}
+
+	return name
+}
+
+// constructorOf returns the type a function, without a receiver, constructs. That is the
+// single local type among its results, if any.
+func constructorOf(fn *goparser.GoStructMethod, locals map[string]struct{}) string {
+	if len(fn.Receivers) > 0 {
+		return ""
+	}
+
+	owner := ""
+	for _, result := range fn.Results {
+		name := associatedTypeName(result.Type, locals)
+		switch {
+		case name == "" || name == owner:
+			continue
+		case owner != "":
+			return ""
+		}
+
+		owner = name
+	}
+
+	return owner
+}
+
+// associatedType returns the name of the local type the symbol is associated with. Types are
+// associated with themselves, typed constants and variables with their type, constructors with
+// the type they return and methods with their receiver.
+func associatedType(node interface{}, locals map[string]struct{}) string {
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		return n.Name
+	case *goparser.GoInterface:
+		return n.Name
+	case *goparser.GoCustomType:
+		return n.Name
+	case *goparser.GoMethod:
+		if _, ok := locals[n.Name]; ok {
+			return n.Name
+		}
+	case *goparser.GoAssignment:
+		return associatedTypeName(n.Type, locals)
+	case *goparser.GoStructMethod:
+		if owner := n.ReceiverName(); owner != "" {
+			return owner
+		}
+
+		return constructorOf(n, locals)
+	}
+
+	return ""
+}
+
+// symbolName returns the name of a symbol, or an empty string for unknown nodes.
+func symbolName(node interface{}) string {
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		return n.Name
+	case *goparser.GoInterface:
+		return n.Name
+	case *goparser.GoCustomType:
+		return n.Name
+	case *goparser.GoMethod:
+		return n.Name
+	case *goparser.GoStructMethod:
+		return n.Name
+	case *goparser.GoField:
+		return n.Name
+	case *goparser.GoAssignment:
+		return n.Name
+	}
+
+	return ""
+}
+
+// symbolPosition returns the file and line, starting from 1, of the declaration of a type.
+func symbolPosition(node interface{}) (string, int) {
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		return filePath(n.File), n.Line
+	case *goparser.GoInterface:
+		return filePath(n.File), n.Line
+	case *goparser.GoCustomType:
+		return filePath(n.File), n.Line
+	case *goparser.GoMethod:
+		return filePath(n.File), n.Line
+	}
+
+	return "", 0
+}
+
+func filePath(file *goparser.GoFile) string {
+	if file == nil {
+		return ""
+	}
+
+	return file.FilePath
+}
+
+// sortSymbols sorts the symbols in place according to the sort order. The locals are the types
+// the symbols may be associated with when grouped.
+func sortSymbols(symbols []interface{}, order SortOrder, locals map[string]struct{}) {
+	switch order {
+	case SortAlphabetical:
+		sort.SliceStable(symbols, func(i, j int) bool {
+			return symbolName(symbols[i]) < symbolName(symbols[j])
+		})
+	case SortGrouped:
+		keys := make([]string, len(symbols))
+		first := map[string]int{}
+		for i, node := range symbols {
+			keys[i] = associatedType(node, locals)
+			if keys[i] == "" {
+				// Not associated with a type, it is a group of its own.
+				keys[i] = "\x00" + symbolName(node)
+			}
+
+			if _, ok := first[keys[i]]; !ok {
+				first[keys[i]] = i
+			}
+		}
+
+		indices := make([]int, len(symbols))
+		for i := range indices {
+			indices[i] = i
+		}
+
+		sort.SliceStable(indices, func(i, j int) bool {
+			return first[keys[indices[i]]] < first[keys[indices[j]]]
+		})
+
+		sorted := make([]interface{}, len(symbols))
+		for i, idx := range indices {
+			sorted[i] = symbols[idx]
+		}
+
+		copy(symbols, sorted)
+	}
+}
+
+// typesOf returns the structs, interfaces, custom types and function types of the file ordered
+// by their position in source.
+func typesOf(file *goparser.GoFile) []interface{} {
+	var list []interface{}
+	for _, s := range file.Structs {
+		list = append(list, s)
+	}
+	for _, i := range file.Interfaces {
+		list = append(list, i)
+	}
+	for _, c := range file.CustomTypes {
+		list = append(list, c)
+	}
+	for _, f := range file.CustomFuncs {
+		list = append(list, f)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		fi, li := symbolPosition(list[i])
+		fj, lj := symbolPosition(list[j])
+		if fi != fj {
+			return fi < fj
+		}
+
+		return li < lj
+	})
+
+	return list
+}
+
+// typeMembers returns the constants, variables and constructors associated with the type.
+func (t *TemplateContext) typeMembers(name string) *TypeMembers {
+	members := &TypeMembers{Type: name}
+	if t.File == nil {
+		return members
+	}
+
+	locals := localTypes(t.File)
+	for _, a := range t.File.ConstAssignments {
+		if associatedTypeName(a.Type, locals) == name {
+			members.Consts = append(members.Consts, a)
+		}
+	}
+	for _, a := range t.File.VarAssignments {
+		if associatedTypeName(a.Type, locals) == name {
+			members.Vars = append(members.Vars, a)
+		}
+	}
+	for _, fn := range t.File.StructMethods {
+		if constructorOf(fn, locals) == name {
+			members.Constructors = append(members.Constructors, fn)
+		}
+	}
+
+	return members
+}
+
+// unassociated returns a shallow copy of the file without the constants, variables and
+// functions that are associated with a type, i.e. those rendered as members of the types in
+// the godoc layout.
+func unassociated(file *goparser.GoFile) *goparser.GoFile {
+	locals := localTypes(file)
+	copied := *file
+
+	copied.ConstAssignments = nil
+	for _, a := range file.ConstAssignments {
+		if associatedTypeName(a.Type, locals) == "" {
+			copied.ConstAssignments = append(copied.ConstAssignments, a)
+		}
+	}
+
+	copied.VarAssignments = nil
+	for _, a := range file.VarAssignments {
+		if associatedTypeName(a.Type, locals) == "" {
+			copied.VarAssignments = append(copied.VarAssignments, a)
+		}
+	}
+
+	copied.StructMethods = nil
+	for _, fn := range file.StructMethods {
+		if constructorOf(fn, locals) == "" {
+			copied.StructMethods = append(copied.StructMethods, fn)
+		}
+	}
+
+	return &copied
+}
+
+// functionsOf returns the functions, i.e. not methods, of the list.
+func functionsOf(list []*goparser.GoStructMethod) []*goparser.GoStructMethod {
+	var functions []*goparser.GoStructMethod
+	for _, f := range list {
+		if len(f.Receivers) == 0 {
+			functions = append(functions, f)
+		}
+	}
+
+	return functions
+}
+
+// layout returns the configured layout mode.
+func (t *TemplateContext) layout() LayoutMode {
+	if t.Config == nil {
+		return LayoutKind
+	}
+
+	return t.Config.Layout
+}
+
+// renderTypeMembers renders the constants, variables and constructors of the type when in the
+// godoc layout, otherwise it renders nothing.
+func (t *TemplateContext) renderTypeMembers(name string) string {
+	if t.layout() != LayoutGodoc {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	t.RenderTypeMembers(&buf, name)
+	return buf.String()
+}
+
+// renderGodocLayout renders the imports, the constants, variables and functions that are not
+// associated with a type and last the types, with their members, of the file.
+func (t *TemplateContext) renderGodocLayout(wr io.Writer) {
+	sections := t.Clone(false)
+	sections.File = unassociated(t.File)
+
+	if len(t.File.Imports) > 0 {
+		t.RenderImports(wr)
+	}
+	if hasDocumentedSymbols(sections.File.ConstAssignments) {
+		sections.RenderConstDeclarations(wr)
+	}
+	if hasDocumentedSymbols(sections.File.VarAssignments) {
+		sections.RenderVarDeclarations(wr)
+	}
+	if hasDocumentedSymbols(functionsOf(sections.File.StructMethods)) {
+		sections.RenderFunctions(wr)
+	}
+	if hasDocumentedSymbols(typesOf(t.File)) {
+		t.RenderTypes(wr)
+	}
+}
@@ -0,0 +1,218 @@
+package asciidoc
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const layoutSource = `// Package shop sells things.
+package shop
+
+import "errors"
+
+// ErrNotFound is returned when missing.
+var ErrNotFound = errors.New("not found")
+
+// MaxItems is the max.
+const MaxItems = 10
+
+// Level is a level.
+type Level int
+
+const (
+	// Low is low.
+	Low Level = iota
+	// High is high.
+	High
+)
+
+// Item is an item.
+type Item struct {
+	// Name is the name.
+	Name string
+}
+
+// NewItem creates an item.
+func NewItem(name string) (*Item, error) { return &Item{Name: name}, nil }
+
+// DefaultItem is the default.
+var DefaultItem = Item{}
+
+// Reset resets.
+func (i *Item) Reset() {}
+
+// Store stores.
+type Store interface {
+	// Get gets.
+	Get() Item
+}
+
+// Open opens a store.
+func Open() Store { return nil }
+
+// Helper helps.
+func Helper() {}
+`
+
+// layoutHeadings renders the layout source and returns the level 2 and 3 section headings.
+func layoutHeadings(t *testing.T, configure func(p *Producer)) []string {
+	t.Helper()
+	modDir := t.TempDir()
+	writeFiles(t, modDir, map[string]string{
+		"go.mod":       "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": layoutSource,
+	})
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(filepath.Join(modDir, "shop")).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+	configure(p)
+	p.Generate()
+
+	var headings []string
+	for _, m := range regexp.MustCompile(`(?m)^(={2,4} .*)$`).FindAllStringSubmatch(buff.String(), -1) {
+		headings = append(headings, m[1])
+	}
+
+	return headings
+}
+
+func TestGodocLayout(t *testing.T) {
+	headings := layoutHeadings(t, func(p *Producer) { p.Layout(LayoutGodoc) })
+
+	assert.Equal(t, []string{
+		"== Package example.com/shop/shop",
+		"== Imports",
+		"== Constants",
+		"=== MaxItems",
+		"== Variables",
+		"=== ErrNotFound",
+		"== Functions",
+		"=== Helper",
+		"== Types",
+		"=== Level",
+		"==== Constants",
+		"=== Low",
+		"=== High",
+		"=== Item",
+		"==== Name string",
+		"==== Variables",
+		"=== DefaultItem",
+		"==== Constructors",
+		"=== NewItem",
+		"==== Receivers",
+		"=== Store",
+		"==== Get() Item",
+		"==== Constructors",
+		"=== Open",
+	}, headings)
+}
+
+func TestSortOrders(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  SortOrder
+		expect []string
+	}{
+		{"source", SortSource, []string{"MaxItems", "Low", "High"}},
+		{"alphabetical", SortAlphabetical, []string{"High", "Low", "MaxItems"}},
+		{"grouped", SortGrouped, []string{"MaxItems", "Low", "High"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			headings := layoutHeadings(t, func(p *Producer) { p.SortOrder(tc.order) })
+
+			var consts []string
+			for i, heading := range headings {
+				if heading != "== Constants" {
+					continue
+				}
+				for _, h := range headings[i+1:] {
+					if h[:3] != "===" {
+						break
+					}
+					consts = append(consts, h[4:])
+				}
+			}
+
+			assert.Equal(t, tc.expect, consts)
+		})
+	}
+}
+
+func TestSortGroupedByAssociatedType(t *testing.T) {
+	file, err := goparser.ParseInlineFile(nil, "shop.go", layoutSource+`
+// Min is the min.
+const Min = 1
+
+// Other is another level.
+const Other Level = 7
+`)
+	require.NoError(t, err)
+
+	ctx := NewTemplateWithOverrides(nil).NewContextWithConfig(file, nil, &TemplateContextConfig{
+		SortOrder: SortGrouped,
+	})
+
+	var names []string
+	for _, group := range ctx.groups(file.ConstAssignments) {
+		for _, node := range group.Symbols {
+			names = append(names, symbolName(node))
+		}
+	}
+	assert.Equal(t, []string{"MaxItems", "Low", "High", "Other", "Min"}, names)
+
+	names = nil
+	for _, group := range ctx.groups(file.StructMethods) {
+		for _, node := range group.Symbols {
+			names = append(names, symbolName(node))
+		}
+	}
+	assert.Equal(t, []string{"NewItem", "Reset", "Open", "Helper"}, names)
+}
+
+func TestConstructorOf(t *testing.T) {
+	file, err := goparser.ParseInlineFile(nil, "shop.go", `package shop
+
+import "time"
+
+type Item struct{}
+
+type List[T any] struct{}
+
+func NewItem() *Item { return nil }
+func ParseItem(s string) (Item, error) { return Item{}, nil }
+func NewList[T any]() *List[T] { return nil }
+func Pair() (*Item, *List[int]) { return nil, nil }
+func Now() time.Time { return time.Now() }
+func (i *Item) Clone() *Item { return i }
+`)
+	require.NoError(t, err)
+
+	locals := localTypes(file)
+	constructs := map[string]string{}
+	for _, fn := range file.StructMethods {
+		constructs[fn.Name] = constructorOf(fn, locals)
+	}
+
+	assert.Equal(t, map[string]string{
+		"NewItem":   "Item",
+		"ParseItem": "Item",
+		"NewList":   "List",
+		"Pair":      "",
+		"Now":       "",
+		"Clone":     "",
+	}, constructs)
+}
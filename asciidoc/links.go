@@ -167,6 +167,17 @@ func (t *TemplateContext) packagePathForFile(file *goparser.GoFile) string {
 	return ""
 }
 
+// isFiltered returns true if the symbol, e.g. Item or Item.Price, in the package is removed by
+// the symbol filter.
+func (t *TemplateContext) isFiltered(pkgPath, name string) bool {
+	if t.Config == nil || len(t.Config.Filtered) == 0 {
+		return false
+	}
+
+	_, ok := t.Config.Filtered[pkgPath+"."+name]
+	return ok
+}
+
 func anchorID(pkgPath, typeName string) string {
 	identifier := pkgPath
 	if identifier != "" {
@@ -408,7 +419,7 @@ func (t *TemplateContext) linkIdentifier(
 			return prefix + trimmed
 		}
 		anchor := anchorID(pkgPath, typeName)
-		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled {
+		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled && !t.isFiltered(pkgPath, typeName) {
 			return prefix + fmt.Sprintf("<<%s,%s>>", anchor, trimmed)
 		}
 		return prefix + trimmed
@@ -421,7 +432,7 @@ func (t *TemplateContext) linkIdentifier(
 
 	if t.isInternalImport(importPath) {
 		anchor := anchorID(importPath, typeName)
-		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled {
+		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled && !t.isFiltered(importPath, typeName) {
 			// Check if this is a cross-module reference in separate mode
 			if t.Config.SubModuleMode == SubModuleSeparate && t.isWorkspaceImport(importPath) {
 				// Cross-module reference - use file link
@@ -438,7 +449,7 @@ func (t *TemplateContext) linkIdentifier(
 	}
 
 	if t.Config != nil && t.Config.TypeLinks == TypeLinksInternalExternal {
-		url := fmt.Sprintf("https://pkg.go.dev/%s#%s", importPath, typeName)
+		url := t.externalURL(importPath, typeName)
 		return prefix + fmt.Sprintf("link:%s[%s]", url, trimmed)
 	}
 
@@ -946,7 +957,7 @@ func (t *TemplateContext) htmlIdentifier(
 			return html.EscapeString(trimmed)
 		}
 		anchor := anchorID(pkgPath, typeName)
-		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled {
+		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled && !t.isFiltered(pkgPath, typeName) {
 			return fmt.Sprintf("<a href=\"#%s\">%s</a>", anchor, html.EscapeString(typeName))
 		}
 		return html.EscapeString(trimmed)
@@ -959,17 +970,16 @@ func (t *TemplateContext) htmlIdentifier(
 	nameEsc := html.EscapeString(typeName)
 	if t.isInternalImport(importPath) {
 		anchor := anchorID(importPath, typeName)
-		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled {
+		if t.Config != nil && t.Config.TypeLinks != TypeLinksDisabled && !t.isFiltered(importPath, typeName) {
 			return fmt.Sprintf("%s.<a href=\"#%s\">%s</a>", aliasEsc, anchor, nameEsc)
 		}
 		return fmt.Sprintf("%s.%s", aliasEsc, nameEsc)
 	}
 	if t.Config != nil && t.Config.TypeLinks == TypeLinksInternalExternal {
 		return fmt.Sprintf(
-			"%s.<a href=\"https://pkg.go.dev/%s#%s\">%s</a>",
+			"%s.<a href=\"%s\">%s</a>",
 			aliasEsc,
-			importPath,
-			typeName,
+			html.EscapeString(t.externalURL(importPath, typeName)),
 			nameEsc,
 		)
 	}
@@ -0,0 +1,663 @@
+package asciidoc
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// The documentation lint rules, see LintRules.
+const (
+	// RuleMissingDoc is an exported symbol without documentation.
+	RuleMissingDoc = "missing-doc"
+	// RuleDocPrefix is documentation that does not start with the name of the symbol.
+	RuleDocPrefix = "doc-prefix"
+	// RuleUnresolvedReference is a backtick reference, e.g. `Item.Reset`, to no known symbol.
+	RuleUnresolvedReference = "unresolved-reference"
+	// RuleMissingOverview is a package without any of the package overview files, see PackageDoc.
+	RuleMissingOverview = "missing-overview"
+	// RuleUnbalancedDelimiter is an asciidoc delimited block, e.g. ----, that is never closed.
+	RuleUnbalancedDelimiter = "unbalanced-delimiter"
+)
+
+var (
+	// referenceCandidateRegex matches backtick contents that look like a symbol reference e.g.
+	// Item, Item.Reset, shop.Item or example.com/shop.Item.
+	referenceCandidateRegex = regexp.MustCompile(`^[A-Za-z_]\w*(?:[./][A-Za-z_][\w-]*)*$`)
+
+	// fileNameRegex matches backtick contents that are file names e.g. go.mod or README.md.
+	fileNameRegex = regexp.MustCompile(`\.(?:go|mod|sum|work|md|adoc|asciidoc|json|ya?ml|toml|xml|html|txt|sh)$`)
+
+	// groupDeclRegex matches the first line of a grouped declaration e.g. const (
+	groupDeclRegex = regexp.MustCompile(`^(?:const|var|type)\s*\($`)
+)
+
+// LintRule is a documentation lint rule.
+type LintRule struct {
+	// Name identifies the rule e.g. missing-doc.
+	Name string
+	// Description describes what the rule checks.
+	Description string
+	// Severity is the default severity of the rule.
+	Severity Severity
+}
+
+// LintRules returns the documentation lint rules with their default severity.
+func LintRules() []LintRule {
+	return []LintRule{
+		{RuleMissingDoc, "Exported symbols shall be documented.", SeverityWarning},
+		{RuleDocPrefix, "The documentation shall start with the name of the symbol.", SeverityWarning},
+		{RuleUnresolvedReference, "Backtick references shall resolve to a symbol.", SeverityWarning},
+		{RuleMissingOverview, "Each package shall have a package overview file.", SeverityWarning},
+		{RuleUnbalancedDelimiter, "Asciidoc delimited blocks shall be closed.", SeverityError},
+	}
+}
+
+// LintSeverity sets the severity of the lint rule, SeverityOff disables the rule.
+func (p *Producer) LintSeverity(rule string, severity Severity) *Producer {
+	if p.lintSeverities == nil {
+		p.lintSeverities = map[string]Severity{}
+	}
+
+	p.lintSeverities[rule] = severity
+	return p
+}
+
+// Lint checks the documentation of the included packages instead of generating it and returns
+// the problems, see LintRules, sorted by file and position. The problems are also returned by
+// Diagnostics.
+//
+// A //goasciidoc:nolint directive, in a doc comment or outside of the declarations of a file,
+// suppresses all, or the listed, rules e.g. //goasciidoc:nolint missing-doc,doc-prefix.
+func (p *Producer) Lint() ([]Diagnostic, error) {
+	p.diagnostics = nil
+
+	var packages []*goparser.GoPackage
+	err := goparser.ParseSinglePackageWalker(p.parseconfig, func(pkg *goparser.GoPackage) error {
+		packages = append(packages, pkg)
+		return nil
+	}, p.paths...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l := newLinter(packages, p.lintSeverities, p.overviewpaths, p.report)
+	for _, pkg := range packages {
+		l.lintPackage(pkg)
+	}
+
+	sort.SliceStable(p.diagnostics, func(i, j int) bool {
+		a, b := p.diagnostics[i], p.diagnostics[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Column < b.Column
+	})
+
+	return p.diagnostics, nil
+}
+
+// linter lints the documentation of packages.
+type linter struct {
+	severities    map[string]Severity
+	overviewpaths []string
+	report        func(Diagnostic)
+	// symbols are the names, e.g. Item, Item.Reset or Item.Name, by package path of the
+	// linted packages.
+	symbols map[string]map[string]bool
+	// sources caches the lines of the source files.
+	sources map[string][]string
+	// seen are the reported diagnostics, a group doc is linted once per symbol of the group.
+	seen map[Diagnostic]bool
+}
+
+// lintTarget is a documentation to lint.
+type lintTarget struct {
+	// node is the symbol, nil for the package documentation.
+	node interface{}
+	name string
+	doc  string
+	file *goparser.GoFile
+	// line is the line of the declaration, zero when unknown.
+	line int
+	// from and to are the lines of the enclosing declaration, e.g. the struct of a field, to
+	// search the documentation in when the line is unknown.
+	from, to int
+	// public is true for the exported symbols that shall be documented.
+	public bool
+	// grouped is true when the symbol is part of a grouped declaration e.g. const ( ... ).
+	grouped bool
+}
+
+func newLinter(
+	packages []*goparser.GoPackage,
+	severities map[string]Severity,
+	overviewpaths []string,
+	report func(Diagnostic)) *linter {
+
+	l := &linter{
+		severities:    map[string]Severity{},
+		overviewpaths: overviewpaths,
+		report:        report,
+		symbols:       map[string]map[string]bool{},
+		sources:       map[string][]string{},
+		seen:          map[Diagnostic]bool{},
+	}
+
+	for _, rule := range LintRules() {
+		l.severities[rule.Name] = rule.Severity
+	}
+	for rule, severity := range severities {
+		l.severities[rule] = severity
+	}
+
+	for _, pkg := range packages {
+		names := l.symbols[pkg.FqPackage]
+		if names == nil {
+			names = map[string]bool{}
+			l.symbols[pkg.FqPackage] = names
+		}
+
+		for _, s := range pkg.Structs {
+			names[s.Name] = true
+			for _, f := range s.Fields {
+				names[s.Name+"."+f.Name] = true
+			}
+		}
+		for _, i := range pkg.Interfaces {
+			names[i.Name] = true
+			for _, m := range i.Methods {
+				names[i.Name+"."+m.Name] = true
+			}
+		}
+		for _, c := range pkg.CustomTypes {
+			names[c.Name] = true
+		}
+		for _, c := range pkg.CustomFuncs {
+			names[c.Name] = true
+		}
+		for _, a := range append(append([]*goparser.GoAssignment{}, pkg.ConstAssignments...), pkg.VarAssignments...) {
+			names[a.Name] = true
+		}
+		for _, fn := range pkg.StructMethods {
+			if owner := fn.ReceiverName(); owner != "" {
+				names[owner+"."+fn.Name] = true
+			} else {
+				names[fn.Name] = true
+			}
+		}
+	}
+
+	return l
+}
+
+// lintPackage lints the package overview and the documentation of the package and its symbols.
+func (l *linter) lintPackage(pkg *goparser.GoPackage) {
+	l.lintOverview(pkg)
+
+	for _, t := range lintTargets(pkg) {
+		if t.node != nil && ignored(t.node) {
+			continue
+		}
+
+		l.lintDoc(pkg, t)
+	}
+}
+
+// lintOverview reports a package without any of the package overview files.
+func (l *linter) lintOverview(pkg *goparser.GoPackage) {
+	if len(l.overviewpaths) == 0 || pkg.FilePath == "" {
+		return
+	}
+
+	for _, path := range l.overviewpaths {
+		if fileExists(filepath.Join(pkg.FilePath, path)) {
+			return
+		}
+	}
+
+	for _, file := range pkg.Files {
+		if file.Directives.NoLint(RuleMissingOverview) {
+			return
+		}
+	}
+
+	l.emit(Diagnostic{
+		Rule:    RuleMissingOverview,
+		File:    filepath.Join(pkg.FilePath, l.overviewpaths[0]),
+		Message: "package " + pkg.FqPackage + " has no overview, searched " + strings.Join(l.overviewpaths, ", "),
+	}, nil)
+}
+
+// lintTargets returns the package documentation and the documentation of the symbols.
+func lintTargets(pkg *goparser.GoPackage) []lintTarget {
+	var targets []lintTarget
+
+	for _, file := range pkg.Files {
+		if file.Doc != "" {
+			targets = append(targets, lintTarget{name: pkg.Package, doc: file.Doc, file: file})
+		}
+	}
+
+	for _, s := range pkg.Structs {
+		targets = append(targets, lintTarget{
+			node: s, name: s.Name, doc: s.Doc, file: s.File, line: s.Line, public: s.Exported,
+		})
+
+		for _, f := range s.Fields {
+			targets = append(targets, lintTarget{
+				node: f, name: f.Name, doc: f.Doc, file: s.File, from: s.Line, to: s.Line + strings.Count(s.FullDecl, "\n"),
+			})
+		}
+	}
+
+	for _, i := range pkg.Interfaces {
+		targets = append(targets, lintTarget{
+			node: i, name: i.Name, doc: i.Doc, file: i.File, line: i.Line, public: i.Exported,
+		})
+
+		for _, m := range i.Methods {
+			targets = append(targets, lintTarget{
+				node: m, name: m.Name, doc: m.Doc, file: i.File, line: m.Line, from: i.Line, to: i.Line + strings.Count(i.FullDecl, "\n"),
+			})
+		}
+	}
+
+	for _, c := range pkg.CustomTypes {
+		targets = append(targets, lintTarget{
+			node: c, name: c.Name, doc: c.Doc, file: c.File, line: c.Line, public: c.Exported,
+		})
+	}
+
+	for _, c := range pkg.CustomFuncs {
+		targets = append(targets, lintTarget{
+			node: c, name: c.Name, doc: c.Doc, file: c.File, line: c.Line, public: c.Exported,
+		})
+	}
+
+	for _, a := range append(append([]*goparser.GoAssignment{}, pkg.ConstAssignments...), pkg.VarAssignments...) {
+		targets = append(targets, lintTarget{
+			node: a, name: a.Name, doc: a.Doc, file: a.File, line: a.Line, public: a.Exported,
+			grouped: groupDeclRegex.MatchString(strings.TrimSpace(strings.SplitN(a.FullDecl, "\n", 2)[0])),
+		})
+	}
+
+	for _, fn := range pkg.StructMethods {
+		owner := fn.ReceiverName()
+		targets = append(targets, lintTarget{
+			node: fn, name: fn.Name, doc: fn.Doc, file: fn.File, line: fn.Line,
+			public: fn.Exported && (owner == "" || token.IsExported(owner)),
+		})
+	}
+
+	return targets
+}
+
+// identIndex returns the byte index of the first occurrence of the identifier name, as a whole
+// word, in the line or -1 when not found.
+func identIndex(line, name string) int {
+	if name == "" {
+		return -1
+	}
+
+	for offset := 0; offset < len(line); {
+		i := strings.Index(line[offset:], name)
+		if i < 0 {
+			return -1
+		}
+
+		start, end := offset+i, offset+i+len(name)
+		before, _ := utf8.DecodeLastRuneInString(line[:start])
+		after, _ := utf8.DecodeRuneInString(line[end:])
+		if (start == 0 || !isIdentRune(before)) && (end == len(line) || !isIdentRune(after)) {
+			return start
+		}
+
+		offset = start + 1
+	}
+
+	return -1
+}
+
+// isIdentRune reports if r may be part of a Go identifier.
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// lintDoc lints the documentation of the target.
+func (l *linter) lintDoc(pkg *goparser.GoPackage, t lintTarget) {
+	doc := strings.TrimSpace(t.doc)
+
+	if t.public && doc == "" {
+		line, column := t.line, 0
+		if lines := l.lines(t.file); t.line > 0 && t.line <= len(lines) {
+			if i := identIndex(lines[t.line-1], t.name); i >= 0 {
+				column = i + 1
+			}
+		}
+
+		l.emitAt(t, line, column, RuleMissingDoc, "exported "+t.name+" has no documentation")
+		return
+	}
+
+	if doc == "" {
+		return
+	}
+
+	if t.public && !t.grouped && !docStartsWith(doc, t.name) {
+		first := strings.Fields(doc)[0]
+		line, column := l.locate(t, first, 0)
+		l.emitAt(t, line, column, RuleDocPrefix, "documentation of "+t.name+" should start with \""+t.name+" ...\"")
+	}
+
+	tc := &TemplateContext{
+		File:    t.file,
+		Package: pkg,
+		Module:  pkg.Module,
+		Config:  &TemplateContextConfig{TypeLinks: TypeLinksInternal},
+	}
+
+	occurrences := map[string]int{}
+	for _, m := range backtickPattern.FindAllStringSubmatch(t.doc, -1) {
+		nth := occurrences[m[0]]
+		occurrences[m[0]]++
+
+		if l.unresolved(tc, strings.TrimSpace(m[1])) {
+			line, column := l.locate(t, m[0], nth)
+			l.emitAt(t, line, column, RuleUnresolvedReference, "reference "+m[0]+" does not resolve to a symbol")
+		}
+	}
+
+	for _, block := range unclosedBlocks(t.doc) {
+		line, column := l.locate(t, block.line, block.nth)
+		l.emitAt(t, line, column, RuleUnbalancedDelimiter, "delimited block "+block.delimiter+" is never closed")
+	}
+}
+
+// unresolved returns true if the backtick content is a reference, that processDocumentation
+// would link, to no symbol. Contents that do not look like references, e.g. code or lower case
+// words, external references and references to packages that are not linted are never
+// unresolved.
+func (l *linter) unresolved(tc *TemplateContext, content string) bool {
+	if !referenceCandidateRegex.MatchString(content) || fileNameRegex.MatchString(content) {
+		return false
+	}
+
+	first := content
+	if i := strings.IndexAny(content, "./"); i != -1 {
+		first = content[:i]
+	}
+	if !strings.Contains(content, "/") && !token.IsExported(first) && tc.importPathForAlias(first, tc.File) == "" {
+		return false
+	}
+
+	ref := tc.parseReference(content)
+	if ref == nil {
+		return false
+	}
+	if !tc.resolveReference(ref) {
+		return true
+	}
+	if ref.IsExternal || ref.Kind == RefPackage {
+		return false
+	}
+
+	names, ok := l.symbols[ref.PackagePath]
+	if !ok {
+		return false
+	}
+
+	if ref.Receiver != "" {
+		return !names[ref.Receiver+"."+ref.Identifier]
+	}
+
+	return !names[ref.Identifier]
+}
+
+// emitAt reports the problem, of the target, at the line and column unless suppressed.
+func (l *linter) emitAt(t lintTarget, line, column int, rule, message string) {
+	var directives goparser.GoDirectives
+	if t.node != nil {
+		directives = symbolDirectives(t.node)
+	}
+	if t.file != nil {
+		directives = append(append(goparser.GoDirectives{}, directives...), t.file.Directives...)
+	}
+
+	path := ""
+	if t.file != nil {
+		path = t.file.FilePath
+	}
+
+	l.emit(Diagnostic{Rule: rule, File: path, Line: line, Column: column, Message: message}, directives)
+}
+
+// emit reports the diagnostic, with the severity of its rule, unless the rule is off or
+// suppressed by the directives.
+func (l *linter) emit(d Diagnostic, directives goparser.GoDirectives) {
+	d.Severity = l.severities[d.Rule]
+	if d.Severity == SeverityOff || directives.NoLint(d.Rule) || l.seen[d] {
+		return
+	}
+
+	l.seen[d] = true
+	l.report(d)
+}
+
+// lines returns the lines of the file.
+func (l *linter) lines(file *goparser.GoFile) []string {
+	if file == nil || file.FilePath == "" {
+		return nil
+	}
+
+	if lines, ok := l.sources[file.FilePath]; ok {
+		return lines
+	}
+
+	data, err := os.ReadFile(file.FilePath)
+	if err != nil {
+		l.sources[file.FilePath] = nil
+		return nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	l.sources[file.FilePath] = lines
+
+	return lines
+}
+
+// locate returns the line and column, starting from 1, of the nth, from 0, occurrence of the
+// text in the documentation of the target. When not found, it is the line of the declaration
+// and column zero.
+func (l *linter) locate(t lintTarget, text string, nth int) (int, int) {
+	lines := l.lines(t.file)
+
+	from, to := t.from, t.to
+	switch {
+	case t.node == nil:
+		from, to = 1, packageClauseLine(lines)
+	case t.line > 0:
+		from, to = commentAbove(lines, t.line)
+		if from == 0 {
+			from, to = t.from, t.to
+		}
+	}
+
+	for n := from; n > 0 && n <= to && n <= len(lines); n++ {
+		line := lines[n-1]
+		offset := 0
+		for {
+			i := strings.Index(line[offset:], text)
+			if i == -1 {
+				break
+			}
+			if nth == 0 {
+				return n, utf8.RuneCountInString(line[:offset+i]) + 1
+			}
+
+			nth--
+			offset += i + len(text)
+		}
+	}
+
+	if t.line > 0 {
+		return t.line, 0
+	}
+
+	return from, 0
+}
+
+// commentAbove returns the first and last line, starting from 1, of the comment above the
+// declaration at line, or the comment above the grouped declaration, e.g. const (, the
+// declaration is part of. It returns zeros when there is no comment.
+func commentAbove(lines []string, line int) (int, int) {
+	end := line - 2
+	for end >= 0 && end < len(lines) {
+		i := end
+		if strings.HasSuffix(strings.TrimSpace(lines[i]), "*/") {
+			for i >= 0 && !strings.Contains(lines[i], "/*") {
+				i--
+			}
+			if i >= 0 {
+				return i + 1, end + 1
+			}
+
+			return 0, 0
+		}
+
+		for i >= 0 && strings.HasPrefix(strings.TrimSpace(lines[i]), "//") {
+			i--
+		}
+		if i < end {
+			return i + 2, end + 1
+		}
+
+		if !groupDeclRegex.MatchString(strings.TrimSpace(lines[end])) {
+			break
+		}
+
+		end--
+	}
+
+	return 0, 0
+}
+
+// packageClauseLine returns the line, starting from 1, of the package clause.
+func packageClauseLine(lines []string) int {
+	for i, line := range lines {
+		if strings.HasPrefix(line, "package ") {
+			return i + 1
+		}
+	}
+
+	return len(lines)
+}
+
+// docStartsWith returns true if the documentation starts with the name, optionally after an
+// article e.g. "A Item is", or is a deprecation notice.
+func docStartsWith(doc, name string) bool {
+	if strings.HasPrefix(doc, "Deprecated:") {
+		return true
+	}
+
+	for _, article := range []string{"A ", "An ", "The "} {
+		if strings.HasPrefix(doc, article) {
+			doc = doc[len(article):]
+			break
+		}
+	}
+
+	if !strings.HasPrefix(doc, name) {
+		return false
+	}
+
+	next, _ := utf8.DecodeRuneInString(doc[len(name):])
+	return next == utf8.RuneError || !(unicode.IsLetter(next) || unicode.IsDigit(next) || next == '_')
+}
+
+// openBlock is an asciidoc delimited block that is not closed.
+type openBlock struct {
+	// delimiter is the delimiter that closes the block e.g. ----.
+	delimiter string
+	// line is the, trimmed, line that opened the block and nth its occurrence, from 0, in the doc.
+	line string
+	nth  int
+}
+
+// unclosedBlocks returns the asciidoc delimited blocks, e.g. ---- or |===, in the doc that are
+// never closed. Blocks nest, except in verbatim blocks e.g. listings, where only the closing
+// delimiter is recognized. A delimiter of an enclosing block closes it, and the blocks within
+// it are unclosed.
+func unclosedBlocks(doc string) []openBlock {
+	var (
+		stack, unclosed []openBlock
+		occurrences     = map[string]int{}
+	)
+
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+
+		delimiter := blockDelimiter(line)
+		if delimiter == "" {
+			continue
+		}
+
+		nth := occurrences[line]
+		occurrences[line]++
+
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.delimiter == delimiter {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			if strings.ContainsAny(top.delimiter[:1], "-.+/`|") && top.delimiter != "--" {
+				continue
+			}
+		}
+
+		closed := false
+		for i := len(stack) - 2; i >= 0 && !closed; i-- {
+			if stack[i].delimiter == delimiter {
+				unclosed = append(unclosed, stack[i+1:]...)
+				stack, closed = stack[:i], true
+			}
+		}
+
+		if !closed {
+			stack = append(stack, openBlock{delimiter: delimiter, line: line, nth: nth})
+		}
+	}
+
+	return append(unclosed, stack...)
+}
+
+// blockDelimiter returns the delimiter, that closes the block, if the line opens, or closes, an
+// asciidoc delimited block, otherwise an empty string.
+func blockDelimiter(line string) string {
+	switch {
+	case strings.HasPrefix(line, "```"):
+		return "```"
+	case line == "--":
+		return line
+	case strings.HasPrefix(line, "|===") && strings.Trim(line[1:], "=") == "":
+		return line
+	case len(line) >= 4 && strings.ContainsAny(line[:1], "-.=*_+/") && strings.Trim(line, line[:1]) == "":
+		return line
+	}
+
+	return ""
+}
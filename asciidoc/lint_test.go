@@ -0,0 +1,188 @@
+package asciidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const lintSource = `// Package shop sells items, see ` + "`Item`" + ` and ` + "`Store`" + `.
+package shop
+
+import "fmt"
+
+type Item struct {
+	// Name is the name, see ` + "`Item.Price`" + `.
+	Name string
+}
+
+// Returns the price, see ` + "`fmt.Sprintf`" + `, ` + "`Item.Reset`" + ` or ` + "`README.md`" + `.
+func (i *Item) Price() string { return fmt.Sprint(1) }
+
+// Reset resets the item, see ` + "`Missing`" + `.
+//
+//goasciidoc:nolint unresolved-reference
+func (i *Item) Reset() {}
+
+// Levels are the levels.
+const (
+	Low  = 1
+	High = 2
+)
+
+//goasciidoc:nolint
+var Counter = 1
+
+// Open opens the shop.
+//
+// ----
+// shop.Open()
+func Open() {}
+
+// Close closes the shop.
+//
+// ====
+// ----
+// ====
+// ----
+func Close() {}
+`
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":       "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": lintSource,
+	})
+
+	p := NewProducer().
+		Module(dir).
+		Include(filepath.Join(dir, "shop")).
+		PackageDoc("_design/overview.adoc")
+
+	diagnostics, err := p.Lint()
+	require.NoError(t, err)
+
+	file := filepath.Join(dir, "shop", "shop.go")
+	assert.Equal(t, []Diagnostic{
+		{SeverityWarning, RuleMissingOverview, filepath.Join(dir, "shop", "_design", "overview.adoc"), 0, 0,
+			"package example.com/shop/shop has no overview, searched _design/overview.adoc"},
+		{SeverityWarning, RuleUnresolvedReference, file, 1, 45, "reference `Store` does not resolve to a symbol"},
+		{SeverityWarning, RuleMissingDoc, file, 6, 6, "exported Item has no documentation"},
+		{SeverityWarning, RuleDocPrefix, file, 11, 4, "documentation of Price should start with \"Price ...\""},
+		{SeverityError, RuleUnbalancedDelimiter, file, 30, 4, "delimited block ---- is never closed"},
+		{SeverityError, RuleUnbalancedDelimiter, file, 36, 4, "delimited block ==== is never closed"},
+	}, diagnostics)
+	assert.Equal(t, diagnostics, p.Diagnostics())
+
+	p.LintSeverity(RuleMissingDoc, SeverityOff).LintSeverity(RuleUnbalancedDelimiter, SeverityInfo)
+	diagnostics, err = p.Lint()
+	require.NoError(t, err)
+
+	require.Len(t, diagnostics, 5)
+	assert.Equal(t, RuleDocPrefix, diagnostics[2].Rule)
+	assert.Equal(t, SeverityInfo, diagnostics[3].Severity)
+}
+
+func TestUnclosedBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		expect []openBlock
+	}{
+		{"balanced", "----\ncode\n----\n\n|===\n|a\n|===", nil},
+		{"unclosed listing", "text\n----\ncode", []openBlock{{"----", "----", 0}}},
+		{"verbatim content", "....\n----\n....", nil},
+		{"nested", "====\n****\n****\n====", nil},
+		{"unclosed nested", "====\n****\n====", []openBlock{{"****", "****", 0}}},
+		{"fence", "```go\nx := 1\n```", nil},
+		{"open block", "--\ntext", []openBlock{{"--", "--", 0}}},
+		{"second occurrence", "----\na\n----\n----", []openBlock{{"----", "----", 2}}},
+		{"not a delimiter", "---\n-- x\n== Title", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.expect == nil {
+				assert.Empty(t, unclosedBlocks(tc.doc))
+				return
+			}
+
+			assert.Equal(t, tc.expect, unclosedBlocks(tc.doc))
+		})
+	}
+}
+
+func TestDocStartsWith(t *testing.T) {
+	assert.True(t, docStartsWith("Open opens.", "Open"))
+	assert.True(t, docStartsWith("An Item is an item.", "Item"))
+	assert.True(t, docStartsWith("Deprecated: use Close.", "Open"))
+	assert.True(t, docStartsWith("Open", "Open"))
+	assert.False(t, docStartsWith("OpenAll opens all.", "Open"))
+	assert.False(t, docStartsWith("Opens the shop.", "Open"))
+}
+
+func TestIdentIndex(t *testing.T) {
+	assert.Equal(t, 5, identIndex("type Item struct {", "Item"))
+	assert.Equal(t, 15, identIndex("func (i *Item) Price() string", "Price"))
+	assert.Equal(t, 12, identIndex("type Items, Item int", "Item"))
+	assert.Equal(t, 0, identIndex("Item", "Item"))
+	assert.Equal(t, -1, identIndex("type MyItem_2 int", "Item"))
+	assert.Equal(t, -1, identIndex("type Itemé int", "Item"))
+	assert.Equal(t, -1, identIndex("type Item int", ""))
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	diagnostics := []Diagnostic{
+		{SeverityError, RuleUnbalancedDelimiter, "shop/shop.go", 3, 4, "delimited block ---- is never closed"},
+		{SeverityInfo, "snippet", "", 0, 0, "no snippet"},
+	}
+
+	var text bytes.Buffer
+	require.NoError(t, WriteDiagnostics(&text, DiagnosticsText, diagnostics))
+	assert.Equal(t,
+		"shop/shop.go:3:4: error: delimited block ---- is never closed [unbalanced-delimiter]\ninfo: no snippet [snippet]\n",
+		text.String())
+
+	var data bytes.Buffer
+	require.NoError(t, WriteDiagnostics(&data, DiagnosticsJSON, diagnostics))
+	var decoded []Diagnostic
+	require.NoError(t, json.Unmarshal(data.Bytes(), &decoded))
+	assert.Equal(t, diagnostics, decoded)
+
+	data.Reset()
+	require.NoError(t, WriteDiagnostics(&data, DiagnosticsJSON, nil))
+	assert.Equal(t, "[]\n", data.String())
+
+	data.Reset()
+	require.NoError(t, WriteDiagnostics(&data, DiagnosticsSARIF, diagnostics))
+
+	var sarif sarifLog
+	require.NoError(t, json.Unmarshal(data.Bytes(), &sarif))
+	assert.Equal(t, "2.1.0", sarif.Version)
+	require.Len(t, sarif.Runs, 1)
+
+	run := sarif.Runs[0]
+	assert.Equal(t, "goasciidoc", run.Tool.Driver.Name)
+	assert.Len(t, run.Tool.Driver.Rules, len(LintRules())+1)
+	assert.Equal(t, "snippet", run.Tool.Driver.Rules[len(LintRules())].ID)
+
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, sarifResult{
+		RuleID:  RuleUnbalancedDelimiter,
+		Level:   "error",
+		Message: sarifText{Text: "delimited block ---- is never closed"},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifact{URI: "shop/shop.go"},
+			Region:           &sarifRegion{StartLine: 3, StartColumn: 4},
+		}}},
+	}, run.Results[0])
+	assert.Equal(t, "note", run.Results[1].Level)
+	assert.Empty(t, run.Results[1].Locations)
+
+	assert.Error(t, WriteDiagnostics(&data, "xml", diagnostics))
+}
@@ -0,0 +1,351 @@
+package asciidoc
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// macroRegex matches a ${gad:name[:arg]} macro.
+var macroRegex = regexp.MustCompile(`\$\{gad:([^}]*)\}`)
+
+// MacroContext is where a macro is expanded.
+type MacroContext struct {
+	// Package is the package being documented.
+	Package *goparser.GoPackage
+	// Module is the module of the package, nil when not module based.
+	Module *goparser.GoModule
+	// Current is the path of the ${gad:current:*} macros, i.e. the file of the documented
+	// symbol or the module root for the package documentation.
+	Current string
+	// Source is the path of the file the documentation is declared in.
+	Source string
+	// Line is the line, starting from 1, of the documented declaration or zero when unknown.
+	Line int
+}
+
+// MacroFunc expands a macro where arg is the text after the macro name, e.g. HOME in
+// ${gad:env:HOME}, and empty when there is none. When an error is returned, the macro is removed
+// and the error is reported as a warning.
+type MacroFunc func(ctx *MacroContext, arg string) (string, error)
+
+// MacroRegistry are the macros, by name, that are substituted in the documentation when macros
+// are enabled. A name may contain colons e.g. module:name.
+type MacroRegistry struct {
+	macros map[string]MacroFunc
+	// packages caches the files of the packages, by directory, the const macro has resolved.
+	packages map[string][]*goparser.GoFile
+	// git caches the git output, by directory and arguments.
+	git map[string]string
+}
+
+// NewMacroRegistry creates a registry with the built-in macros.
+//
+// .Built-in Macros
+// |===
+// |Macro |Expands to
+//
+// |current:fq |The path of the current file.
+// |current:fqdir |The directory of the current file.
+// |current:dir |The name of the directory of the current file.
+// |current:file |The name of the current file.
+// |snippet:path#region |A source block of a tag region, or Go declaration, of the file.
+// |module:name |The name of the module.
+// |module:version |The version of the module.
+// |module:go |The go version of the module.
+// |package:name |The name of the current package.
+// |package:path |The import path of the current package.
+// |const:[pkg.]Name |The value of a constant.
+// |git:commit, git:short, git:tag, git:branch |The commit, short commit, latest tag and branch.
+// |date[:layout] |The current, or SOURCE_DATE_EPOCH, date (default layout 2006-01-02).
+// |env:VAR |The value of the environment variable.
+// |===
+func NewMacroRegistry() *MacroRegistry {
+	r := &MacroRegistry{macros: map[string]MacroFunc{}}
+
+	r.Register("current:fq", func(ctx *MacroContext, _ string) (string, error) {
+		return ctx.Current, nil
+	})
+	r.Register("current:fqdir", func(ctx *MacroContext, _ string) (string, error) {
+		return filepath.Dir(ctx.Current), nil
+	})
+	r.Register("current:dir", func(ctx *MacroContext, _ string) (string, error) {
+		return filepath.Base(filepath.Dir(ctx.Current)), nil
+	})
+	r.Register("current:file", func(ctx *MacroContext, _ string) (string, error) {
+		return filepath.Base(ctx.Current), nil
+	})
+	r.Register("snippet", func(ctx *MacroContext, arg string) (string, error) {
+		path, region, _ := strings.Cut(arg, "#")
+		return snippet(strings.TrimSpace(path), strings.TrimSpace(region), ctx.snippetDirs())
+	})
+	r.Register("module:name", func(ctx *MacroContext, _ string) (string, error) {
+		return ctx.module(func(m *goparser.GoModule) string { return m.Name })
+	})
+	r.Register("module:version", func(ctx *MacroContext, _ string) (string, error) {
+		return ctx.module(func(m *goparser.GoModule) string { return m.Version })
+	})
+	r.Register("module:go", func(ctx *MacroContext, _ string) (string, error) {
+		return ctx.module(func(m *goparser.GoModule) string { return m.GoVersion })
+	})
+	r.Register("package:name", func(ctx *MacroContext, _ string) (string, error) {
+		return ctx.Package.Package, nil
+	})
+	r.Register("package:path", func(ctx *MacroContext, _ string) (string, error) {
+		return ctx.Package.FqPackage, nil
+	})
+	r.Register("const", r.constant)
+	for name, args := range map[string][]string{
+		"git:commit": {"rev-parse", "HEAD"},
+		"git:short":  {"rev-parse", "--short", "HEAD"},
+		"git:tag":    {"describe", "--tags", "--abbrev=0"},
+		"git:branch": {"rev-parse", "--abbrev-ref", "HEAD"},
+	} {
+		args := args
+		r.Register(name, func(ctx *MacroContext, _ string) (string, error) {
+			return r.runGit(filepath.Dir(ctx.Source), args...)
+		})
+	}
+	r.Register("date", func(_ *MacroContext, layout string) (string, error) {
+		if layout == "" {
+			layout = "2006-01-02"
+		}
+
+		return buildTime().Format(layout), nil
+	})
+	r.Register("env", func(_ *MacroContext, name string) (string, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+
+		return value, nil
+	})
+
+	return r
+}
+
+// Register registers, or replaces, the macro with the name e.g. team or team:owner. It is used
+// as ${gad:team} or, with an argument, ${gad:team:payments}.
+func (r *MacroRegistry) Register(name string, fn MacroFunc) *MacroRegistry {
+	r.macros[name] = fn
+	return r
+}
+
+// Names returns the names of the registered macros, sorted.
+func (r *MacroRegistry) Names() []string {
+	names := make([]string, 0, len(r.macros))
+	for name := range r.macros {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// lookup returns the macro, with the longest registered name, the body, e.g. module:name or
+// env:HOME, starts with and its argument.
+func (r *MacroRegistry) lookup(body string) (string, MacroFunc, string, bool) {
+	if fn, ok := r.macros[body]; ok {
+		return body, fn, "", true
+	}
+
+	for i := len(body) - 1; i > 0; i-- {
+		if body[i] != ':' {
+			continue
+		}
+
+		if fn, ok := r.macros[body[:i]]; ok {
+			return body[:i], fn, body[i+1:], true
+		}
+	}
+
+	return "", nil, "", false
+}
+
+// Expand substitutes the macros in the doc. Unknown macros are kept as is and, as the errors of
+// the macros, passed to warn with the name of the macro ("macro" when unknown).
+func (r *MacroRegistry) Expand(doc string, ctx *MacroContext, warn func(name string, err error)) string {
+	matches := macroRegex.FindAllStringSubmatchIndex(doc, -1)
+	if len(matches) == 0 {
+		return doc
+	}
+
+	var (
+		b    strings.Builder
+		last int
+	)
+
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		before := doc[last:start]
+		last = end
+
+		name, fn, arg, ok := r.lookup(doc[m[2]:m[3]])
+		if !ok {
+			b.WriteString(doc[start-len(before) : end])
+			warn("macro", fmt.Errorf("unknown macro %s", doc[start:end]))
+			continue
+		}
+
+		value, err := fn(ctx, arg)
+		if err != nil {
+			b.WriteString(before)
+			warn(name, err)
+			continue
+		}
+
+		// A multi line value, e.g. a source block, must start, and end, on a line of its own.
+		if strings.Contains(value, "\n") {
+			if start > 0 && doc[start-1] != '\n' {
+				before = strings.TrimRight(before, " \t") + "\n"
+			}
+			if end < len(doc) && doc[end] != '\n' {
+				value += "\n"
+			}
+		}
+
+		b.WriteString(before)
+		b.WriteString(value)
+	}
+
+	b.WriteString(doc[last:])
+	return b.String()
+}
+
+// snippetDirs returns the directories snippets are resolved relative, i.e. the package and the
+// module root.
+func (ctx *MacroContext) snippetDirs() []string {
+	dirs := []string{filepath.Dir(ctx.Source)}
+	if ctx.Module != nil {
+		dirs = append(dirs, ctx.Module.Base)
+	}
+
+	return dirs
+}
+
+// module returns the module property or an error when there is no module, or it is not set.
+func (ctx *MacroContext) module(property func(m *goparser.GoModule) string) (string, error) {
+	if ctx.Module == nil {
+		return "", fmt.Errorf("no module")
+	}
+
+	value := property(ctx.Module)
+	if value == "" {
+		return "", fmt.Errorf("not set in module %s", ctx.Module.Name)
+	}
+
+	return value, nil
+}
+
+// constant expands ${gad:const:[pkg.]Name} to the value of the constant. The package is the name
+// of the current package, the import path of a package in the module or its path relative the
+// module root. Without a package it is the current package.
+func (r *MacroRegistry) constant(ctx *MacroContext, arg string) (string, error) {
+	pkg, name := "", arg
+	if i := strings.LastIndex(arg, "."); i >= 0 {
+		pkg, name = arg[:i], arg[i+1:]
+	}
+
+	var consts []*goparser.GoAssignment
+	if pkg == "" || pkg == ctx.Package.Package || pkg == ctx.Package.FqPackage {
+		consts = ctx.Package.ConstAssignments
+	} else {
+		files, err := r.packageFiles(ctx.Module, pkg)
+		if err != nil {
+			return "", err
+		}
+
+		for _, f := range files {
+			consts = append(consts, f.ConstAssignments...)
+		}
+	}
+
+	for _, c := range consts {
+		if c.Name == name {
+			if c.Value == "" {
+				return "", fmt.Errorf("value of constant %s is unknown", arg)
+			}
+
+			return c.Value, nil
+		}
+	}
+
+	return "", fmt.Errorf("no constant %s", arg)
+}
+
+// packageFiles parses the non test files of the package in the module.
+func (r *MacroRegistry) packageFiles(mod *goparser.GoModule, pkg string) ([]*goparser.GoFile, error) {
+	if mod == nil {
+		return nil, fmt.Errorf("no module to resolve package %s in", pkg)
+	}
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(pkg, mod.Name), "/")
+	dir := filepath.Join(mod.Base, filepath.FromSlash(rel))
+	if files, ok := r.packages[dir]; ok {
+		return files, nil
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	var sources []string
+	for _, path := range paths {
+		if !strings.HasSuffix(path, "_test.go") {
+			sources = append(sources, path)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("no package %s in module %s", pkg, mod.Name)
+	}
+
+	files, err := goparser.NewParser(goparser.WithModule(mod)).ParseFiles(sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.packages == nil {
+		r.packages = map[string][]*goparser.GoFile{}
+	}
+	r.packages[dir] = files
+
+	return files, nil
+}
+
+// runGit runs git in the directory and returns its trimmed output.
+func (r *MacroRegistry) runGit(dir string, args ...string) (string, error) {
+	key := dir + "\x00" + strings.Join(args, " ")
+	if out, ok := r.git[key]; ok {
+		return out, nil
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+
+	if r.git == nil {
+		r.git = map[string]string{}
+	}
+	r.git[key] = strings.TrimSpace(string(out))
+
+	return r.git[key], nil
+}
+
+// buildTime returns the time of SOURCE_DATE_EPOCH, for reproducible builds, or the current time.
+func buildTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+
+	return time.Now()
+}
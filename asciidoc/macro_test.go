@@ -0,0 +1,113 @@
+package asciidoc
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroRegistry(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":                "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go":          "package shop\n\n// Name is the name.\nconst Name = \"shop\"\n",
+		"shop/limits/limits.go": "package limits\n\nconst (\n\tMaxItems = 10\n\tRatio    = 0.5\n)\n",
+	})
+
+	mod, err := goparser.NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+	mod.Version = "v1.2.0"
+
+	files, err := goparser.NewParser(goparser.WithModule(mod)).ParseFiles(filepath.Join(dir, "shop", "shop.go"))
+	require.NoError(t, err)
+
+	pkg := &goparser.GoPackage{GoFile: *files[0]}
+	source := filepath.Join(dir, "shop", "shop.go")
+
+	t.Setenv("GAD_TEST_OWNER", "payments")
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	registry := NewMacroRegistry().
+		Register("team", func(_ *MacroContext, arg string) (string, error) {
+			if arg == "" {
+				return "all", nil
+			}
+			return "team " + arg, nil
+		}).
+		Register("fail", func(*MacroContext, string) (string, error) {
+			return "", errors.New("failed")
+		})
+
+	tests := []struct {
+		name   string
+		doc    string
+		expect string
+		warn   string
+	}{
+		{"current", "${gad:current:dir}/${gad:current:file}", "shop/shop.go", ""},
+		{"module", "${gad:module:name}@${gad:module:version} go${gad:module:go}", "example.com/shop@v1.2.0 go1.21", ""},
+		{"package", "${gad:package:name} ${gad:package:path}", "shop example.com/shop/shop", ""},
+		{"const in current package", "${gad:const:Name} ${gad:const:shop.Name}", "shop shop", ""},
+		{"const by import path", "max ${gad:const:example.com/shop/shop/limits.MaxItems}", "max 10", ""},
+		{"const by relative path", "ratio ${gad:const:shop/limits.Ratio}", "ratio 0.5", ""},
+		{"missing const", "x${gad:const:Missing}", "x", "const: no constant Missing"},
+		{"date", "${gad:date} ${gad:date:2006}", "2023-11-14 2023", ""},
+		{"env", "owner ${gad:env:GAD_TEST_OWNER}", "owner payments", ""},
+		{"unset env", "${gad:env:GAD_TEST_UNSET}", "", "env: environment variable GAD_TEST_UNSET is not set"},
+		{"custom", "${gad:team} ${gad:team:orders}", "all team orders", ""},
+		{"custom error", "a ${gad:fail} b", "a  b", "fail: failed"},
+		{"unknown", "keep ${gad:nope:x}", "keep ${gad:nope:x}", "macro: unknown macro ${gad:nope:x}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings []string
+			ctx := &MacroContext{Package: pkg, Module: mod, Current: source, Source: source, Line: 4}
+
+			doc := registry.Expand(tc.doc, ctx, func(name string, err error) {
+				warnings = append(warnings, name+": "+err.Error())
+			})
+
+			assert.Equal(t, tc.expect, doc)
+			if tc.warn == "" {
+				assert.Empty(t, warnings)
+			} else {
+				assert.Equal(t, []string{tc.warn}, warnings)
+			}
+		})
+	}
+
+	assert.Contains(t, registry.Names(), "team")
+	assert.Contains(t, registry.Names(), "git:commit")
+}
+
+func TestUnknownMacroIsReported(t *testing.T) {
+	file := &goparser.GoFile{FilePath: "/src/shop/shop.go"}
+	pkg := &goparser.GoPackage{
+		GoFile: goparser.GoFile{
+			StructMethods: []*goparser.GoStructMethod{{GoMethod: goparser.GoMethod{
+				File: file,
+				Line: 7,
+				Doc:  "Reset in ${gad:current:file} by ${gad:owner}",
+			}}},
+		},
+	}
+
+	var diagnostics []Diagnostic
+	require.NoError(t, getProcessMacroFunc(goparser.ParseConfig{}, nil, func(d Diagnostic) {
+		diagnostics = append(diagnostics, d)
+	}, func(*goparser.GoPackage) error { return nil })(pkg))
+
+	assert.Equal(t, "Reset in shop.go by ${gad:owner}", pkg.StructMethods[0].Doc)
+	assert.Equal(t, []Diagnostic{{
+		Severity: SeverityWarning,
+		Rule:     "macro",
+		File:     file.FilePath,
+		Line:     7,
+		Message:  "unknown macro ${gad:owner}",
+	}}, diagnostics)
+}
@@ -0,0 +1,191 @@
+package asciidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// SymbolManifest maps the fully qualified symbols, e.g. github.com/acme/lib/auth.Token, of a
+// documentation run onto the URL of the section that documents them. Other projects load the
+// manifest to link to this documentation instead of pkg.go.dev.
+type SymbolManifest struct {
+	// Module is the name of the documented module (if any).
+	Module string `json:"module,omitempty"`
+	// BaseURL is where the documentation is published, empty when the documents are referred
+	// relative to the manifest.
+	BaseURL string `json:"base_url,omitempty"`
+	// Symbols maps the fully qualified symbol onto where it is documented.
+	Symbols map[string]ManifestSymbol `json:"symbols"`
+
+	// dir is the directory of a loaded manifest, relative document URLs are relative to it.
+	dir string
+}
+
+// ManifestSymbol is where a symbol is documented.
+type ManifestSymbol struct {
+	// Kind is type, function or method.
+	Kind string `json:"kind"`
+	// URL is the document URL including the anchor e.g. https://docs.acme.io/lib/docs.html#anchor.
+	URL string `json:"url"`
+	// Document is the document, relative the manifest, that contains the symbol.
+	Document string `json:"document,omitempty"`
+	// Anchor is the anchor of the section that documents the symbol (methods use their type).
+	Anchor string `json:"anchor"`
+}
+
+// NewSymbolManifest creates an empty manifest. When baseURL is set, e.g.
+// https://docs.acme.io/lib/, the symbol URLs are absolute and refer the _.html_ documents
+// that asciidoctor renders. Otherwise they refer the _.adoc_ documents relative the manifest.
+func NewSymbolManifest(module, baseURL string) *SymbolManifest {
+	return &SymbolManifest{
+		Module:  module,
+		BaseURL: baseURL,
+		Symbols: map[string]ManifestSymbol{},
+	}
+}
+
+// LoadSymbolManifest loads a manifest from a local path or a file URL e.g.
+// file:///srv/docs/lib/symbols.json.
+func LoadSymbolManifest(location string) (*SymbolManifest, error) {
+	path := location
+	if strings.HasPrefix(location, "file:") {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest url %q: %w", location, err)
+		}
+		path = u.Path
+	} else if strings.Contains(location, "://") {
+		return nil, fmt.Errorf("unsupported manifest location %q (use a path or a file url)", location)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest %q: %w", location, err)
+	}
+
+	var manifest SymbolManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest %q: %w", location, err)
+	}
+
+	if abs, err := filepath.Abs(filepath.Dir(path)); err == nil {
+		manifest.dir = abs
+	}
+
+	return &manifest, nil
+}
+
+// Write writes the manifest as JSON to path.
+func (m *SymbolManifest) Write(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(path); !dirExists(dir) {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// AddPackage adds all types, functions and methods of the package documented in the document,
+// a path relative the manifest. Unexported symbols are only added when private is set.
+func (m *SymbolManifest) AddPackage(pkg *goparser.GoPackage, document string, private bool) {
+	t := &TemplateContext{File: &pkg.GoFile, Package: pkg, Module: pkg.Module}
+
+	add := func(kind, name, owner string, file *goparser.GoFile) {
+		pkgPath := t.packagePathForFile(file)
+		if pkgPath == "" {
+			return
+		}
+
+		anchor := anchorID(pkgPath, owner)
+		m.Symbols[pkgPath+"."+name] = ManifestSymbol{
+			Kind:     kind,
+			URL:      m.documentURL(document) + "#" + anchor,
+			Document: document,
+			Anchor:   anchor,
+		}
+	}
+
+	for _, s := range pkg.Structs {
+		if (s.Exported || private) && !s.Directives.Ignored() {
+			add("type", s.Name, s.Name, s.File)
+		}
+	}
+	for _, i := range pkg.Interfaces {
+		if (i.Exported || private) && !i.Directives.Ignored() {
+			add("type", i.Name, i.Name, i.File)
+		}
+	}
+	for _, c := range pkg.CustomTypes {
+		if (c.Exported || private) && !c.Directives.Ignored() {
+			add("type", c.Name, c.Name, c.File)
+		}
+	}
+	for _, f := range pkg.CustomFuncs {
+		if (f.Exported || private) && !f.Directives.Ignored() {
+			add("type", f.Name, f.Name, f.File)
+		}
+	}
+	for _, fn := range pkg.StructMethods {
+		if !(fn.Exported || private) || fn.Directives.Ignored() {
+			continue
+		}
+
+		if len(fn.ReceiverTypes) == 0 {
+			add("function", fn.Name, fn.Name, fn.File)
+			continue
+		}
+
+		owner := baseTypeIdentifier(fn.ReceiverTypes[0].Type)
+		if owner != "" {
+			add("method", owner+"."+fn.Name, owner, fn.File)
+		}
+	}
+}
+
+// documentURL returns the URL of a document, relative the manifest, using the base URL.
+func (m *SymbolManifest) documentURL(document string) string {
+	document = filepath.ToSlash(document)
+	if m.BaseURL == "" {
+		return document
+	}
+
+	if ext := filepath.Ext(document); ext == ".adoc" || ext == ".asciidoc" {
+		document = strings.TrimSuffix(document, ext) + ".html"
+	}
+
+	return strings.TrimSuffix(m.BaseURL, "/") + "/" + strings.TrimPrefix(document, "/")
+}
+
+// resolvedSymbols returns the symbol URLs of the manifest. Relative URLs, i.e. manifests
+// without base URL, are made relative to the directory dir where the links are rendered.
+func (m *SymbolManifest) resolvedSymbols(dir string) map[string]string {
+	symbols := make(map[string]string, len(m.Symbols))
+	for name, symbol := range m.Symbols {
+		link := symbol.URL
+		if !strings.Contains(link, "://") && m.dir != "" {
+			doc, anchor, _ := strings.Cut(link, "#")
+			doc = filepath.Join(m.dir, filepath.FromSlash(doc))
+			if dir != "" {
+				if rel, err := filepath.Rel(dir, doc); err == nil {
+					doc = rel
+				}
+			}
+			link = filepath.ToSlash(doc) + "#" + anchor
+		}
+		symbols[name] = link
+	}
+
+	return symbols
+}
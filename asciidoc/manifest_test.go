@@ -0,0 +1,111 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestSymbolManifestCrossProjectLinks(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, filepath.Join(root, "lib"), map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.21\n",
+		"auth/auth.go": `package auth
+
+// Token is an access token.
+type Token string
+
+// Verify verifies the token.
+func (t Token) Verify() error { return nil }
+
+// Issue issues a token.
+func Issue() Token { return "" }
+
+type secret struct{}
+`,
+	})
+	writeFiles(t, filepath.Join(root, "app"), map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.21\n",
+		"svc/svc.go": `package svc
+
+import "example.com/lib/auth"
+
+// Session holds the token, see ` + "`auth.Token.Verify`" + `.
+type Session struct {
+	// Token is the session token.
+	Token auth.Token
+}
+`,
+	})
+
+	libDocs := filepath.Join(root, "docs", "lib", "docs.adoc")
+	manifestPath := filepath.Join(root, "docs", "lib", "symbols.json")
+
+	NewProducer().
+		Module(filepath.Join(root, "lib")).
+		Include(filepath.Join(root, "lib")).
+		Outfile(libDocs).
+		SymbolManifest(manifestPath, "").
+		Generate()
+
+	manifest, err := LoadSymbolManifest("file://" + filepath.ToSlash(manifestPath))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/lib", manifest.Module)
+	assert.Equal(t, ManifestSymbol{
+		Kind:     "type",
+		URL:      "docs.adoc#example-com-lib-auth-Token",
+		Document: "docs.adoc",
+		Anchor:   "example-com-lib-auth-Token",
+	}, manifest.Symbols["example.com/lib/auth.Token"])
+	assert.Equal(t, "docs.adoc#example-com-lib-auth-Issue", manifest.Symbols["example.com/lib/auth.Issue"].URL)
+	assert.Equal(t, "method", manifest.Symbols["example.com/lib/auth.Token.Verify"].Kind)
+	assert.NotContains(t, manifest.Symbols, "example.com/lib/auth.secret")
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(filepath.Join(root, "app")).
+		Include(filepath.Join(root, "app")).
+		Outfile(filepath.Join(root, "docs", "app", "docs.adoc")).
+		NoIndex().
+		TypeLinks(TypeLinksInternalExternal).
+		LinkManifests(manifest)
+
+	overrideAllDefaults(t, p)
+	p.Generate()
+
+	doc := buff.String()
+	assert.Contains(t, doc, "link:../lib/docs.adoc#example-com-lib-auth-Token[auth.Token]")
+	assert.Contains(t, doc, "link:../lib/docs.adoc#example-com-lib-auth-Token[auth.Token.Verify]")
+	assert.NotContains(t, doc, "pkg.go.dev/example.com/lib")
+}
+
+func TestSymbolManifestBaseURL(t *testing.T) {
+	m := NewSymbolManifest("example.com/lib", "https://docs.acme.io/lib/")
+	assert.Equal(t, "https://docs.acme.io/lib/api/docs.html", m.documentURL("api/docs.adoc"))
+
+	resolver := NewExternalLinkResolver().withManifests([]*SymbolManifest{{
+		Symbols: map[string]ManifestSymbol{
+			"example.com/lib/auth.Token": {URL: "https://docs.acme.io/lib/docs.html#example-com-lib-auth-Token"},
+		},
+	}}, "")
+
+	assert.Equal(t, "https://docs.acme.io/lib/docs.html#example-com-lib-auth-Token", resolver.URL(nil, "example.com/lib/auth", "Token"))
+	assert.Equal(t, "https://pkg.go.dev/example.com/lib/auth#Other", resolver.URL(nil, "example.com/lib/auth", "Other"))
+
+	_, err := LoadSymbolManifest("https://docs.acme.io/lib/symbols.json")
+	assert.Error(t, err)
+}
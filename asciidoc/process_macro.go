@@ -1,7 +1,6 @@
 package asciidoc
 
 import (
-	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -21,23 +20,29 @@ var (
 	mdHeadingExtractRegex = regexp.MustCompile(`^[ \t]*#{1,6}[ \t]*(.*)$`)
 )
 
-// getProcessMacroFunc returns the macro substitution function.
+// getProcessMacroFunc returns the macro substitution function. The macros are expanded using
+// the registry, or the built-in macros when nil. Problems, e.g. an unknown macro or a snippet
+// that cannot be found, are reported to report unless nil.
 func getProcessMacroFunc(
 	config goparser.ParseConfig,
+	macros *MacroRegistry,
+	report func(Diagnostic),
 	next goparser.ParseSinglePackageWalkerFunc) goparser.ParseSinglePackageWalkerFunc {
 
-	return func(pkg *goparser.GoPackage) error {
+	if macros == nil {
+		macros = NewMacroRegistry()
+	}
 
-		processDocs := func(doc, fq string) string {
+	return func(pkg *goparser.GoPackage) error {
 
-			fqdir := filepath.Dir(fq)
-			dir := filepath.Base(fqdir)
-			file := filepath.Base(fq)
+		root := ""
+		if pkg.Module != nil {
+			root = pkg.Module.Base
+		}
 
-			doc = strings.ReplaceAll(doc, "${gad:current:fq}", fq)
-			doc = strings.ReplaceAll(doc, "${gad:current:fqdir}", fqdir)
-			doc = strings.ReplaceAll(doc, "${gad:current:dir}", dir)
-			doc = strings.ReplaceAll(doc, "${gad:current:file}", file)
+		// processDocsAt substitutes the macros, where fq is the current path, in the doc declared
+		// at line in the source file.
+		processDocsAt := func(doc, fq, source string, line int) string {
 
 			doc = asciidocTagRegex.ReplaceAllString(doc, "")
 
@@ -53,54 +58,93 @@ func getProcessMacroFunc(
 				})
 			}
 
-			return doc
+			// Last, so the macro values, e.g. snippets, are inlined as is.
+			ctx := &MacroContext{
+				Package: pkg,
+				Module:  pkg.Module,
+				Current: fq,
+				Source:  source,
+				Line:    line,
+			}
+
+			return macros.Expand(doc, ctx, func(name string, err error) {
+				if report != nil {
+					report(Diagnostic{
+						Severity: SeverityWarning,
+						Rule:     name,
+						File:     source,
+						Line:     line,
+						Message:  err.Error(),
+					})
+				}
+			})
+		}
+
+		processDocs := func(doc string, file *goparser.GoFile, line int) string {
+			return processDocsAt(doc, file.FilePath, file.FilePath, line)
 		}
 
-		pkg.Doc = processDocs(pkg.Doc, pkg.Module.Base)
+		pkg.Doc = processDocsAt(pkg.Doc, root, packageDocFile(pkg), 0)
 
 		for _, c := range pkg.ConstAssignments {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, c.Line)
 		}
 
 		for _, c := range pkg.CustomFuncs {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, c.Line)
 		}
 
 		for _, c := range pkg.CustomTypes {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, c.Line)
 		}
 
 		for _, c := range pkg.Imports {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, 0)
 		}
 
 		for _, c := range pkg.Interfaces {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, c.Line)
 
 			for _, c := range c.Methods {
-				c.Doc = processDocs(c.Doc, c.File.FilePath)
+				c.Doc = processDocs(c.Doc, c.File, c.Line)
 			}
 
 		}
 
 		for _, c := range pkg.StructMethods {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, c.Line)
 		}
 
 		for _, c := range pkg.Structs {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, c.Line)
 
 			for _, c := range c.Fields {
-				c.Doc = processDocs(c.Doc, c.File.FilePath)
+				c.Doc = processDocs(c.Doc, c.File, 0)
 			}
 
 		}
 
 		for _, c := range pkg.VarAssignments {
-			c.Doc = processDocs(c.Doc, c.File.FilePath)
+			c.Doc = processDocs(c.Doc, c.File, c.Line)
 		}
 
 		return next(pkg)
 	}
 
 }
+
+// packageDocFile returns the path of the file with the package doc comment, or the first file of
+// the package when none has one. A package without files returns its path.
+func packageDocFile(pkg *goparser.GoPackage) string {
+	for _, file := range pkg.Files {
+		if file.Doc != "" {
+			return file.FilePath
+		}
+	}
+
+	if len(pkg.Files) > 0 {
+		return pkg.Files[0].FilePath
+	}
+
+	return pkg.FilePath
+}
@@ -160,7 +160,7 @@ func TestIgnoreMarkdownHeadings(t *testing.T) {
 			}
 
 			// Get the process function and execute it
-			processFunc := getProcessMacroFunc(config, next)
+			processFunc := getProcessMacroFunc(config, nil, nil, next)
 			err := processFunc(pkg)
 			if err != nil {
 				t.Fatalf("processFunc returned error: %v", err)
@@ -214,7 +214,7 @@ func TestIgnoreMarkdownHeadingsOnStructFields(t *testing.T) {
 		return nil
 	}
 
-	processFunc := getProcessMacroFunc(config, next)
+	processFunc := getProcessMacroFunc(config, nil, nil, next)
 	err := processFunc(pkg)
 	if err != nil {
 		t.Fatalf("processFunc returned error: %v", err)
@@ -282,7 +282,7 @@ func TestIgnoreMarkdownHeadingsOnMethods(t *testing.T) {
 		return nil
 	}
 
-	processFunc := getProcessMacroFunc(config, next)
+	processFunc := getProcessMacroFunc(config, nil, nil, next)
 	err := processFunc(pkg)
 	if err != nil {
 		t.Fatalf("processFunc returned error: %v", err)
@@ -368,7 +368,7 @@ See below for examples.
 		return nil
 	}
 
-	processFunc := getProcessMacroFunc(config, next)
+	processFunc := getProcessMacroFunc(config, nil, nil, next)
 	err := processFunc(pkg)
 	if err != nil {
 		t.Fatalf("processFunc returned error: %v", err)
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	texttemplate "text/template"
 
 	"github.com/mariotoffia/goasciidoc/goparser"
 )
@@ -64,18 +65,69 @@ type Producer struct {
 	// macro determine if a additional pass is done to substitute ${goasciidoc:macroname:...} with
 	// corresponding values.
 	macro bool
+	// macros are the macros substituted when macro is enabled, nil for the built-in macros.
+	macros *MacroRegistry
+	// lintSeverities overrides the default severities of the lint rules, by rule.
+	lintSeverities map[string]Severity
 	// typeLinks controls linking behaviour for referenced types.
 	typeLinks TypeLinkMode
 	// signatureStyle controls how signatures are rendered.
 	signatureStyle string
 	// highlighter controls which source highlighter attribute is emitted in the index header.
 	highlighter string
-	// renderOptions controls what examples to render (struct-json, struct-yaml).
+	// renderOptions controls what examples to render (struct-json, struct-yaml, struct-xml, struct-toml, struct-jsonschema).
 	renderOptions map[string]bool
+	// schemaDir is where standalone JSON Schema documents are written, empty disables.
+	schemaDir string
+	// testReportDir is where the test report of each package is written, empty disables.
+	testReportDir string
+	// examples is the registry of example values for well-known types, nil uses the built-in.
+	examples *goparser.ExampleRegistry
 	// subModuleMode controls how submodules are processed
 	subModuleMode SubModuleMode
 	// packageMode controls how packages are processed and rendered
 	packageMode PackageMode
+	// methodCode renders the implementation of all functions and methods.
+	methodCode bool
+	// layout controls how the symbols of a package are organised
+	layout LayoutMode
+	// sortOrder controls the order of the symbols within a section
+	sortOrder SortOrder
+	// usedBy controls which references are listed in the "Used By" sections of types.
+	usedBy UsedByMode
+	// usedByIndex is the reverse reference index built when generating.
+	usedByIndex UsedByIndex
+	// externalLinks resolves the URLs of external packages and symbols, nil uses pkg.go.dev.
+	externalLinks *ExternalLinkResolver
+	// manifests are symbol manifests of other projects that external references link to.
+	manifests []*SymbolManifest
+	// manifestPath is where the symbol manifest of this run is written, empty disables.
+	manifestPath string
+	// manifestBaseURL is the URL where the documentation of this run is published.
+	manifestBaseURL string
+	// manifest is the symbol manifest built when generating.
+	manifest *SymbolManifest
+	// history enables the "Since" badges derived from the git tags of the module.
+	history bool
+	// historyIndex is the git tag history, keyed by module name, built when generating.
+	historyIndex map[string]goparser.APIHistory
+	// templateFuncs are user registered functions available in all templates.
+	templateFuncs texttemplate.FuncMap
+	// templateData are user registered named values available in all templates.
+	templateData map[string]interface{}
+	// partials are shared templates, per name, parsed into the namespace of every template.
+	partials map[string]string
+	// extensions are, per template name, overrides that only redefine {{block}} sections.
+	extensions map[string][]string
+	// filtered are the fully qualified names of the symbols removed by the symbol filter.
+	filtered map[string]struct{}
+	// diagnostics are the problems found when generating.
+	diagnostics []Diagnostic
+	// diagnosticsPath is where the diagnostics of a run are written as JSON, empty disables.
+	diagnosticsPath string
+	// diagnosticsAppendix is where the diagnostics of a run are written as an asciidoc appendix,
+	// empty disables.
+	diagnosticsAppendix string
 }
 
 // NewProducer creates a new instance of a producer.
@@ -124,6 +176,17 @@ func (p *Producer) EnableMacro() *Producer {
 	return p
 }
 
+// Macro registers, or replaces, the macro with the name, see MacroRegistry.Register, in addition
+// to the built-in macros. The macros are substituted when enabled using EnableMacro.
+func (p *Producer) Macro(name string, fn MacroFunc) *Producer {
+	if p.macros == nil {
+		p.macros = NewMacroRegistry()
+	}
+
+	p.macros.Register(name, fn)
+	return p
+}
+
 // NonExported will set renderer to render all Symbols both
 // exported and non exported. By default only exported symbols
 // are rendered.
@@ -162,11 +225,58 @@ func (p *Producer) OverrideFilePath(name, path string) *Producer {
 
 // Override will use another template instead of a built-in default
 // for the particular name (see TemplateType for valid template names)
+//
+// If a template is already set and the template only consists of {{define}} blocks, see
+// IsTemplateExtension, it extends the current template by redefining its {{block}} sections
+// instead of replacing it.
 func (p *Producer) Override(name, template string) *Producer {
+	if _, ok := p.overrides[name]; ok && IsTemplateExtension(template) {
+		if p.extensions == nil {
+			p.extensions = map[string][]string{}
+		}
+
+		p.extensions[name] = append(p.extensions[name], template)
+		return p
+	}
+
 	p.overrides[name] = template
 	return p
 }
 
+// PartialFilePath loads a partial from path, see Partial.
+func (p *Producer) PartialFilePath(name, path string) *Producer {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+
+	return p.Partial(name, string(data))
+}
+
+// Partial registers a shared template that is parsed into the namespace of every template.
+// It is used as {{template "name" .}} and may {{define}} further templates, e.g. a common
+// signature or anchor snippet. Partials cannot replace the {{block}} sections of a template,
+// use an extension override for that.
+func (p *Producer) Partial(name, template string) *Producer {
+	if p.partials == nil {
+		p.partials = map[string]string{}
+	}
+
+	p.partials[name] = template
+	return p
+}
+
+// Partials returns a copy of the registered partials, by name.
+func (p *Producer) Partials() map[string]string {
+	partials := make(map[string]string, len(p.partials))
+	for name, text := range p.partials {
+		partials[name] = text
+	}
+
+	return partials
+}
+
 // Outfile sets a file to write to
 func (p *Producer) Outfile(path string) *Producer {
 	p.outfile = path
@@ -250,18 +360,111 @@ func (p *Producer) PackageMode(mode PackageMode) *Producer {
 	return p
 }
 
+// IncludeMethodCode renders the implementation of all functions and methods as collapsible
+// blocks. Use a //goasciidoc:code directive, in the package doc comment or on a function, to
+// render it for a package or a single function (and //goasciidoc:code off to skip it).
+func (p *Producer) IncludeMethodCode() *Producer {
+	p.methodCode = true
+	return p
+}
+
+// Layout configures how the symbols of a package are organised. LayoutGodoc renders, as godoc
+// does, each type followed by its constants, variables, constructors and methods.
+func (p *Producer) Layout(mode LayoutMode) *Producer {
+	p.layout = mode
+	return p
+}
+
+// SortOrder configures the order of the symbols within each section (source, alphabetical or
+// grouped by associated type). A //goasciidoc:order directive always takes precedence.
+func (p *Producer) SortOrder(order SortOrder) *Producer {
+	p.sortOrder = order
+	return p
+}
+
 // TypeLinks configures how type references are rendered inside the generated documentation.
 func (p *Producer) TypeLinks(mode TypeLinkMode) *Producer {
 	p.typeLinks = mode
 	return p
 }
 
+// UsedBy renders a "Used By" section under each struct, interface and custom type that lists
+// the functions, methods, fields, interface methods and types using it. Use UsedByWorkspace to
+// include references from other modules in the workspace.
+func (p *Producer) UsedBy(mode UsedByMode) *Producer {
+	p.usedBy = mode
+	return p
+}
+
+// ExternalLinks adds rules, tried in order before the default pkg.go.dev link, that map
+// external import paths onto URL templates e.g. corp.example.com/* -> https://godoc.corp/{path}#{symbol}.
+func (p *Producer) ExternalLinks(rules ...ExternalLinkRule) *Producer {
+	p.externalLinks = NewExternalLinkResolver(append(p.externalLinks.Rules(), rules...)...)
+	return p
+}
+
+// SymbolManifest writes a JSON manifest to path that maps each documented symbol onto the URL,
+// and anchor, where it is documented. When baseURL is set, e.g. https://docs.acme.io/lib/, the
+// URLs refer the published _.html_ documents, otherwise the documents relative the manifest.
+func (p *Producer) SymbolManifest(path, baseURL string) *Producer {
+	p.manifestPath = path
+	p.manifestBaseURL = baseURL
+	return p
+}
+
+// LinkManifests makes external references to symbols in the manifests, of other projects,
+// link to their documentation instead of using the external link rules.
+func (p *Producer) LinkManifests(manifests ...*SymbolManifest) *Producer {
+	p.manifests = append(p.manifests, manifests...)
+	return p
+}
+
+// History renders a "Since vX.Y.Z" badge on each exported symbol. The local semver git tags of
+// the module are walked, by shelling out to git, and the exported API is parsed at each tag to
+// find the tag the symbol first appeared in and the tag where its signature last changed.
+func (p *Producer) History(enabled bool) *Producer {
+	p.history = enabled
+	return p
+}
+
+// TemplateFuncs registers functions available in all templates, in addition to the built-in
+// and helper functions. It may be invoked several times, a function with the same name as a
+// previously registered (or built-in) replaces it.
+func (p *Producer) TemplateFuncs(funcs texttemplate.FuncMap) *Producer {
+	if p.templateFuncs == nil {
+		p.templateFuncs = texttemplate.FuncMap{}
+	}
+
+	for name, fn := range funcs {
+		p.templateFuncs[name] = fn
+	}
+
+	return p
+}
+
+// TemplateData registers a named value available in all templates as {{.Config.Data.name}}.
+func (p *Producer) TemplateData(name string, value interface{}) *Producer {
+	if p.templateData == nil {
+		p.templateData = map[string]interface{}{}
+	}
+
+	p.templateData[name] = value
+	return p
+}
+
 // Concatenation configures how doc comments split by blank lines are combined.
 func (p *Producer) Concatenation(mode goparser.DocConcatenationMode) *Producer {
 	p.parseconfig.DocConcatenation = mode
 	return p
 }
 
+// Generated sets how files with a "// Code generated ... DO NOT EDIT." header are handled:
+// included (default), excluded or included with a "generated by" note.
+func (p *Producer) Generated(policy goparser.GeneratedPolicy) *Producer {
+	p.parseconfig.Generated = policy
+	return p
+}
+
 // SignatureStyle controls how signatures are rendered (e.g. "goasciidoc", "source").
 func (p *Producer) SignatureStyle(style string) *Producer {
 	p.signatureStyle = strings.TrimSpace(strings.ToLower(style))
@@ -274,12 +477,53 @@ func (p *Producer) Highlighter(name string) *Producer {
 	return p
 }
 
-// RenderOptions controls what examples to render for structs (struct-json, struct-yaml).
+// RenderOptions controls what examples to render for structs (struct-json, struct-yaml, struct-xml, struct-toml, struct-jsonschema).
 func (p *Producer) RenderOptions(opts map[string]bool) *Producer {
 	p.renderOptions = opts
 	return p
 }
 
+// SchemaOutputDir writes a standalone JSON Schema document, _<Struct>.schema.json_, for each
+// rendered struct into a per package sub directory of dir.
+func (p *Producer) SchemaOutputDir(dir string) *Producer {
+	p.schemaDir = dir
+	return p
+}
+
+// TestReportOutputDir writes a test report, _<package>_test.adoc_, per package with tests into
+// dir. It lists the tests, benchmarks and fuzz targets, the cases of table driven tests and
+// links each test to the symbols it exercises in the generated documentation.
+func (p *Producer) TestReportOutputDir(dir string) *Producer {
+	p.testReportDir = dir
+	return p
+}
+
+// DiagnosticsOutput writes the diagnostics of each run, e.g. type-check problems and unresolved
+// declarations that may degrade the documentation, as a JSON array to path.
+func (p *Producer) DiagnosticsOutput(path string) *Producer {
+	p.diagnosticsPath = path
+	return p
+}
+
+// DiagnosticsAppendix writes the diagnostics of each run as an asciidoc appendix to path, see
+// DiagnosticsTemplate, e.g. to include at the end of the documentation.
+func (p *Producer) DiagnosticsAppendix(path string) *Producer {
+	p.diagnosticsAppendix = path
+	return p
+}
+
+// ExampleValue registers the example value to render in struct examples for a fully
+// qualified type name such as _github.com/shopspring/decimal.Decimal_. The built-in
+// examples for the standard library are retained unless overridden.
+func (p *Producer) ExampleValue(typeName string, value goparser.ExampleValue) *Producer {
+	if p.examples == nil {
+		p.examples = goparser.NewExampleRegistry()
+	}
+
+	p.examples.Register(typeName, value)
+	return p
+}
+
 // Include adds one or more directory or files in any combination. The producer
 // will sort out which are directories and which are filepaths.
 //
@@ -323,6 +567,14 @@ func (p *Producer) Excludes(patterns ...string) *Producer {
 	return p
 }
 
+// SymbolFilter removes the symbols, by name and kind, that do not pass the filter from the
+// parsed packages before rendering (see goparser.NewSymbolFilter). References to removed
+// symbols are rendered as plain text.
+func (p *Producer) SymbolFilter(filter *goparser.SymbolFilter) *Producer {
+	p.parseconfig.SymbolFilter = filter
+	return p
+}
+
 // AllBuildTags enables auto-discovery of all build tags in the source code.
 func (p *Producer) AllBuildTags(enabled bool) *Producer {
 	p.parseconfig.AllBuildTags = enabled
@@ -115,7 +115,10 @@ var GlobalService = &Service{Name: DefaultGreeting}
 	assert.Equal(t, string(want), got)
 }
 
-func overrideAllDefaults(t *testing.T, p *Producer) {
+// defaultPartials are the default templates the binary registers as partials.
+var defaultPartials = map[string]bool{"module": true, "implementation": true}
+
+func overrideAllDefaults(t *testing.T, p *Producer) *Producer {
 	t.Helper()
 	defaultsDir := filepath.Join("..", "defaults")
 	entries, err := os.ReadDir(defaultsDir)
@@ -126,8 +129,14 @@ func overrideAllDefaults(t *testing.T, p *Producer) {
 			continue
 		}
 		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if defaultPartials[name] {
+			p.PartialFilePath(name, filepath.Join(defaultsDir, entry.Name()))
+			continue
+		}
 		p.OverrideFilePath(name, filepath.Join(defaultsDir, entry.Name()))
 	}
+
+	return p
 }
 
 // createSampleModule writes a minimal module with a single Go file and returns its paths.
@@ -12,9 +12,19 @@ import (
 )
 
 // CreateTemplateWithOverrides creates a new instance of _Template_
-// and add the possible _Provider.overrides_ into it.
+// and add the possible _Provider.overrides_, extensions, partials and template functions into it.
 func (p *Producer) CreateTemplateWithOverrides() *Template {
-	return NewTemplateWithOverrides(p.overrides)
+	return NewTemplateFromSources(p.templateSources())
+}
+
+// templateSources returns the sources to create the templates from.
+func (p *Producer) templateSources() *TemplateSources {
+	return &TemplateSources{
+		Overrides:  p.overrides,
+		Extensions: p.extensions,
+		Partials:   p.partials,
+		Funcs:      p.templateFuncs,
+	}
 }
 
 // Generate will execute the generation of the documentation
@@ -22,25 +32,97 @@ func (p *Producer) Generate() {
 
 	p.debugf("Generate: starting with %d include path(s)", len(p.paths))
 
+	p.diagnostics = nil
+	marks := p.unresolvedMarks()
+
+	p.applyExamples()
+	p.applyManifests()
+	p.buildFilteredIndex()
+	p.buildUsedByIndex()
+	p.buildHistory()
+
+	if p.manifestPath != "" {
+		module := ""
+		if p.parseconfig.Module != nil {
+			module = p.parseconfig.Module.Name
+		}
+		p.manifest = NewSymbolManifest(module, p.manifestBaseURL)
+	}
+
 	// Package-level rendering takes precedence
 	if p.packageMode != PackageModeNone {
 		p.generateSeparatePackages()
 		p.debugf("Generate: completed package-level rendering")
+	} else {
+		// Dispatch based on sub-module mode
+		switch p.subModuleMode {
+		case SubModuleSingle:
+			p.generateMergedModules()
+		case SubModuleSeparate:
+			p.generateSeparateModules()
+		default:
+			// SubModuleNone - original single module behavior
+			p.generateSingleModule()
+		}
+
+		p.debugf("Generate: completed for %d include path(s)", len(p.paths))
+	}
+
+	if p.manifest != nil {
+		p.debugf("Generate: writing symbol manifest with %d symbol(s) to %s", len(p.manifest.Symbols), p.manifestPath)
+		if err := p.manifest.Write(p.manifestPath); err != nil {
+			panic(err)
+		}
+	}
+
+	p.reportUnresolved(marks)
+	if err := p.writeDiagnosticsReport(); err != nil {
+		panic(err)
+	}
+}
+
+// applyManifests makes the external link resolver link to the symbols in the loaded manifests,
+// relative the directory of the output document.
+func (p *Producer) applyManifests() {
+	if len(p.manifests) == 0 {
 		return
 	}
 
-	// Dispatch based on sub-module mode
-	switch p.subModuleMode {
-	case SubModuleSingle:
-		p.generateMergedModules()
-	case SubModuleSeparate:
-		p.generateSeparateModules()
-	default:
-		// SubModuleNone - original single module behavior
-		p.generateSingleModule()
+	dir := ""
+	switch {
+	case p.outfile != "":
+		dir = filepath.Dir(p.outfile)
+	case p.parseconfig.Module != nil:
+		dir = p.parseconfig.Module.Base
+	}
+	if abs, err := filepath.Abs(dir); err == nil && dir != "" {
+		dir = abs
+	}
+
+	p.externalLinks = p.externalLinks.withManifests(p.manifests, dir)
+}
+
+// addToManifest adds the symbols of the package, rendered in the current output file, to the
+// symbol manifest (if enabled).
+func (p *Producer) addToManifest(pkg *goparser.GoPackage) {
+	if p.manifest == nil {
+		return
+	}
+
+	document := ""
+	if p.outfile != "" {
+		document = filepath.Base(p.outfile)
+
+		dir, err := filepath.Abs(filepath.Dir(p.manifestPath))
+		outfile, err2 := filepath.Abs(p.outfile)
+		if err == nil && err2 == nil {
+			if rel, err := filepath.Rel(dir, outfile); err == nil {
+				document = rel
+			}
+		}
 	}
 
-	p.debugf("Generate: completed for %d include path(s)", len(p.paths))
+	p.manifest.AddPackage(pkg, document, p.private)
 }
 
 // generateSingleModule handles the original single-module generation
@@ -325,6 +407,81 @@ func (p *Producer) generateSeparatePackages() {
 	p.generatePackageMasterIndex(packageFiles, packageInfoMap)
 }
 
+// buildFilteredIndex collects the fully qualified names of all symbols removed by the symbol
+// filter, so references to them can be rendered as plain text instead of broken links.
+func (p *Producer) buildFilteredIndex() {
+	p.filtered = nil
+
+	filter := p.parseconfig.SymbolFilter
+	if filter == nil {
+		return
+	}
+
+	p.parseconfig.SymbolFilter = nil
+	packages, err := p.collectAllPackages()
+	p.parseconfig.SymbolFilter = filter
+
+	if err != nil {
+		p.debugf("Generate: error collecting packages for the symbol filter: %v", err)
+		return
+	}
+
+	p.filtered = map[string]struct{}{}
+	for _, pkg := range packages {
+		for _, name := range filter.Apply(pkg) {
+			p.filtered[name] = struct{}{}
+		}
+	}
+
+	p.debugf("Generate: symbol filter removes %d symbol(s)", len(p.filtered))
+}
+
+// buildUsedByIndex collects all packages and builds the reverse reference index for the
+// "Used By" sections of types, when enabled.
+func (p *Producer) buildUsedByIndex() {
+	p.usedByIndex = nil
+	if p.usedBy == UsedByDisabled {
+		return
+	}
+
+	packages, err := p.collectAllPackages()
+	if err != nil {
+		p.debugf("Generate: error collecting packages for used by index: %v", err)
+		return
+	}
+
+	p.usedByIndex = NewUsedByIndex(packages, p.usedBy, p.parseconfig.Workspace, p.private)
+	p.debugf("Generate: used by index contains %d referenced type(s)", len(p.usedByIndex))
+}
+
+// buildHistory builds the git tag history of each documented module, used to render the
+// "Since" badges, when enabled.
+func (p *Producer) buildHistory() {
+	p.historyIndex = nil
+	if !p.history {
+		return
+	}
+
+	var modules []*goparser.GoModule
+	if p.parseconfig.Workspace != nil {
+		modules = p.parseconfig.Workspace.Modules
+	} else if p.parseconfig.Module != nil {
+		modules = []*goparser.GoModule{p.parseconfig.Module}
+	}
+
+	p.historyIndex = map[string]goparser.APIHistory{}
+	for _, mod := range modules {
+		history, err := goparser.BuildAPIHistory(mod, p.parseconfig.Debug)
+		if err != nil {
+			p.debugf("Generate: no tag history for module %s: %v", mod.Name, err)
+			continue
+		}
+
+		p.historyIndex[mod.Name] = history
+		p.debugf("Generate: tag history of module %s contains %d symbol(s)", mod.Name, len(history))
+	}
+}
+
 // PackageInfo holds metadata about a package for cross-referencing
 type PackageInfo struct {
 	Package *goparser.GoPackage
@@ -391,8 +548,17 @@ func (p *Producer) renderPackage(t *Template, w io.Writer, pkg *goparser.GoPacka
 		return nil
 	}
 
+	if err := p.writeSchemas(pkg); err != nil {
+		return err
+	}
+
+	if err := p.writeTestReport(t, pkg); err != nil {
+		return err
+	}
+
 	// Build package references (internal and external)
 	pkgRefs := p.buildPackageReferences(pkg, packageInfoMap)
+	p.addToManifest(pkg)
 
 	// Render using package-standalone template
 	firstFile := pkg.Files[0]
@@ -414,11 +580,20 @@ func (p *Producer) renderPackage(t *Template, w io.Writer, pkg *goparser.GoPacka
 		Index:       indexConfig,
 		PackageRefs: pkgRefs,
 		Config: &TemplateContextConfig{
-			Private:        p.private,
-			TypeLinks:      p.typeLinks,
-			SignatureStyle: p.signatureStyle,
-			RenderOptions:  p.renderOptions,
-			PackageMode:    p.packageMode,
+			IncludeMethodCode: p.methodCode,
+			Private:           p.private,
+			TypeLinks:         p.typeLinks,
+			SignatureStyle:    p.signatureStyle,
+			RenderOptions:     p.renderOptions,
+			PackageMode:       p.packageMode,
+			Generated:         p.parseconfig.Generated,
+			UsedBy:            p.usedByIndex,
+			ExternalLinks:     p.externalLinks,
+			History:           p.historyIndex,
+			Data:              p.templateData,
+			Filtered:          p.filtered,
+			Layout:            p.layout,
+			SortOrder:         p.sortOrder,
 		},
 	}
 
@@ -427,40 +602,47 @@ func (p *Producer) renderPackage(t *Template, w io.Writer, pkg *goparser.GoPacka
 		return fmt.Errorf("error rendering package header: %v", err)
 	}
 
-	// Render package contents (imports, interfaces, structs, functions, etc.)
-	for _, file := range pkg.Files {
-		fileCtx := ctx.Clone(false)
-		fileCtx.File = file
-		fileCtx.Package = pkg
-
-		// Render imports
-		if len(file.Imports) > 0 {
-			fileCtx.RenderImports(w)
-		}
+	if p.layout == LayoutGodoc {
+		// Types, and their members, may be declared in different files.
+		pkgCtx := ctx.Clone(false)
+		pkgCtx.File = &pkg.GoFile
+		pkgCtx.renderGodocLayout(w)
+	} else {
+		// Render package contents (imports, interfaces, structs, functions, etc.)
+		for _, file := range pkg.Files {
+			fileCtx := ctx.Clone(false)
+			fileCtx.File = file
+			fileCtx.Package = pkg
+
+			// Render imports
+			if len(file.Imports) > 0 {
+				fileCtx.RenderImports(w)
+			}
 
-		// Render interfaces
-		if len(file.Interfaces) > 0 {
-			fileCtx.RenderInterfaces(w)
-		}
+			// Render interfaces
+			if hasDocumentedSymbols(file.Interfaces) {
+				fileCtx.RenderInterfaces(w)
+			}
 
-		// Render structs
-		if len(file.Structs) > 0 {
-			fileCtx.RenderStructs(w)
-		}
+			// Render structs
+			if hasDocumentedSymbols(file.Structs) {
+				fileCtx.RenderStructs(w)
+			}
 
-		// Render functions
-		if len(file.StructMethods) > 0 {
-			fileCtx.RenderFunctions(w)
-		}
+			// Render functions
+			if hasDocumentedSymbols(file.StructMethods) {
+				fileCtx.RenderFunctions(w)
+			}
 
-		// Render variables
-		if len(file.VarAssignments) > 0 {
-			fileCtx.RenderVarDeclarations(w)
-		}
+			// Render variables
+			if hasDocumentedSymbols(file.VarAssignments) {
+				fileCtx.RenderVarDeclarations(w)
+			}
 
-		// Render constants
-		if len(file.ConstAssignments) > 0 {
-			fileCtx.RenderConstDeclarations(w)
+			// Render constants
+			if hasDocumentedSymbols(file.ConstAssignments) {
+				fileCtx.RenderConstDeclarations(w)
+			}
 		}
 	}
 
@@ -577,8 +759,8 @@ func (p *Producer) buildPackageReferences(pkg *goparser.GoPackage, packageInfoMa
 			Name: impPath,
 		}
 
-		// Add pkg.go.dev link for external navigation
-		externalRef.File = fmt.Sprintf("https://pkg.go.dev/%s", strings.TrimPrefix(impPath, "/"))
+		// Add external link (default pkg.go.dev) for external navigation
+		externalRef.File = p.externalLinks.URL(pkg.Module, impPath, "")
 
 		refs.External = append(refs.External, externalRef)
 	}
@@ -656,6 +838,7 @@ func (p *Producer) generatePackageMasterIndex(packageFiles []string, packageInfo
 		Config: &TemplateContextConfig{
 			PackageMode:        p.packageMode,
 			PackageModeInclude: p.packageMode == PackageModeInclude,
+			Data:               p.templateData,
 		},
 	}
 
@@ -694,6 +877,7 @@ func (p *Producer) generatePackageMasterIndex(packageFiles []string, packageInfo
 			Config: &TemplateContextConfig{
 				PackageMode:        p.packageMode,
 				PackageModeInclude: p.packageMode == PackageModeInclude,
+				Data:               p.templateData,
 			},
 		}
 
@@ -760,6 +944,7 @@ func (p *Producer) generateMasterIndex(moduleFiles []string) {
 		Index:     indexConfig,
 		Config: &TemplateContextConfig{
 			ModuleModeInclude: false, // Don't use include mode for overview
+			Data:              p.templateData,
 		},
 	}
 
@@ -789,6 +974,7 @@ func (p *Producer) generateMasterIndex(moduleFiles []string) {
 			ModuleAnchor: fmt.Sprintf("module-%d", i+1),
 			Config: &TemplateContextConfig{
 				ModuleModeInclude: true, // Use include mode
+				Data:              p.templateData,
 			},
 		}
 
@@ -844,14 +1030,32 @@ func (p *Producer) getProcessFunc(
 
 		p.debugf("Render: package %s (%d file(s))", pkg.Package, len(pkg.Files))
 
+		if err := p.writeSchemas(pkg); err != nil {
+			return err
+		}
+
+		if err := p.writeTestReport(t, pkg); err != nil {
+			return err
+		}
+
+		p.addToManifest(pkg)
+
 		tc := t.NewContextWithConfig(&pkg.GoFile, pkg, &TemplateContextConfig{
-			IncludeMethodCode:    false,
+			IncludeMethodCode:    p.methodCode,
 			PackageOverviewPaths: overviewpaths,
 			Private:              p.private,
 			TypeLinks:            p.typeLinks,
 			SignatureStyle:       p.signatureStyle,
 			RenderOptions:        p.renderOptions,
 			SubModuleMode:        p.subModuleMode,
+			Generated:            p.parseconfig.Generated,
+			UsedBy:               p.usedByIndex,
+			ExternalLinks:        p.externalLinks,
+			History:              p.historyIndex,
+			Data:                 p.templateData,
+			Filtered:             p.filtered,
+			Layout:               p.layout,
+			SortOrder:            p.sortOrder,
 		})
 
 		// Set workspace if available
@@ -877,35 +1081,41 @@ func (p *Producer) getProcessFunc(
 
 		tc.RenderPackage(w)
 
+		if p.layout == LayoutGodoc {
+			p.debugf("Render: package %s in godoc layout", pkg.Package)
+			tc.renderGodocLayout(w)
+			return nil
+		}
+
 		if len(pkg.Imports) > 0 {
 			p.debugf("Render: package %s imports section", pkg.Package)
 			tc.RenderImports(w)
 		}
-		if len(pkg.Interfaces) > 0 {
+		if hasDocumentedSymbols(pkg.Interfaces) {
 			p.debugf("Render: package %s interfaces section", pkg.Package)
 			tc.RenderInterfaces(w)
 		}
-		if len(pkg.Structs) > 0 {
+		if hasDocumentedSymbols(pkg.Structs) {
 			p.debugf("Render: package %s structs section", pkg.Package)
 			tc.RenderStructs(w)
 		}
-		if len(pkg.CustomTypes) > 0 {
+		if hasDocumentedSymbols(pkg.CustomTypes) {
 			p.debugf("Render: package %s custom type definitions", pkg.Package)
 			tc.RenderVarTypeDefs(w)
 		}
-		if len(pkg.ConstAssignments) > 0 {
+		if hasDocumentedSymbols(pkg.ConstAssignments) {
 			p.debugf("Render: package %s const declarations", pkg.Package)
 			tc.RenderConstDeclarations(w)
 		}
-		if len(pkg.CustomFuncs) > 0 {
+		if hasDocumentedSymbols(pkg.CustomFuncs) {
 			p.debugf("Render: package %s custom function definitions", pkg.Package)
 			tc.RenderTypeDefFuncs(w)
 		}
-		if len(pkg.VarAssignments) > 0 {
+		if hasDocumentedSymbols(pkg.VarAssignments) {
 			p.debugf("Render: package %s variable declarations", pkg.Package)
 			tc.RenderVarDeclarations(w)
 		}
-		if len(pkg.StructMethods) > 0 {
+		if hasDocumentedSymbols(pkg.StructMethods) {
 			p.debugf("Render: package %s struct methods", pkg.Package)
 			tc.RenderFunctions(w)
 		}
@@ -915,7 +1125,7 @@ func (p *Producer) getProcessFunc(
 
 	if p.macro {
 
-		return getProcessMacroFunc(p.parseconfig, processor)
+		return getProcessMacroFunc(p.parseconfig, p.macros, p.report, processor)
 
 	}
 
@@ -0,0 +1,46 @@
+package asciidoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// writeSchemas writes a standalone JSON Schema document for each rendered struct
+// in the package when a schema output directory has been configured.
+func (p *Producer) writeSchemas(pkg *goparser.GoPackage) error {
+	if p.schemaDir == "" || pkg == nil || len(pkg.Structs) == 0 {
+		return nil
+	}
+
+	name := pkg.FqPackage
+	if name == "" {
+		name = pkg.Package
+	}
+
+	dir := filepath.Join(p.schemaDir, strings.ReplaceAll(name, "/", "_"))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create schema directory %s: %w", dir, err)
+	}
+
+	for _, s := range pkg.Structs {
+		if !s.Exported && !p.private {
+			continue
+		}
+
+		schema := s.JSONSchema()
+		schema.ID = s.Name + ".schema.json"
+
+		path := filepath.Join(dir, schema.ID)
+		p.debugf("Schema: writing %s", path)
+
+		if err := os.WriteFile(path, []byte(schema.String()+"\n"), 0o644); err != nil {
+			return fmt.Errorf("write schema %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package asciidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructJSONSchemaRenderedAndWritten(t *testing.T) {
+	modDir := t.TempDir()
+	pkgDir := filepath.Join(modDir, "sample")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+
+	goMod := "module example.com/sample\n\ngo 1.21\n"
+	require.NoError(t, os.WriteFile(filepath.Join(modDir, "go.mod"), []byte(goMod), 0o644))
+
+	src := `package sample
+
+import "time"
+
+// Event is emitted on changes.
+type Event struct {
+	// At is when the event occurred.
+	At   time.Time ` + "`json:\"at\"`" + `
+	Kind string    ` + "`json:\"kind,omitempty\"`" + `
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "event.go"), []byte(src), 0o644))
+
+	schemaDir := filepath.Join(modDir, "schemas")
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(pkgDir).
+		NoIndex().
+		RenderOptions(map[string]bool{"struct-jsonschema": true}).
+		SchemaOutputDir(schemaDir)
+
+	overrideAllDefaults(t, p)
+
+	p.Generate()
+
+	doc := buff.String()
+	assert.Contains(t, doc, "==== JSON Schema")
+	assert.Contains(t, doc, `"format": "date-time"`)
+	assert.NotContains(t, doc, "==== JSON Example")
+
+	data, err := os.ReadFile(filepath.Join(schemaDir, "example.com_sample_sample", "Event.schema.json"))
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Equal(t, "Event.schema.json", schema["$id"])
+	assert.Equal(t, []any{"at"}, schema["required"])
+}
@@ -0,0 +1,55 @@
+package asciidoc
+
+import (
+	"fmt"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// sinceBadge returns the "Since vX.Y.Z" badge of an exported struct, interface, custom type,
+// function type, function, method, variable or constant when the git tag history is enabled.
+// When the signature changed after the symbol was introduced, the tag of the last change is
+// part of the badge. Symbols not present in any tag get no badge.
+func (t *TemplateContext) sinceBadge(node interface{}) string {
+	if t.Config == nil || len(t.Config.History) == 0 {
+		return ""
+	}
+
+	var (
+		file *goparser.GoFile
+		name string
+	)
+
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		file, name = n.File, n.Name
+	case *goparser.GoInterface:
+		file, name = n.File, n.Name
+	case *goparser.GoCustomType:
+		file, name = n.File, n.Name
+	case *goparser.GoMethod:
+		file, name = n.File, n.Name
+	case *goparser.GoStructMethod:
+		file, name = n.File, n.Name
+		if len(n.ReceiverTypes) > 0 {
+			name = baseTypeIdentifier(n.ReceiverTypes[0].Type) + "." + n.Name
+		}
+	case *goparser.GoAssignment:
+		file, name = n.File, n.Name
+	}
+
+	if file == nil || file.Module == nil || name == "" {
+		return ""
+	}
+
+	history, ok := t.Config.History[file.Module.Name].Lookup(file.Module.Base, file, name)
+	if !ok {
+		return ""
+	}
+
+	if history.Changed != "" {
+		return fmt.Sprintf("[.since]#Since %s, changed in %s#", history.Since, history.Changed)
+	}
+
+	return fmt.Sprintf("[.since]#Since %s#", history.Since)
+}
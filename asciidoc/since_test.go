@@ -0,0 +1,92 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestSinceBadges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q")
+
+	release := func(tag string, files map[string]string) {
+		writeFiles(t, dir, files)
+		gitRun(t, dir, "add", "-A")
+		gitRun(t, dir, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", tag)
+		gitRun(t, dir, "tag", tag)
+	}
+
+	release("v0.1.0", map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.21\n",
+		"auth/auth.go": `package auth
+
+// Token is an access token.
+type Token string
+
+// Issue issues a token.
+func Issue() Token { return "" }
+`,
+	})
+	release("v0.2.0", map[string]string{
+		"auth/auth.go": `package auth
+
+// Token is an access token.
+type Token string
+
+// Issue issues a token.
+func Issue(subject string) Token { return "" }
+
+// Verify verifies the token.
+func (t Token) Verify() error { return nil }
+`,
+	})
+	writeFiles(t, dir, map[string]string{
+		"auth/auth.go": `package auth
+
+// Token is an access token.
+type Token string
+
+// Issue issues a token.
+func Issue(subject string) Token { return "" }
+
+// Verify verifies the token.
+func (t Token) Verify() error { return nil }
+
+// Unreleased is not tagged yet.
+type Unreleased struct{}
+`,
+	})
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(dir).
+		Include(dir).
+		Outfile(filepath.Join(dir, "docs.adoc")).
+		NoIndex().
+		History(true)
+
+	overrideAllDefaults(t, p)
+	p.Generate()
+
+	doc := buff.String()
+	assert.Contains(t, doc, "[.since]#Since v0.1.0#\n\nToken is an access token.")
+	assert.Contains(t, doc, "[.since]#Since v0.1.0, changed in v0.2.0#\n")
+	assert.Contains(t, doc, "[.since]#Since v0.2.0#\n\nVerify verifies the token.")
+	assert.Equal(t, 3, bytes.Count(buff.Bytes(), []byte("[.since]#")))
+}
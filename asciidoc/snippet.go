@@ -0,0 +1,201 @@
+package asciidoc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// snippetMarkerRegex matches a line with an asciidoc tag, or end, marker e.g. // tag::name[]
+var snippetMarkerRegex = regexp.MustCompile(`(?m)^.*\b(?:tag|end)::[^\[\s]*\[\].*(?:\n|$)`)
+
+// snippet returns a source block of the region in the file at path. The region is an asciidoc
+// tag region, i.e. the lines between tag::region[] and end::region[], or in a Go file the
+// named declaration e.g. Open, Item or Item.Reset. An empty region is the whole file.
+func snippet(path, region string, dirs []string) (string, error) {
+	file, data, err := readSnippetFile(path, dirs)
+	if err != nil {
+		return "", err
+	}
+
+	code, ok := string(data), true
+	if region != "" {
+		code, ok = tagRegion(code, region)
+		if !ok && filepath.Ext(file) == ".go" {
+			code, ok = goDeclaration(data, region)
+		}
+	}
+	if !ok {
+		return "", fmt.Errorf("snippet %s#%s: no tag region or declaration named %s in %s", path, region, region, file)
+	}
+
+	code = dedent(snippetMarkerRegex.ReplaceAllString(code, ""))
+
+	lang := strings.TrimPrefix(filepath.Ext(file), ".")
+	if lang == "" {
+		return fmt.Sprintf("[source]\n----\n%s\n----", code), nil
+	}
+
+	return fmt.Sprintf("[source,%s]\n----\n%s\n----", lang, code), nil
+}
+
+// readSnippetFile reads the file at path, an absolute path or a path relative the first of the
+// directories where it exists.
+func readSnippetFile(path string, dirs []string) (string, []byte, error) {
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		candidates = nil
+		for _, dir := range dirs {
+			if dir != "" {
+				candidates = append(candidates, filepath.Join(dir, path))
+			}
+		}
+	}
+
+	for _, candidate := range candidates {
+		if data, err := os.ReadFile(candidate); err == nil {
+			return candidate, data, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("snippet %s: file not found in %s", path, strings.Join(candidates, ", "))
+}
+
+// tagRegion returns the lines between the tag::name[] and end::name[] marker lines.
+func tagRegion(code, name string) (string, bool) {
+	lines := strings.SplitAfter(code, "\n")
+	begin, end := "tag::"+name+"[]", "end::"+name+"[]"
+
+	for i, line := range lines {
+		if !strings.Contains(line, begin) {
+			continue
+		}
+
+		for j := i + 1; j < len(lines); j++ {
+			if strings.Contains(lines[j], end) {
+				return strings.Join(lines[i+1:j], ""), true
+			}
+		}
+
+		// An unterminated region extends to the end of the file.
+		return strings.Join(lines[i+1:], ""), true
+	}
+
+	return "", false
+}
+
+// goDeclaration returns the source of the named top level declaration, without its doc comment,
+// in the Go source. Methods are named with their receiver type e.g. Item.Reset. A type, constant
+// or variable in a grouped declaration is returned as a declaration of its own.
+func goDeclaration(src []byte, name string) (string, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return "", false
+	}
+
+	source := func(from, to token.Pos) string {
+		return string(src[fset.Position(from).Offset:fset.Position(to).Offset])
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if funcDeclName(d) == name {
+				return source(d.Pos(), d.End()), true
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if !specDeclares(spec, name) {
+					continue
+				}
+
+				if len(d.Specs) == 1 {
+					return source(d.Pos(), d.End()), true
+				}
+
+				return d.Tok.String() + " " + source(spec.Pos(), spec.End()), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// funcDeclName returns the name of the function, or the receiver type and name of a method
+// e.g. Item.Reset.
+func funcDeclName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+
+	return fn.Name.Name
+}
+
+// specDeclares returns true if the type, constant or variable spec declares the name.
+func specDeclares(spec ast.Spec, name string) bool {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Name.Name == name
+	case *ast.ValueSpec:
+		for _, n := range s.Names {
+			if n.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// dedent removes the indentation common to all non blank lines and the surrounding blank lines.
+func dedent(code string) string {
+	lines := strings.Split(strings.TrimRight(code, " \t\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, prefix)
+	}
+
+	return strings.Join(lines, "\n")
+}
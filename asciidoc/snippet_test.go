@@ -0,0 +1,112 @@
+package asciidoc
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const snippetSource = `package shop
+
+// Item is an item.
+type Item struct {
+	Name string
+}
+
+const (
+	// Low is low.
+	Low = 1
+	High = 2
+)
+
+// Reset resets the item.
+func (i *Item) Reset() {
+	// tag::reset[]
+	i.Name = ""
+	// end::reset[]
+}
+`
+
+func TestSnippet(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"shop/shop.go":      snippetSource,
+		"config/app.yaml":   "# tag::db[]\ndb:\n  host: localhost\n# end::db[]\n",
+		"config/README":     "plain\n",
+		"shop/sub/other.go": "package sub\n\nfunc Other() {}\n",
+	})
+
+	dirs := []string{filepath.Join(dir, "shop"), dir}
+
+	tests := []struct {
+		name   string
+		path   string
+		region string
+		expect string
+		err    string
+	}{
+		{"tag region", "shop.go", "reset", "[source,go]\n----\ni.Name = \"\"\n----", ""},
+		{"type", "shop.go", "Item", "[source,go]\n----\ntype Item struct {\n\tName string\n}\n----", ""},
+		{"method", "shop.go", "Item.Reset", "[source,go]\n----\nfunc (i *Item) Reset() {\n\ti.Name = \"\"\n}\n----", ""},
+		{"grouped constant", "shop.go", "High", "[source,go]\n----\nconst High = 2\n----", ""},
+		{"module relative", "config/app.yaml", "db", "[source,yaml]\n----\ndb:\n  host: localhost\n----", ""},
+		{"whole file", "sub/other.go", "", "[source,go]\n----\npackage sub\n\nfunc Other() {}\n----", ""},
+		{"no extension", "config/README", "", "[source]\n----\nplain\n----", ""},
+		{"missing region", "shop.go", "Open", "", "snippet shop.go#Open: no tag region or declaration named Open in " + filepath.Join(dir, "shop", "shop.go")},
+		{"missing region in non go file", "config/app.yaml", "Item", "", "no tag region or declaration named Item"},
+		{"missing file", "nope.go", "Item", "", "snippet nope.go: file not found in"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			block, err := snippet(tc.path, tc.region, dirs)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, block)
+		})
+	}
+}
+
+func TestSnippetMacro(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"shop/shop.go": snippetSource})
+
+	file := &goparser.GoFile{FilePath: filepath.Join(dir, "shop", "shop.go")}
+	pkg := &goparser.GoPackage{
+		GoFile: goparser.GoFile{
+			Module: &goparser.GoModule{Base: dir},
+			StructMethods: []*goparser.GoStructMethod{{GoMethod: goparser.GoMethod{
+				File: file,
+				Line: 15,
+				Doc:  "Reset resets, e.g. ${gad:snippet:shop.go#reset} or\n${gad:snippet:shop/shop.go#Low}\n\n${gad:snippet:shop.go#Missing}",
+			}}},
+		},
+	}
+
+	var diagnostics []Diagnostic
+	require.NoError(t, getProcessMacroFunc(goparser.ParseConfig{}, nil, func(d Diagnostic) {
+		diagnostics = append(diagnostics, d)
+	}, func(*goparser.GoPackage) error { return nil })(pkg))
+
+	assert.Equal(t,
+		"Reset resets, e.g.\n[source,go]\n----\ni.Name = \"\"\n----\n or\n[source,go]\n----\nconst Low = 1\n----\n\n",
+		pkg.StructMethods[0].Doc)
+
+	require.Len(t, diagnostics, 1)
+	assert.Equal(t, Diagnostic{
+		Severity: SeverityWarning,
+		Rule:     "snippet",
+		File:     file.FilePath,
+		Line:     15,
+		Message:  "snippet shop.go#Missing: no tag region or declaration named Missing in " + file.FilePath,
+	}, diagnostics[0])
+	assert.Equal(t, file.FilePath+":15: warning: "+diagnostics[0].Message+" [snippet]", diagnostics[0].String())
+}
@@ -0,0 +1,80 @@
+package asciidoc
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolFilterRendering(t *testing.T) {
+	modDir := t.TempDir()
+	writeFiles(t, modDir, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"model/model.go": `package model
+
+// Item is a sellable item.
+type Item struct {
+	// Price is the price in cents.
+	Price int
+}
+
+// ItemMock is a test double, see Item.
+type ItemMock struct{}
+
+// Reset resets the mock.
+func (m *ItemMock) Reset() {}
+`,
+		"store/store.go": `package store
+
+import "example.com/shop/model"
+
+// Store uses ` + "`model.ItemMock`" + ` in tests and ` + "`model.Item`" + ` otherwise.
+type Store struct {
+	// Double is used in tests.
+	Double *model.ItemMock
+	// Item is the item.
+	Item *model.Item
+}
+
+// NewSizeOption creates an option.
+func NewSizeOption() {}
+
+// MaxItems is the maximum number of items.
+const MaxItems = 10
+`,
+	})
+
+	filter, err := goparser.NewSymbolFilter(nil, []string{"glb:*Mock*", "func=glb:New*Option", "const="})
+	require.NoError(t, err)
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(modDir).
+		Outfile(filepath.Join(modDir, "docs.adoc")).
+		NoIndex().
+		TypeLinks(TypeLinksInternal).
+		SymbolFilter(filter)
+
+	overrideAllDefaults(t, p)
+
+	p.Generate()
+
+	doc := buff.String()
+	assert.NotContains(t, doc, "=== ItemMock")
+	assert.NotContains(t, doc, "Reset")
+	assert.NotContains(t, doc, "NewSizeOption")
+	assert.NotContains(t, doc, "MaxItems")
+	assert.NotContains(t, doc, "== Constants")
+	assert.Contains(t, doc, "=== Item\n")
+
+	assert.NotContains(t, doc, "<<example-com-shop-model-ItemMock")
+	assert.Contains(t, doc, "Store uses `model.ItemMock` in tests and <<example-com-shop-model-Item,model.Item>> otherwise.")
+	assert.Contains(t, doc, "==== Double *model.ItemMock\n")
+	assert.Contains(t, doc, "<<example-com-shop-model-Item,model.Item>>")
+}
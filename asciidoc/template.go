@@ -2,8 +2,10 @@ package asciidoc
 
 import (
 	"bytes"
+	"sort"
 	"strings"
 	texttemplate "text/template"
+	"text/template/parse"
 
 	"github.com/mariotoffia/goasciidoc/goparser"
 )
@@ -53,6 +55,16 @@ const (
 	ConstDeclarationTemplate TemplateType = "const"
 	// ReceiversTemplate is a template that renders receivers functions
 	ReceiversTemplate TemplateType = "receivers"
+	// TypesTemplate is a template that renders all types, with their members, in the godoc layout
+	TypesTemplate TemplateType = "types"
+	// MembersTemplate is a template that renders the constants, variables and constructors of a type
+	MembersTemplate TemplateType = "members"
+	// TestReportTemplate is a template that renders the tests, benchmarks and fuzz targets of a
+	// package as a standalone test report
+	TestReportTemplate TemplateType = "testreport"
+	// DiagnosticsTemplate is a template that renders the problems found when generating as a
+	// standalone asciidoc appendix
+	DiagnosticsTemplate TemplateType = "diagnostics"
 )
 
 func (tt TemplateType) String() string {
@@ -105,6 +117,43 @@ var defaultTemplateFuncs = texttemplate.FuncMap{
 	"toYAML": func(s *goparser.GoStruct) string {
 		return s.ToYAML()
 	},
+	"hasXMLTag": func(s *goparser.GoStruct) bool {
+		return s.HasXMLTag()
+	},
+	"hasTOMLTag": func(s *goparser.GoStruct) bool {
+		return s.HasTOMLTag()
+	},
+	"toXML": func(s *goparser.GoStruct) string {
+		return s.ToXML()
+	},
+	"toTOML": func(s *goparser.GoStruct) string {
+		return s.ToTOML()
+	},
+	"toJSONSchema": func(s *goparser.GoStruct) string {
+		return s.ToJSONSchema()
+	},
+	"generatedNote": func(t *TemplateContext, node interface{}) string {
+		return t.generatedNote(node)
+	},
+	"platformVariants": func(t *TemplateContext, node interface{}) string {
+		return t.platformVariants(node)
+	},
+	"usedBy": func(t *TemplateContext, node interface{}) string {
+		return t.usedBy(node)
+	},
+	"sinceBadge": func(t *TemplateContext, node interface{}) string {
+		return t.sinceBadge(node)
+	},
+	"groups": func(t *TemplateContext, list interface{}) []SymbolGroup {
+		return t.groups(list)
+	},
+	"ignored": ignored,
+	"implementation": func(t *TemplateContext, fn *goparser.GoStructMethod) *CodeDoc {
+		return t.implementation(fn)
+	},
+	"typeMembers": func(t *TemplateContext, name string) string {
+		return t.renderTypeMembers(name)
+	},
 	"processReferences": func(t *TemplateContext, doc string) string {
 		return t.processDocumentation(doc)
 	},
@@ -130,37 +179,68 @@ type Template struct {
 // NewTemplateWithOverrides creates a new template with the ability to easily
 // override defaults.
 func NewTemplateWithOverrides(overrides map[string]string) *Template {
+	return NewTemplateWithFuncs(overrides, nil)
+}
+
+// NewTemplateWithFuncs creates a new template, just as NewTemplateWithOverrides, where the
+// funcs are available in all templates. The funcs may replace the built-in and helper
+// functions, except the template specific functions e.g. render.
+func NewTemplateWithFuncs(overrides map[string]string, funcs texttemplate.FuncMap) *Template {
+	return NewTemplateFromSources(&TemplateSources{Overrides: overrides, Funcs: funcs})
+}
+
+// TemplateSources is the text, and functions, all templates are created from.
+type TemplateSources struct {
+	// Overrides replaces the template text of the named templates.
+	Overrides map[string]string
+	// Extensions are, per template name, texts of only {{define}} blocks that are parsed, in
+	// order, on top of the template. They redefine {{block}} sections of the template instead
+	// of replacing it entirely.
+	Extensions map[string][]string
+	// Partials are, per name, shared templates parsed into the namespace of every template.
+	// They are used as {{template "name" .}} and may {{define}} further templates.
+	Partials map[string]string
+	// Funcs are available in all templates.
+	Funcs texttemplate.FuncMap
+}
+
+// NewTemplateFromSources creates a new template from the sources, nil creates the default
+// template.
+func NewTemplateFromSources(src *TemplateSources) *Template {
+	if src == nil {
+		src = &TemplateSources{}
+	}
 
 	return &Template{
 		Templates: map[string]*TemplateAndText{
 			IndexTemplate.String(): createTemplate(
 				IndexTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 			PackageTemplate.String(): createTemplate(
 				PackageTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 			PackageRefTemplate.String(): createTemplate(
 				PackageRefTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 			PackageRefsTemplate.String(): createTemplate(
 				PackageRefsTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 			ImportTemplate.String(): createTemplate(
 				ImportTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"render": func(t *TemplateContext) string { return t.File.DeclImports() },
 				},
@@ -168,7 +248,7 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			FunctionsTemplate.String(): createTemplate(
 				FunctionsTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"render": func(t *TemplateContext, f *goparser.GoStructMethod) string {
 						var buf bytes.Buffer
@@ -178,18 +258,21 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 					"notreceiver": func(t *TemplateContext, f *goparser.GoStructMethod) bool {
 						return len(f.Receivers) == 0
 					},
+					"functions": func(t *TemplateContext, list []*goparser.GoStructMethod) []*goparser.GoStructMethod {
+						return functionsOf(list)
+					},
 				},
 			),
 			FunctionTemplate.String(): createTemplate(
 				FunctionTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 			InterfacesTemplate.String(): createTemplate(
 				InterfacesTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"render": func(t *TemplateContext, i *goparser.GoInterface) string {
 						var buf bytes.Buffer
@@ -201,7 +284,7 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			InterfaceTemplate.String(): createTemplate(
 				InterfaceTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"tabifylast": func(decl string) string {
 						idx := strings.LastIndex(decl, " ")
@@ -215,7 +298,7 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			StructsTemplate.String(): createTemplate(
 				StructsTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"render": func(t *TemplateContext, s *goparser.GoStruct) string {
 						var buf bytes.Buffer
@@ -227,7 +310,7 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			StructTemplate.String(): createTemplate(
 				StructTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"tabify": func(decl string) string { return strings.Replace(decl, " ", "\t", 1) },
 					"render": func(t *TemplateContext, s *goparser.GoStruct) string {
@@ -242,22 +325,92 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 					},
 					"hasReceivers": func(t *TemplateContext, receiver string) bool {
 						if nil != t.Package {
-							return len(t.Package.FindMethodsByReceiver(receiver)) > 0
+							return hasDocumentedSymbols(t.Package.FindMethodsByReceiver(receiver))
 						}
-						return len(t.File.FindMethodsByReceiver(receiver)) > 0
+						return hasDocumentedSymbols(t.File.FindMethodsByReceiver(receiver))
 					},
 				},
 			),
 			ReceiversTemplate.String(): createTemplate(
 				ReceiversTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
+			TypesTemplate.String(): createTemplate(
+				TypesTemplate,
+				"",
+				src,
+				texttemplate.FuncMap{
+					"types": func(t *TemplateContext) []interface{} {
+						return typesOf(t.File)
+					},
+					"render": func(t *TemplateContext, node interface{}) string {
+						var buf bytes.Buffer
+						switch n := node.(type) {
+						case *goparser.GoStruct:
+							t.RenderStruct(&buf, n)
+						case *goparser.GoInterface:
+							t.RenderInterface(&buf, n)
+						case *goparser.GoCustomType:
+							t.RenderVarTypeDef(&buf, n)
+						case *goparser.GoMethod:
+							t.RenderTypeDefFunc(&buf, n)
+						}
+						return buf.String()
+					},
+				},
+			),
+			MembersTemplate.String(): createTemplate(
+				MembersTemplate,
+				"",
+				src,
+				texttemplate.FuncMap{
+					"renderConst": func(t *TemplateContext, a *goparser.GoAssignment) string {
+						var buf bytes.Buffer
+						t.RenderConstDeclaration(&buf, a)
+						return buf.String()
+					},
+					"renderVar": func(t *TemplateContext, a *goparser.GoAssignment) string {
+						var buf bytes.Buffer
+						t.RenderVarDeclaration(&buf, a)
+						return buf.String()
+					},
+					"renderFunction": func(t *TemplateContext, f *goparser.GoStructMethod) string {
+						var buf bytes.Buffer
+						t.RenderFunction(&buf, f)
+						return buf.String()
+					},
+				},
+			),
+			TestReportTemplate.String(): createTemplate(
+				TestReportTemplate,
+				"",
+				src,
+				texttemplate.FuncMap{
+					"exercised": func(t *TemplateContext, name string) string {
+						return t.exercisedLink(name)
+					},
+					"unexercised": func(t *TemplateContext) []string {
+						return t.unexercised()
+					},
+					"location": testLocation,
+				},
+			),
+			DiagnosticsTemplate.String(): createTemplate(
+				DiagnosticsTemplate,
+				"",
+				src,
+				texttemplate.FuncMap{
+					"cell": func(s string) string {
+						return strings.ReplaceAll(s, "|", "\\|")
+					},
+				},
+			),
 			CustomVarTypeDefsTemplate.String(): createTemplate(
 				CustomVarTypeDefsTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"render": func(t *TemplateContext, td *goparser.GoCustomType) string {
 						var buf bytes.Buffer
@@ -269,7 +422,7 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			CustomVarTypeDefTemplate.String(): createTemplate(
 				CustomVarTypeDefTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"renderReceivers": func(t *TemplateContext, receiver string) string {
 						var buf bytes.Buffer
@@ -278,16 +431,16 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 					},
 					"hasReceivers": func(t *TemplateContext, receiver string) bool {
 						if nil != t.Package {
-							return len(t.Package.FindMethodsByReceiver(receiver)) > 0
+							return hasDocumentedSymbols(t.Package.FindMethodsByReceiver(receiver))
 						}
-						return len(t.File.FindMethodsByReceiver(receiver)) > 0
+						return hasDocumentedSymbols(t.File.FindMethodsByReceiver(receiver))
 					},
 				},
 			),
 			VarDeclarationsTemplate.String(): createTemplate(
 				VarDeclarationsTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"render": func(t *TemplateContext, a *goparser.GoAssignment) string {
 						var buf bytes.Buffer
@@ -299,13 +452,13 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			VarDeclarationTemplate.String(): createTemplate(
 				VarDeclarationTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 			ConstDeclarationsTemplate.String(): createTemplate(
 				ConstDeclarationsTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"tabify": func(decl string) string { return strings.Replace(decl, " ", "\t", 1) },
 					"render": func(t *TemplateContext, a *goparser.GoAssignment) string {
@@ -318,13 +471,13 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			ConstDeclarationTemplate.String(): createTemplate(
 				ConstDeclarationTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 			CustomFuncTypeDefsTemplate.String(): createTemplate(
 				CustomFuncTypeDefsTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{
 					"render": func(t *TemplateContext, td *goparser.GoMethod) string {
 						var buf bytes.Buffer
@@ -336,7 +489,7 @@ func NewTemplateWithOverrides(overrides map[string]string) *Template {
 			CustomFuncTypeDefTemplate.String(): createTemplate(
 				CustomFuncTypeDefTemplate,
 				"",
-				overrides,
+				src,
 				texttemplate.FuncMap{},
 			),
 		},
@@ -380,28 +533,79 @@ func (t *Template) NewContextWithConfig(
 }
 
 // createTemplate will create a template named name and parses the str
-// as template. If fails it will panic with the parse error.
+// as template. If fails it will panic with the parse error. The source funcs
+// are added after the default functions and before the template specific fm.
 //
-// If name is found in override map it will use that string to parse the template
-// instead of the provided str.
+// If name is found in the source overrides it will use that string to parse the template
+// instead of the provided str. The partials are parsed first, then the template and last
+// the extensions, hence an extension may redefine blocks of the template.
 func createTemplate(
 	name TemplateType,
 	str string,
-	overrides map[string]string,
+	src *TemplateSources,
 	fm texttemplate.FuncMap,
 ) *TemplateAndText {
 
-	if s, ok := overrides[name.String()]; ok {
+	if s, ok := src.Overrides[name.String()]; ok {
 		str = s
 	}
 
-	pt, err := texttemplate.New(name.String()).Funcs(defaultTemplateFuncs).Funcs(fm).Parse(str)
-	if err != nil {
+	pt := texttemplate.New(name.String()).Funcs(defaultTemplateFuncs).
+		Funcs(helperTemplateFuncs).
+		Funcs(src.Funcs).
+		Funcs(fm)
+
+	partials := make([]string, 0, len(src.Partials))
+	for partial := range src.Partials {
+		partials = append(partials, partial)
+	}
+	sort.Strings(partials)
+
+	for _, partial := range partials {
+		if _, err := pt.New(partial).Parse(src.Partials[partial]); err != nil {
+			panic(err)
+		}
+	}
+
+	if _, err := pt.Parse(str); err != nil {
 		panic(err)
 	}
+
+	// The extensions are parsed in their own, otherwise empty, template so errors are reported
+	// as e.g. struct.extension while their {{define}} blocks replace those of the template.
+	for _, extension := range src.Extensions[name.String()] {
+		if _, err := pt.New(name.String() + ".extension").Parse(extension); err != nil {
+			panic(err)
+		}
+	}
+
 	return &TemplateAndText{
 		Text:     str,
 		Template: pt,
 	}
 
 }
+
+// IsTemplateExtension returns true if the text only consists of {{define}} blocks, i.e. it
+// extends a template by redefining its {{block}} sections rather than replacing it.
+func IsTemplateExtension(text string) bool {
+	tree := parse.New("extension")
+	tree.Mode = parse.SkipFuncCheck
+
+	treeSet := map[string]*parse.Tree{}
+	if _, err := tree.Parse(text, "", "", treeSet); err != nil {
+		return false
+	}
+
+	if root, ok := treeSet["extension"]; ok && !parse.IsEmptyTree(root.Root) {
+		return false
+	}
+
+	for name := range treeSet {
+		if name != "extension" {
+			return true
+		}
+	}
+
+	return false
+}
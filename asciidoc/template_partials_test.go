@@ -0,0 +1,119 @@
+package asciidoc
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTemplateExtension(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		expect bool
+	}{
+		{"only defines", `{{define "heading"}}=== {{.Struct.Name}}{{end}}`, true},
+		{"defines and whitespace", "\n{{define \"a\"}}A{{end}}\n\n{{define \"b\"}}{{shout .}}{{end}}\n", true},
+		{"defines and comment", `{{/* footer */}}{{define "footer"}}F{{end}}`, true},
+		{"body", `=== {{.Struct.Name}}`, false},
+		{"body and defines", `{{define "a"}}A{{end}}=== {{.Struct.Name}}`, false},
+		{"block", `{{block "footer" .}}F{{end}}`, false},
+		{"empty", ``, false},
+		{"invalid", `{{define "a"}}`, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expect, IsTemplateExtension(tc.text))
+		})
+	}
+}
+
+func TestPartialsAndBlockExtensions(t *testing.T) {
+	modDir := t.TempDir()
+	writeFiles(t, modDir, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"model/model.go": `package model
+
+// Item is a sellable item.
+type Item struct {
+	// Price is the price in cents.
+	Price int
+}
+
+// Level is a level.
+type Level int
+`,
+	})
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(modDir).
+		Outfile(filepath.Join(modDir, "docs.adoc")).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+	p.Partial("owner", `{{define "owner"}}Owned by {{.Config.Data.team}}.{{end}}`).
+		Partial("kind", `[.kind]#{{.}}#`).
+		TemplateData("team", "checkout").
+		Override(StructTemplate.String(), `
+{{define "heading"}}=== Struct {{.Struct.Name}}{{end}}
+{{define "footer"}}{{template "owner" .}} {{template "kind" "struct"}}{{printf "\n"}}{{end}}
+`).
+		Override(CustomVarTypeDefTemplate.String(), `{{define "footer"}}{{template "owner" .}}{{end}}`)
+
+	p.Generate()
+
+	doc := buff.String()
+	assert.Contains(t, doc, "[[example-com-shop-model-Item]]\n\n=== Struct Item\n[source, go]\n----\ntype Item struct {")
+	assert.Contains(t, doc, "==== Price int\n\n\nPrice is the price in cents.")
+	assert.Contains(t, doc, "Owned by checkout. [.kind]#struct#\n")
+	assert.Contains(t, doc, "=== Level\n[source, go]")
+	assert.Equal(t, 2, bytes.Count(buff.Bytes(), []byte("Owned by checkout.")))
+}
+
+func TestCheckTemplatesPartialsAndExtensions(t *testing.T) {
+	p := overrideAllDefaults(t, NewProducer()).
+		Partial("owner", "{{define \"owner\"}}\nOwned by {{ owner .Struct }}{{end}}").
+		Override(StructTemplate.String(), "{{define \"footer\"}}\n  {{.Struct.Owner}}{{end}}")
+
+	issues := p.CheckTemplates()
+	require.NotEmpty(t, issues)
+	assert.Equal(t, TemplateIssue{
+		Template: "owner",
+		Line:     2,
+		Column:   13,
+		Message:  `function "owner" not defined`,
+	}, issues[0])
+
+	p = overrideAllDefaults(t, NewProducer()).
+		Override(StructTemplate.String(), "{{define \"footer\"}}\n  {{.Struct.Owner}}{{end}}")
+
+	assert.Equal(t, []TemplateIssue{{
+		Template: "struct.extension",
+		Line:     2,
+		Column:   12,
+		Message:  `executing "footer" at <.Struct.Owner>: can't evaluate field Owner in type *goparser.GoStruct`,
+	}}, p.CheckTemplates())
+}
+
+func TestCheckTemplatesBrokenPartialOnly(t *testing.T) {
+	issues := NewProducer().Partial("sig", "{{ .Foo ").CheckTemplates()
+	require.Len(t, issues, 1)
+	assert.Equal(t, "sig", issues[0].Template)
+	assert.Equal(t, 1, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "unclosed action")
+
+	issues = NewProducer().Partial("sig", "\n{{ shout .Foo }}").CheckTemplates()
+	assert.Equal(t, []TemplateIssue{{
+		Template: "sig",
+		Line:     2,
+		Column:   4,
+		Message:  `function "shout" not defined`,
+	}}, issues)
+}
@@ -78,6 +78,13 @@ type TemplateContext struct {
 	Index *IndexConfig
 	// Receiver is the current receivers to be rendered.
 	Receiver []*goparser.GoStructMethod
+	// Members are the constants, variables and constructors of the current type to be
+	// rendered in the godoc layout.
+	Members *TypeMembers
+	// TestReport is the test suite of the package to be rendered as a test report.
+	TestReport *TestReport
+	// Diagnostics are the problems found when generating to be rendered as an appendix.
+	Diagnostics []Diagnostic
 	// Docs is a map that contains filepaths to various asciidoc documents
 	// that can be included.
 	//
@@ -97,8 +104,9 @@ type TemplateContext struct {
 // TemplateContextConfig contains configuration parameters how templates
 // renders the content and the TemplateContexts behaves.
 type TemplateContextConfig struct {
-	// IncludeMethodCode determines if the code is included in the documentation or not.
-	// Default not included.
+	// IncludeMethodCode determines if the implementation of functions and methods is included
+	// in the documentation or not. A //goasciidoc:code directive, on the function or in the
+	// package doc comment, overrides it. Default not included.
 	IncludeMethodCode bool
 	// PackageOverviewPaths paths to search for package overview relative the package path.
 	//
@@ -123,7 +131,7 @@ type TemplateContextConfig struct {
 	TypeLinks TypeLinkMode
 	// SignatureStyle determines how signatures are rendered (e.g. "goasciidoc" or "source").
 	SignatureStyle string
-	// RenderOptions controls what examples to render (struct-json, struct-yaml).
+	// RenderOptions controls what examples to render (struct-json, struct-yaml, struct-xml, struct-toml, struct-jsonschema).
 	RenderOptions map[string]bool
 	// SubModuleMode indicates how submodules are being processed
 	SubModuleMode SubModuleMode
@@ -133,6 +141,28 @@ type TemplateContextConfig struct {
 	PackageMode PackageMode
 	// PackageModeInclude when true renders package with include directive, otherwise with link
 	PackageModeInclude bool
+	// Generated is the policy for generated files, a "generated by" note is rendered for
+	// generated packages and symbols when goparser.GeneratedBadge.
+	Generated goparser.GeneratedPolicy
+	// UsedBy is the reverse reference index used to render the "Used By" section of types,
+	// nil or empty renders no such sections.
+	UsedBy UsedByIndex
+	// ExternalLinks resolves the URLs of external packages and symbols, nil links to pkg.go.dev.
+	ExternalLinks *ExternalLinkResolver
+	// History is the git tag history of the exported symbols, keyed by module name, used to
+	// render "Since" badges. Nil or empty renders no badges.
+	History map[string]goparser.APIHistory
+	// Data is named values, registered by the user, available to all templates e.g.
+	// {{.Config.Data.company}}.
+	Data map[string]interface{}
+	// Layout determines how the symbols of a package are organised, LayoutKind renders a
+	// section per kind and LayoutGodoc renders each type followed by its members.
+	Layout LayoutMode
+	// SortOrder determines the order of the symbols within a section.
+	SortOrder SortOrder
+	// Filtered are the fully qualified names, e.g. example.com/shop/model.ItemMock, of the
+	// symbols removed by the symbol filter. References to them are rendered as plain text.
+	Filtered map[string]struct{}
 }
 
 // IndexConfig is configuration to use when generating index template
@@ -158,6 +188,9 @@ type IndexConfig struct {
 	HomePage string `json:"web,omitempty"`
 	// DocType determines the document type, default is book
 	DocType string `json:"doctype,omitempty"`
+	// Examples maps fully qualified type names onto the example values to render
+	// for them in struct examples, e.g. {"github.com/shopspring/decimal.Decimal": "12.34"}.
+	Examples map[string]goparser.ExampleValue `json:"examples,omitempty"`
 }
 
 // Clone will clone the context.
@@ -193,6 +226,9 @@ func (t *TemplateContext) Clone(clean bool) *TemplateContext {
 		Index:           t.Index,
 		Docs:            t.Docs,
 		Receiver:        t.Receiver,
+		Members:         t.Members,
+		TestReport:      t.TestReport,
+		Diagnostics:     t.Diagnostics,
 		importCache:     t.importCache,
 	}
 }
@@ -307,6 +343,58 @@ func (t *TemplateContext) RenderReceiverFunctions(wr io.Writer, receiver string)
 	return t
 }
 
+// RenderTypeMembers will render the constants, variables and constructors of a type, albeit a
+// struct, interface or custom type, as rendered in the godoc layout.
+func (t *TemplateContext) RenderTypeMembers(wr io.Writer, name string) *TemplateContext {
+
+	q := t.Clone(true /*clean*/)
+	q.Members = t.typeMembers(name)
+
+	if err := t.creator.Templates[MembersTemplate.String()].Template.Execute(wr, q); nil != err {
+		panic(err)
+	}
+
+	return t
+}
+
+// RenderTypes will render all types, with their members, of the GoFile/GoPackage onto the
+// provided writer as done in the godoc layout.
+func (t *TemplateContext) RenderTypes(wr io.Writer) *TemplateContext {
+
+	if err := t.creator.Templates[TypesTemplate.String()].Template.Execute(wr, t.Clone(true /*clean*/)); nil != err {
+		panic(err)
+	}
+
+	return t
+}
+
+// RenderTestReport will render the tests, benchmarks and fuzz targets of the report onto the
+// provided writer as a standalone document.
+func (t *TemplateContext) RenderTestReport(wr io.Writer, report *TestReport) *TemplateContext {
+
+	q := t.Clone(true /*clean*/)
+	q.TestReport = report
+
+	if err := t.creator.Templates[TestReportTemplate.String()].Template.Execute(wr, q); nil != err {
+		panic(err)
+	}
+
+	return t
+}
+
+// RenderDiagnostics will render the diagnostics as an appendix onto the provided writer.
+func (t *TemplateContext) RenderDiagnostics(wr io.Writer, diagnostics []Diagnostic) *TemplateContext {
+
+	q := t.Clone(true /*clean*/)
+	q.Diagnostics = diagnostics
+
+	if err := t.creator.Templates[DiagnosticsTemplate.String()].Template.Execute(wr, q); nil != err {
+		panic(err)
+	}
+
+	return t
+}
+
 // RenderFunction will render a single function section onto the provided writer.
 func (t *TemplateContext) RenderFunction(
 	wr io.Writer,
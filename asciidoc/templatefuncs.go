@@ -0,0 +1,275 @@
+package asciidoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"unicode"
+)
+
+// helperTemplateFuncs is a general purpose set of helpers, a subset of the sprig library,
+// available in all templates to make override templates practical to write.
+//
+// .Helpers
+// |===
+// |Function |Description
+//
+// |lower, upper, title
+// |Changes the case of a string
+//
+// |camelcase, snakecase, kebabcase
+// |Converts an identifier e.g. HTTPServer to httpServer, http_server or http-server
+//
+// |trim, trimPrefix, trimSuffix, replace, repeat, quote
+// |String manipulation, the string to operate on is the last argument e.g. `{{replace "a" "b" .Name}}`
+//
+// |contains, hasPrefix, hasSuffix
+// |String predicates e.g. `{{if hasPrefix "Test" .Name}}`
+//
+// |split, join
+// |Splits a string into a list and joins a list into a string
+//
+// |regexMatch, regexFind, regexReplaceAll
+// |Regular expressions, e.g. `{{regexReplaceAll "[0-9]+" .Name "N"}}`
+//
+// |dict, list, hasKey, keys, first, last
+// |Creates and inspects maps (with string keys) and lists
+//
+// |default, empty, coalesce, ternary
+// |Default values and conditionals
+//
+// |sub, mul, div, mod
+// |Integer arithmetic (see also add)
+//
+// |readFile
+// |Reads a sidecar file e.g. `{{readFile "docs/intro.adoc"}}`
+//
+// |toPrettyJSON
+// |Marshals any value to indented JSON
+//
+// |===
+var helperTemplateFuncs = texttemplate.FuncMap{
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"title":     titleCase,
+	"camelcase": camelCase,
+	"snakecase": func(s string) string { return strings.Join(identifierWords(s), "_") },
+	"kebabcase": func(s string) string { return strings.Join(identifierWords(s), "-") },
+	"trim":      strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string {
+		return strings.TrimPrefix(s, prefix)
+	},
+	"trimSuffix": func(suffix, s string) string {
+		return strings.TrimSuffix(s, suffix)
+	},
+	"replace": func(old, new, s string) string {
+		return strings.ReplaceAll(s, old, new)
+	},
+	"repeat":    func(count int, s string) string { return strings.Repeat(s, count) },
+	"quote":     func(s string) string { return fmt.Sprintf("%q", s) },
+	"contains":  func(substr, s string) bool { return strings.Contains(s, substr) },
+	"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix": func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"split":     func(sep, s string) []string { return strings.Split(s, sep) },
+	"join": func(sep string, list interface{}) string {
+		items := toList(list)
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		return strings.Join(parts, sep)
+	},
+	"regexMatch": func(pattern, s string) (bool, error) {
+		return regexp.MatchString(pattern, s)
+	},
+	"regexFind": func(pattern, s string) (string, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", err
+		}
+		return re.FindString(s), nil
+	},
+	"regexReplaceAll": func(pattern, s, repl string) (string, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", err
+		}
+		return re.ReplaceAllString(s, repl), nil
+	},
+	"dict": func(pairs ...interface{}) (map[string]interface{}, error) {
+		if len(pairs)%2 != 0 {
+			return nil, fmt.Errorf("dict expects key value pairs, got %d arguments", len(pairs))
+		}
+		m := make(map[string]interface{}, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			m[fmt.Sprint(pairs[i])] = pairs[i+1]
+		}
+		return m, nil
+	},
+	"list": func(items ...interface{}) []interface{} { return items },
+	"hasKey": func(m map[string]interface{}, key string) bool {
+		_, ok := m[key]
+		return ok
+	},
+	"keys": func(m map[string]interface{}) []string {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	},
+	"first": func(list interface{}) interface{} {
+		if items := toList(list); len(items) > 0 {
+			return items[0]
+		}
+		return nil
+	},
+	"last": func(list interface{}) interface{} {
+		if items := toList(list); len(items) > 0 {
+			return items[len(items)-1]
+		}
+		return nil
+	},
+	"default": func(def interface{}, value ...interface{}) interface{} {
+		if len(value) == 0 || isEmpty(value[0]) {
+			return def
+		}
+		return value[0]
+	},
+	"empty": isEmpty,
+	"coalesce": func(values ...interface{}) interface{} {
+		for _, v := range values {
+			if !isEmpty(v) {
+				return v
+			}
+		}
+		return nil
+	},
+	"ternary": func(yes, no interface{}, cond bool) interface{} {
+		if cond {
+			return yes
+		}
+		return no
+	},
+	"sub": func(a, b int) int { return a - b },
+	"mul": func(a, b int) int { return a * b },
+	"div": func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return a / b, nil
+	},
+	"mod": func(a, b int) (int, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return a % b, nil
+	},
+	"readFile": func(path string) (string, error) {
+		data, err := os.ReadFile(path)
+		return string(data), err
+	},
+	"toPrettyJSON": func(v interface{}) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+}
+
+// titleCase upper cases the first letter of each word.
+func titleCase(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		defer func() { prev = r }()
+		if unicode.IsSpace(prev) || prev == '-' || prev == '_' {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// camelCase converts an identifier to lower camel case e.g. HTTPServer to httpServer.
+func camelCase(s string) string {
+	words := identifierWords(s)
+	for i := 1; i < len(words); i++ {
+		words[i] = titleCase(words[i])
+	}
+	return strings.Join(words, "")
+}
+
+// identifierWords splits an identifier into lower case words, e.g. HTTPServer_name into http,
+// server and name. Words are split on case changes, digits to letters and non alphanumerics.
+func identifierWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+// toList converts a slice or array to a list, any other value is a list of itself.
+func toList(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []interface{}{v}
+	}
+
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
+
+// isEmpty returns true for nil, zero values and empty strings, slices and maps.
+func isEmpty(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return rv.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	}
+
+	return rv.IsZero()
+}
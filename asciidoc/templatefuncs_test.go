@@ -0,0 +1,75 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelperTemplateFuncs(t *testing.T) {
+	sidecar := filepath.Join(t.TempDir(), "intro.adoc")
+	require.NoError(t, os.WriteFile(sidecar, []byte("Intro text"), 0o644))
+
+	tests := []struct {
+		template string
+		expect   string
+	}{
+		{`{{upper "abc"}} {{lower "ABC"}} {{title "hello big world"}}`, "ABC abc Hello Big World"},
+		{`{{camelcase "HTTPServer"}} {{snakecase "HTTPServerName"}} {{kebabcase "parseURL2Go"}}`, "httpServer http_server_name parse-url2-go"},
+		{`{{trimPrefix "Test" "TestFoo"}} {{trimSuffix ".go" "main.go"}} {{replace "a" "o" "banana"}}`, "Foo main bonono"},
+		{`{{repeat 3 "="}} {{quote "x"}} {{trim "  y  "}}`, `=== "x" y`},
+		{`{{contains "an" "banana"}} {{hasPrefix "ba" "banana"}} {{hasSuffix "x" "banana"}}`, "true true false"},
+		{`{{join ", " (split "/" "a/b/c")}}`, "a, b, c"},
+		{`{{regexMatch "^v[0-9]+" "v12"}} {{regexFind "[0-9]+" "abc123def"}} {{regexReplaceAll "[0-9]+" "a1b22" "N"}}`, "true 123 aNbN"},
+		{`{{$d := dict "a" 1 "b" "two"}}{{index $d "b"}} {{hasKey $d "a"}} {{join "," (keys $d)}}`, "two true a,b"},
+		{`{{$l := list 1 2 3}}{{first $l}} {{last $l}} {{len $l}}`, "1 3 3"},
+		{`{{default "none" ""}} {{default "none" "x"}} {{empty ""}} {{coalesce "" "b" "c"}}`, "none x true b"},
+		{`{{ternary "yes" "no" true}} {{sub 5 3}} {{mul 2 3}} {{div 7 2}} {{mod 7 2}}`, "yes 2 6 3 1"},
+		{`{{readFile "` + filepath.ToSlash(sidecar) + `"}}`, "Intro text"},
+		{`{{toPrettyJSON (dict "a" 1)}}`, "{\n  \"a\": 1\n}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.template, func(t *testing.T) {
+			tmpl, err := texttemplate.New("test").Funcs(helperTemplateFuncs).Parse(tc.template)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, tmpl.Execute(&buf, nil))
+			assert.Equal(t, tc.expect, buf.String())
+		})
+	}
+}
+
+func TestProducerTemplateFuncsAndData(t *testing.T) {
+	modDir, pkgDir, _ := createSampleModule(t)
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(pkgDir).
+		Outfile(filepath.Join(modDir, "docs.adoc")).
+		NoIndex().
+		TemplateFuncs(texttemplate.FuncMap{
+			"wikiLink": func(name string) string { return "https://wiki.acme.io/go/" + strings.ToLower(name) },
+		}).
+		TemplateData("company", "ACME").
+		TemplateData("owners", []string{"alice", "bob"})
+
+	overrideAllDefaults(t, p)
+	p.Override(ConstDeclarationTemplate.String(),
+		`{{.Config.Data.company}}: {{.ConstAssignment.Name}} {{wikiLink .ConstAssignment.Name}} `+
+			`{{snakecase .ConstAssignment.Name}} {{join "," .Config.Data.owners}}`,
+	)
+
+	p.Generate()
+
+	assert.Contains(t, buff.String(), "ACME: Value https://wiki.acme.io/go/value value alice,bob")
+}
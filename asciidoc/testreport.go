@@ -0,0 +1,163 @@
+package asciidoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// TestReport is the test suite of a package rendered as a standalone test report.
+type TestReport struct {
+	*goparser.GoTestSuite
+	// Document is the path, relative the test report, of the document where the package is
+	// rendered. When empty, the exercised symbols are not linked.
+	Document string
+}
+
+// TestSection are the tests of a kind in a test report.
+type TestSection struct {
+	// Title is the section title e.g. Benchmarks.
+	Title string
+	// Tests are the tests of the kind.
+	Tests []*goparser.GoTest
+}
+
+// Sections returns the tests, the benchmarks and the fuzz targets sections that have entries.
+func (r *TestReport) Sections() []TestSection {
+	var sections []TestSection
+	for _, section := range []struct {
+		title string
+		kind  goparser.GoTestKind
+	}{
+		{"Tests", goparser.GoTestKindTest},
+		{"Benchmarks", goparser.GoTestKindBenchmark},
+		{"Fuzz Targets", goparser.GoTestKindFuzz},
+	} {
+		if tests := r.OfKind(section.kind); len(tests) > 0 {
+			sections = append(sections, TestSection{Title: section.title, Tests: tests})
+		}
+	}
+
+	return sections
+}
+
+// testReportFile returns the path of the test report of the package in the directory.
+func testReportFile(dir string, pkg *goparser.GoPackage) string {
+	name := pkg.FqPackage
+	if name == "" {
+		name = pkg.Package
+	}
+
+	return filepath.Join(dir, strings.ReplaceAll(name, "/", "_")+"_test.adoc")
+}
+
+// writeTestReport writes a test report, listing the tests, benchmarks and fuzz targets of the
+// package, when a test report output directory has been configured.
+func (p *Producer) writeTestReport(t *Template, pkg *goparser.GoPackage) error {
+	if p.testReportDir == "" || pkg == nil {
+		return nil
+	}
+
+	suite, err := goparser.ParseTestSuite(p.parseconfig, pkg)
+	if err != nil {
+		return fmt.Errorf("parse tests of %s: %w", pkg.FqPackage, err)
+	}
+	if len(suite.Tests) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(p.testReportDir, 0o755); err != nil {
+		return fmt.Errorf("create test report directory %s: %w", p.testReportDir, err)
+	}
+
+	path := testReportFile(p.testReportDir, pkg)
+	p.debugf("TestReport: writing %d test(s) of %s to %s", len(suite.Tests), pkg.FqPackage, path)
+
+	report := &TestReport{GoTestSuite: suite}
+	if p.outfile != "" {
+		dir, err := filepath.Abs(p.testReportDir)
+		outfile, err2 := filepath.Abs(p.outfile)
+		if err == nil && err2 == nil {
+			if rel, err := filepath.Rel(dir, outfile); err == nil {
+				report.Document = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create test report %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := tabwriter.NewWriter(f, 4, 4, 4, ' ', 0)
+	tc := t.NewContextWithConfig(&pkg.GoFile, pkg, &TemplateContextConfig{
+		Private:       p.private,
+		TypeLinks:     p.typeLinks,
+		ExternalLinks: p.externalLinks,
+		Data:          p.templateData,
+		Filtered:      p.filtered,
+	})
+
+	tc.RenderTestReport(w, report)
+	return w.Flush()
+}
+
+// exercisedLink returns the exercised symbol, e.g. Item or Item.Reset, as a link to its type, or
+// function, in the document of the package. Symbols without an anchor are rendered as code.
+func (t *TemplateContext) exercisedLink(name string) string {
+	owner := name
+	if i := strings.Index(name, "."); i > 0 {
+		owner = name[:i]
+	}
+
+	if t.TestReport == nil || t.TestReport.Document == "" {
+		return "`" + name + "`"
+	}
+
+	pkgPath := t.packagePathForFile(nil)
+	if _, ok := t.linkableNames(t.File)[owner]; !ok || t.isFiltered(pkgPath, owner) {
+		return "`" + name + "`"
+	}
+
+	return fmt.Sprintf("<<%s#%s,%s>>", t.TestReport.Document, anchorID(pkgPath, owner), name)
+}
+
+// unexercised returns the names, sorted, of the rendered types and functions of the package that
+// no test references, neither the type itself nor any of its methods.
+func (t *TemplateContext) unexercised() []string {
+	if t.TestReport == nil {
+		return nil
+	}
+
+	exercised := map[string]struct{}{}
+	for _, test := range t.TestReport.Tests {
+		for _, name := range test.Exercises {
+			if i := strings.Index(name, "."); i > 0 {
+				name = name[:i]
+			}
+
+			exercised[name] = struct{}{}
+		}
+	}
+
+	var names []string
+	for name := range t.linkableNames(t.File) {
+		if _, ok := exercised[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// testLocation returns the file name and line of the test function e.g. shop_test.go:12.
+func testLocation(test *goparser.GoTest) string {
+	return fmt.Sprintf("%s:%d", filepath.Base(filePath(test.Function.File)), test.Function.Line)
+}
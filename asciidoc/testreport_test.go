@@ -0,0 +1,113 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestReport(t *testing.T) {
+	modDir := t.TempDir()
+	writeFiles(t, modDir, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": `package shop
+
+// Item is an item.
+type Item struct {
+	Name string
+}
+
+// NewItem creates an item.
+func NewItem(name string) *Item { return &Item{Name: name} }
+
+// Reset resets the item.
+func (i *Item) Reset() { i.Name = "" }
+
+// Open opens the shop.
+func Open() {}
+
+func helper() {}
+`,
+		"shop/shop_test.go": `package shop
+
+import "testing"
+
+// TestNewItem verifies the constructor.
+func TestNewItem(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{name: "plain", in: "x"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) { NewItem(tc.in).Reset() })
+	}
+}
+
+func FuzzNewItem(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) { helper() })
+}
+`,
+	})
+
+	reportDir := filepath.Join(modDir, "reports")
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Outfile(filepath.Join(modDir, "docs", "shop.adoc")).
+		Module(modDir).
+		Include(filepath.Join(modDir, "shop")).
+		TestReportOutputDir(reportDir).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+	p.Generate()
+
+	data, err := os.ReadFile(filepath.Join(reportDir, "example.com_shop_shop_test.adoc"))
+	require.NoError(t, err)
+	report := string(data)
+
+	assert.Contains(t, report, "= Test Report example.com/shop/shop\n")
+	assert.Contains(t, report, "The package has 1 test(s), 0 benchmark(s) and 1 fuzz target(s).")
+	assert.Contains(t, report, "|<<TestNewItem>> |test |2 |<<../docs/shop.adoc#example-com-shop-shop-Item,Item.Reset>>, <<../docs/shop.adoc#example-com-shop-shop-NewItem,NewItem>>\n")
+	assert.Contains(t, report, "== Tests\n\n[[TestNewItem]]\n=== TestNewItem\n\n_Declared in_ `shop_test.go:6`\n\nTestNewItem verifies the constructor.\n\n.Cases\n* empty\n* plain\n")
+	assert.Contains(t, report, "== Fuzz Targets\n\n[[FuzzNewItem]]\n=== FuzzNewItem\n\n_Declared in_ `shop_test.go:20`\n")
+	assert.NotContains(t, report, "== Benchmarks")
+
+	// The unexported helper has no anchor unless private symbols are rendered.
+	assert.Contains(t, report, "|<<FuzzNewItem>> |fuzz |0 |`helper`\n")
+	assert.Contains(t, report, "== Not Exercised\n\nThe types and functions of the package that no test references.\n\n* <<../docs/shop.adoc#example-com-shop-shop-Open,Open>>\n")
+
+	// The tests are not part of the package documentation.
+	assert.NotContains(t, buff.String(), "TestNewItem")
+}
+
+func TestTestReportWithoutTests(t *testing.T) {
+	modDir := t.TempDir()
+	writeFiles(t, modDir, map[string]string{
+		"go.mod":       "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": "package shop\n\n// Open opens the shop.\nfunc Open() {}\n",
+	})
+
+	reportDir := filepath.Join(modDir, "reports")
+	p := NewProducer().
+		Writer(&bytes.Buffer{}).
+		Module(modDir).
+		Include(filepath.Join(modDir, "shop")).
+		TestReportOutputDir(reportDir).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+	p.Generate()
+
+	_, err := os.Stat(reportDir)
+	assert.True(t, os.IsNotExist(err))
+}
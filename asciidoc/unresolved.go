@@ -0,0 +1,135 @@
+package asciidoc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// RuleUnresolved is the rule of an unresolved declaration that has no kind.
+const RuleUnresolved = "unresolved"
+
+// modules returns the module and the workspace modules, once each.
+func (p *Producer) modules() []*goparser.GoModule {
+	var modules []*goparser.GoModule
+	seen := map[*goparser.GoModule]bool{}
+
+	add := func(m *goparser.GoModule) {
+		if m != nil && !seen[m] {
+			seen[m] = true
+			modules = append(modules, m)
+		}
+	}
+
+	add(p.parseconfig.Module)
+	if p.parseconfig.Workspace != nil {
+		for _, m := range p.parseconfig.Workspace.Modules {
+			add(m)
+		}
+	}
+
+	return modules
+}
+
+// unresolvedMarks returns the number of unresolved declarations, per module, before generating
+// so only the declarations of the run are reported.
+func (p *Producer) unresolvedMarks() map[*goparser.GoModule]int {
+	marks := map[*goparser.GoModule]int{}
+	for _, m := range p.modules() {
+		marks[m] = len(m.Unresolved)
+	}
+
+	return marks
+}
+
+// reportUnresolved reports the declarations, and type-check problems, the modules could not
+// resolve since the marks as warnings. The same problem is reported once.
+func (p *Producer) reportUnresolved(marks map[*goparser.GoModule]int) {
+	seen := map[Diagnostic]bool{}
+	for _, m := range p.modules() {
+		for _, u := range m.Unresolved[marks[m]:] {
+			rule := string(u.Kind)
+			if rule == "" {
+				rule = RuleUnresolved
+			}
+
+			// Only the first line, e.g. an unsupported type has an AST dump.
+			message, _, _ := strings.Cut(strings.TrimSpace(u.Message), "\n")
+			d := Diagnostic{
+				Severity: SeverityWarning,
+				Rule:     rule,
+				File:     u.File,
+				Line:     u.Line,
+				Column:   u.Column,
+				Message:  strings.TrimSpace(message),
+			}
+
+			if !seen[d] {
+				seen[d] = true
+				p.report(d)
+			}
+		}
+	}
+}
+
+// writeDiagnosticsReport writes the diagnostics of the run as JSON and as an asciidoc appendix
+// when configured. The paths are relative the working directory.
+func (p *Producer) writeDiagnosticsReport() error {
+	if p.diagnosticsPath == "" && p.diagnosticsAppendix == "" {
+		return nil
+	}
+
+	diagnostics := p.diagnostics
+	if wd, err := os.Getwd(); err == nil {
+		diagnostics = RelativeDiagnostics(wd, diagnostics)
+	}
+
+	if p.diagnosticsPath != "" {
+		p.debugf("Diagnostics: writing %d diagnostic(s) to %s", len(diagnostics), p.diagnosticsPath)
+		if err := writeFile(p.diagnosticsPath, func(f *os.File) error {
+			return WriteDiagnostics(f, DiagnosticsJSON, diagnostics)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if p.diagnosticsAppendix != "" {
+		p.debugf("Diagnostics: writing appendix to %s", p.diagnosticsAppendix)
+		return writeFile(p.diagnosticsAppendix, func(f *os.File) error {
+			file := &goparser.GoFile{Module: p.parseconfig.Module}
+			tc := p.CreateTemplateWithOverrides().NewContextWithConfig(file, nil, &TemplateContextConfig{
+				Private: p.private,
+				Data:    p.templateData,
+			})
+
+			tc.RenderDiagnostics(f, diagnostics)
+			return nil
+		})
+	}
+
+	return nil
+}
+
+// writeFile creates the file, and its directory, and writes it using write.
+func writeFile(path string, write func(f *os.File) error) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create directory %s: %w", dir, err)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package asciidoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnresolvedDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": `package shop
+
+// Item is an item.
+type Item struct {
+	// Debug enables debugging.
+	Debug bool ` + "`example:\"yes\"`" + `
+}
+
+// Open opens | the shop.
+func Open() Missing { return nil }
+`,
+	})
+
+	out := filepath.Join(dir, "out")
+	p := NewProducer().
+		Writer(&bytes.Buffer{}).
+		Module(filepath.Join(dir, "go.mod")).
+		Include(filepath.Join(dir, "shop")).
+		DiagnosticsOutput(filepath.Join(out, "diagnostics.json")).
+		DiagnosticsAppendix(filepath.Join(out, "diagnostics.adoc")).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+	p.Generate()
+
+	file := filepath.Join(dir, "shop", "shop.go")
+	expected := []Diagnostic{
+		{SeverityWarning, string(goparser.UnresolvedTypeCheck), file, 10, 13,
+			"type-check error for example.com/shop/shop: undefined: Missing"},
+		{SeverityWarning, string(goparser.UnresolvedExample), file, 6, 2,
+			"field Debug: invalid example tag value \"yes\": expected a bool"},
+	}
+	assert.Equal(t, expected, p.Diagnostics())
+
+	data, err := os.ReadFile(filepath.Join(out, "diagnostics.json"))
+	require.NoError(t, err)
+
+	var written []Diagnostic
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, expected, written)
+
+	data, err = os.ReadFile(filepath.Join(out, "diagnostics.adoc"))
+	require.NoError(t, err)
+
+	appendix := string(data)
+	assert.Contains(t, appendix, "[appendix]\n== Diagnostics\n")
+	assert.Contains(t, appendix, "2 problem(s) were found")
+	assert.Contains(t, appendix, "|warning |type-check |`"+file+":10:13` |type-check error for example.com/shop/shop: undefined: Missing\n")
+
+	// A second run reports the problems of that run only.
+	p.Generate()
+	assert.Equal(t, expected, p.Diagnostics())
+}
+
+func TestDiagnosticsAppendixWithoutProblems(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":       "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": "package shop\n\n// Open opens the shop.\nfunc Open() {}\n",
+	})
+
+	appendix := filepath.Join(dir, "diagnostics.adoc")
+	p := NewProducer().
+		Writer(&bytes.Buffer{}).
+		Module(filepath.Join(dir, "go.mod")).
+		Include(filepath.Join(dir, "shop")).
+		DiagnosticsAppendix(appendix).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+	p.Generate()
+
+	assert.Empty(t, p.Diagnostics())
+
+	data, err := os.ReadFile(appendix)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "No problems were found when the documentation was generated.")
+	assert.NotContains(t, string(data), "|===")
+}
+
+func TestSummarizeDiagnostics(t *testing.T) {
+	assert.Empty(t, SummarizeDiagnostics(nil))
+	assert.Equal(t, "3 problem(s): type-check 2, example 1", SummarizeDiagnostics([]Diagnostic{
+		{Rule: "type-check"}, {Rule: "example"}, {Rule: "type-check"},
+	}))
+}
+
+func TestRelativeDiagnostics(t *testing.T) {
+	dir := filepath.Join(string(filepath.Separator)+"src", "shop")
+	diagnostics := []Diagnostic{
+		{File: filepath.Join(dir, "shop", "shop.go")},
+		{File: filepath.Join(string(filepath.Separator)+"other", "x.go")},
+		{File: "rel.go"},
+		{},
+	}
+
+	relative := RelativeDiagnostics(dir, diagnostics)
+	assert.Equal(t, filepath.Join("shop", "shop.go"), relative[0].File)
+	assert.Equal(t, diagnostics[1].File, relative[1].File)
+	assert.Equal(t, "rel.go", relative[2].File)
+	assert.Empty(t, relative[3].File)
+
+	// The diagnostics are not modified.
+	assert.Equal(t, filepath.Join(dir, "shop", "shop.go"), diagnostics[0].File)
+	assert.Nil(t, RelativeDiagnostics(dir, nil))
+}
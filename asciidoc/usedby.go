@@ -0,0 +1,333 @@
+package asciidoc
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// UsedByMode determines which references are collected for the "Used By" sections of types.
+type UsedByMode int
+
+const (
+	// UsedByDisabled do not render any "Used By" sections (default)
+	UsedByDisabled UsedByMode = iota
+	// UsedByModule collects references from the module of the type
+	UsedByModule
+	// UsedByWorkspace collects references from all modules in the workspace
+	UsedByWorkspace
+)
+
+// UsedByRef is a symbol that uses a type in its signature, field or type definition.
+type UsedByRef struct {
+	// Kind is function, method, field, interface method or type.
+	Kind string
+	// Name is the name of the symbol, members are prefixed with the owner e.g. Producer.Outfile.
+	Name string
+	// Package is the fully qualified package of the symbol.
+	Package string
+	// Anchor is the anchor of the section that documents the symbol (or its owner).
+	Anchor string
+	// Module is the module where the symbol resides, nil if not known.
+	Module *goparser.GoModule
+}
+
+// UsedByIndex maps the anchor of a type onto the symbols that use it.
+type UsedByIndex map[string][]UsedByRef
+
+// NewUsedByIndex builds the reverse reference index of all packages. When mode is
+// UsedByWorkspace, references from other modules in the workspace are included as well.
+// Unexported symbols are only included when private is set.
+func NewUsedByIndex(
+	packages []*goparser.GoPackage,
+	mode UsedByMode,
+	workspace *goparser.GoWorkspace,
+	private bool,
+) UsedByIndex {
+	index := UsedByIndex{}
+	if mode == UsedByDisabled {
+		return index
+	}
+
+	seen := map[string]struct{}{}
+	for _, pkg := range packages {
+		if pkg == nil {
+			continue
+		}
+
+		t := &TemplateContext{File: &pkg.GoFile, Package: pkg, Module: pkg.Module}
+		if mode == UsedByWorkspace {
+			t.Workspace = workspace
+		}
+
+		pkgPath := t.packagePathForFile(&pkg.GoFile)
+		add := func(ref UsedByRef, scope map[string]struct{}, types ...*goparser.GoType) {
+			ref.Package = pkgPath
+			ref.Module = pkg.Module
+
+			refs := map[string]struct{}{}
+			for _, gt := range types {
+				t.referencedTypes(gt, scope, refs)
+			}
+
+			for key := range refs {
+				if key == ref.Anchor {
+					// Members of the type itself are documented in its section
+					continue
+				}
+
+				id := key + "|" + ref.Kind + "|" + ref.Anchor + "|" + ref.Name
+				if _, ok := seen[id]; ok {
+					continue
+				}
+
+				seen[id] = struct{}{}
+				index[key] = append(index[key], ref)
+			}
+		}
+
+		for _, s := range pkg.Structs {
+			if s == nil || !(s.Exported || private) || s.Directives.Ignored() {
+				continue
+			}
+
+			scope := t.typeParamSet(s.TypeParams)
+			for _, f := range s.Fields {
+				if f == nil || f.TypeInfo == nil || !(f.Exported || private) || f.Directives.Ignored() {
+					continue
+				}
+
+				name := f.Name
+				if name == "" {
+					name = baseTypeIdentifier(f.Type)
+				}
+
+				add(UsedByRef{
+					Kind:   "field",
+					Name:   s.Name + "." + name,
+					Anchor: anchorID(pkgPath, s.Name),
+				}, scope, f.TypeInfo)
+			}
+		}
+
+		for _, i := range pkg.Interfaces {
+			if i == nil || !(i.Exported || private) || i.Directives.Ignored() {
+				continue
+			}
+
+			for _, m := range i.Methods {
+				if m == nil || !(m.Exported || private) || m.Directives.Ignored() {
+					continue
+				}
+
+				add(UsedByRef{
+					Kind:   "interface method",
+					Name:   i.Name + "." + m.Name,
+					Anchor: anchorID(pkgPath, i.Name),
+				}, t.typeParamSet(i.TypeParams, m.TypeParams), signatureTypes(m)...)
+			}
+		}
+
+		for _, c := range pkg.CustomTypes {
+			if c == nil || c.TypeInfo == nil || !(c.Exported || private) || c.Directives.Ignored() {
+				continue
+			}
+
+			add(UsedByRef{
+				Kind:   "type",
+				Name:   c.Name,
+				Anchor: anchorID(pkgPath, c.Name),
+			}, t.typeParamSet(c.TypeParams), c.TypeInfo)
+		}
+
+		for _, m := range pkg.CustomFuncs {
+			if m == nil || !(m.Exported || private) || m.Directives.Ignored() {
+				continue
+			}
+
+			add(UsedByRef{
+				Kind:   "type",
+				Name:   m.Name,
+				Anchor: anchorID(pkgPath, m.Name),
+			}, t.typeParamSet(m.TypeParams), signatureTypes(m)...)
+		}
+
+		for _, fn := range pkg.StructMethods {
+			if fn == nil || !(fn.Exported || private) || fn.Directives.Ignored() {
+				continue
+			}
+
+			types := signatureTypes(&fn.GoMethod)
+			if len(fn.ReceiverTypes) == 0 {
+				add(UsedByRef{
+					Kind:   "function",
+					Name:   fn.Name,
+					Anchor: anchorID(pkgPath, fn.Name),
+				}, t.typeParamSet(fn.TypeParams), types...)
+				continue
+			}
+
+			owner := baseTypeIdentifier(fn.ReceiverTypes[0].Type)
+			if owner == "" || !(token.IsExported(owner) || private) {
+				continue
+			}
+
+			add(UsedByRef{
+				Kind:   "method",
+				Name:   owner + "." + fn.Name,
+				Anchor: anchorID(pkgPath, owner),
+			}, t.typeParamSet(fn.TypeParams, t.receiverOwnerTypeParams(fn)), types...)
+		}
+	}
+
+	for key, refs := range index {
+		sort.SliceStable(refs, func(i, j int) bool {
+			if refs[i].Package != refs[j].Package {
+				return refs[i].Package < refs[j].Package
+			}
+			if refs[i].Name != refs[j].Name {
+				return refs[i].Name < refs[j].Name
+			}
+			return refs[i].Kind < refs[j].Kind
+		})
+		index[key] = refs
+	}
+
+	return index
+}
+
+// referencedTypes adds the anchors of all internal types referenced by the type to refs.
+func (t *TemplateContext) referencedTypes(
+	gt *goparser.GoType,
+	scope map[string]struct{},
+	refs map[string]struct{},
+) {
+	if gt == nil {
+		return
+	}
+
+	switch gt.Kind {
+	case goparser.TypeKindIdent, goparser.TypeKindSelector:
+		if anchor := t.internalTypeAnchor(gt.Type, gt.File, scope); anchor != "" {
+			refs[anchor] = struct{}{}
+		}
+		return
+	case goparser.TypeKindUnknown:
+		if len(gt.Inner) == 0 {
+			if anchor := t.internalTypeAnchor(gt.Type, gt.File, scope); anchor != "" {
+				refs[anchor] = struct{}{}
+			}
+			return
+		}
+	}
+
+	for _, inner := range gt.Inner {
+		t.referencedTypes(inner, scope, refs)
+	}
+}
+
+// internalTypeAnchor resolves a type identifier, as written in the file, onto the anchor of
+// the type. It returns an empty string for builtin, type parameter and external types.
+func (t *TemplateContext) internalTypeAnchor(
+	name string,
+	file *goparser.GoFile,
+	scope map[string]struct{},
+) string {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(name), "~")
+	if trimmed == "" {
+		return ""
+	}
+	if _, ok := scope[trimmed]; ok {
+		return ""
+	}
+	if _, ok := builtinTypes[trimmed]; ok {
+		return ""
+	}
+
+	idx := strings.Index(trimmed, ".")
+	if idx == -1 {
+		pkgPath := t.packagePathForFile(file)
+		if pkgPath == "" {
+			return ""
+		}
+		return anchorID(pkgPath, trimmed)
+	}
+
+	importPath := t.importPathForAlias(trimmed[:idx], file)
+	if !t.isInternalImport(importPath) {
+		return ""
+	}
+
+	return anchorID(importPath, trimmed[idx+1:])
+}
+
+// usedBy renders the "Used By" section of a struct, interface or custom type. It lists, with
+// links, all functions, methods, fields, interface methods and types that use the type. It
+// returns an empty string when there are no references or the index is disabled.
+func (t *TemplateContext) usedBy(node interface{}) string {
+	if t.Config == nil || len(t.Config.UsedBy) == 0 {
+		return ""
+	}
+
+	var name string
+	var file *goparser.GoFile
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		name, file = n.Name, n.File
+	case *goparser.GoInterface:
+		name, file = n.Name, n.File
+	case *goparser.GoCustomType:
+		name, file = n.Name, n.File
+	default:
+		return ""
+	}
+
+	pkgPath := t.packagePathForFile(file)
+	refs := t.Config.UsedBy[anchorID(pkgPath, name)]
+	if len(refs) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("==== Used By\n")
+	for _, ref := range refs {
+		display := ref.Name
+		if ref.Package != pkgPath {
+			display = path.Base(ref.Package) + "." + ref.Name
+		}
+
+		sb.WriteString(fmt.Sprintf("\n* %s (%s)", t.usedByLink(ref, display), ref.Kind))
+	}
+
+	return sb.String()
+}
+
+// usedByLink links to the section of the referencing symbol. In separate sub-module mode,
+// symbols in other modules are linked to the document of that module.
+func (t *TemplateContext) usedByLink(ref UsedByRef, display string) string {
+	if t.Config.SubModuleMode == SubModuleSeparate && ref.Module != nil {
+		current := t.Module
+		if current == nil && t.File != nil {
+			current = t.File.Module
+		}
+
+		if current != nil && current.Name != ref.Module.Name {
+			return fmt.Sprintf(
+				"link:%s.adoc#%s[%s]", goparser.ModuleShortName(ref.Module), ref.Anchor, display,
+			)
+		}
+	}
+
+	return fmt.Sprintf("<<%s,%s>>", ref.Anchor, display)
+}
+
+// signatureTypes returns the parameter and result types of a method.
+func signatureTypes(m *goparser.GoMethod) []*goparser.GoType {
+	types := make([]*goparser.GoType, 0, len(m.Params)+len(m.Results))
+	types = append(types, m.Params...)
+	return append(types, m.Results...)
+}
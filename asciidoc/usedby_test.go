@@ -0,0 +1,144 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsedByRendering(t *testing.T) {
+	modDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"model/model.go": `package model
+
+// Money is an amount in cents.
+type Money int64
+
+// Item is a sellable item.
+type Item struct {
+	Price Money
+}
+
+// Clone returns a copy of the item.
+func (i *Item) Clone() *Item { return i }
+
+// Prices is a list of prices.
+type Prices []Money
+
+// Pricer prices items.
+type Pricer interface {
+	Price(item Item) Money
+}
+`,
+		"cart/cart.go": `package cart
+
+import "example.com/shop/model"
+
+// Cart holds items.
+type Cart struct {
+	Items []*model.Item
+	total model.Money
+}
+
+// Total sums the cart.
+func (c *Cart) Total() model.Money { return c.total }
+
+// New creates a cart with the items.
+func New(items ...model.Item) *Cart { return nil }
+
+func helper(p model.Pricer) {}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(modDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	render := func(mode UsedByMode) string {
+		var buff bytes.Buffer
+		p := NewProducer().
+			Writer(&buff).
+			Module(modDir).
+			Include(modDir).
+			NoIndex().
+			UsedBy(mode)
+
+		overrideAllDefaults(t, p)
+
+		p.Generate()
+		return buff.String()
+	}
+
+	doc := render(UsedByDisabled)
+	assert.NotContains(t, doc, "==== Used By")
+
+	doc = render(UsedByModule)
+	section := func(heading string) string {
+		start := strings.Index(doc, heading+"\n")
+		require.NotEqual(t, -1, start, heading)
+		end := strings.Index(doc[start+len(heading):], "\n=== ")
+		if end == -1 {
+			return doc[start:]
+		}
+		return doc[start : start+len(heading)+end]
+	}
+
+	money := section("=== Money")
+	assert.Contains(t, money, "==== Used By\n\n")
+	assert.Contains(t, money, "* <<example-com-shop-cart-Cart,cart.Cart.Total>> (method)")
+	assert.Contains(t, money, "* <<example-com-shop-model-Item,Item.Price>> (field)")
+	assert.Contains(t, money, "* <<example-com-shop-model-Prices,Prices>> (type)")
+	assert.Contains(t, money, "* <<example-com-shop-model-Pricer,Pricer.Price>> (interface method)")
+	assert.NotContains(t, money, "cart.Cart.total", "unexported fields are not listed")
+
+	item := section("=== Item")
+	assert.Contains(t, item, "* <<example-com-shop-cart-Cart,cart.Cart.Items>> (field)")
+	assert.Contains(t, item, "* <<example-com-shop-cart-New,cart.New>> (function)")
+	assert.Contains(t, item, "* <<example-com-shop-model-Pricer,Pricer.Price>> (interface method)")
+	assert.NotContains(t, item, "Item.Clone", "members of the type itself are not listed")
+
+	assert.NotContains(t, section("=== Pricer"), "==== Used By", "unexported functions are not listed")
+	assert.Contains(t, doc, "[[example-com-shop-cart-New]]\n\n=== New", "functions have anchors")
+}
+
+func TestUsedByIndexSkipsTypeParameters(t *testing.T) {
+	modDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/generic\n\ngo 1.21\n",
+		"set/set.go": `package set
+
+// T is a type that clashes with a type parameter name.
+type T struct{}
+
+// Set is a set of values.
+type Set[T comparable] struct {
+	Values []T
+}
+
+// Add adds a value.
+func (s *Set[T]) Add(v T) {}
+
+// Map maps the values.
+func Map[T any](v T) T { return v }
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(modDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	p := NewProducer().Module(modDir).Include(modDir).UsedBy(UsedByModule)
+	packages, err := p.collectAllPackages()
+	require.NoError(t, err)
+
+	index := NewUsedByIndex(packages, UsedByModule, nil, false)
+	assert.Empty(t, index["example-com-generic-set-T"])
+}
@@ -0,0 +1,109 @@
+package asciidoc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser"
+)
+
+// symbolVariant is one build constraint variant of a symbol.
+type symbolVariant struct {
+	constraint string
+	decl       string
+	doc        string
+}
+
+// platformVariants renders the platforms where a symbol exists and, when the symbol has
+// several build constraint variants, the declaration and documentation of each variant
+// as asciidoctor-tabs tabs. Without the tabs extension, the tabs are rendered as a
+// description list. It returns an empty string for symbols without build constraints.
+func (t *TemplateContext) platformVariants(node interface{}) string {
+	file, variants := variantsOf(node)
+	if len(variants) == 0 {
+		if file == nil || file.Constraint == "" {
+			return ""
+		}
+
+		return fmt.Sprintf("*Platforms:* `%s`", file.Constraint)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("*Platforms:* ")
+	for i, v := range variants {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("`" + variantLabel(v.constraint) + "`")
+	}
+
+	sb.WriteString("\n\n[tabs]\n======\n")
+	for _, v := range variants {
+		sb.WriteString(variantLabel(v.constraint) + "::\n+\n[source, go]\n----\n" + v.decl + "\n----\n")
+
+		if doc := strings.TrimSpace(t.processDocumentation(v.doc)); doc != "" {
+			// Paragraphs are attached to the list item by list continuations
+			sb.WriteString("+\n" + strings.ReplaceAll(doc, "\n\n", "\n+\n") + "\n")
+		}
+	}
+	sb.WriteString("======")
+
+	return sb.String()
+}
+
+// variantLabel returns the label of a variant, variants without a build constraint
+// are the default variant.
+func variantLabel(constraint string) string {
+	if constraint == "" {
+		return "default"
+	}
+
+	return constraint
+}
+
+// variantsOf returns the file of the symbol and its build constraint variants, if any.
+func variantsOf(node interface{}) (*goparser.GoFile, []symbolVariant) {
+	var variants []symbolVariant
+	add := func(file *goparser.GoFile, decl, doc string) {
+		constraint := ""
+		if file != nil {
+			constraint = file.Constraint
+		}
+		variants = append(variants, symbolVariant{constraint: constraint, decl: decl, doc: doc})
+	}
+
+	switch n := node.(type) {
+	case *goparser.GoStruct:
+		for _, v := range n.Variants {
+			add(v.File, v.FullDecl, v.Doc)
+		}
+		return n.File, variants
+	case *goparser.GoInterface:
+		for _, v := range n.Variants {
+			add(v.File, v.FullDecl, v.Doc)
+		}
+		return n.File, variants
+	case *goparser.GoCustomType:
+		for _, v := range n.Variants {
+			add(v.File, v.Decl, v.Doc)
+		}
+		return n.File, variants
+	case *goparser.GoStructMethod:
+		for _, v := range n.Variants {
+			add(v.File, v.Decl, v.Doc)
+		}
+		return n.File, variants
+	case *goparser.GoMethod:
+		for _, v := range n.Variants {
+			add(v.File, v.Decl, v.Doc)
+		}
+		return n.File, variants
+	case *goparser.GoAssignment:
+		for _, v := range n.Variants {
+			add(v.File, v.Decl, v.Doc)
+		}
+		return n.File, variants
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,55 @@
+package asciidoc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlatformVariantsRendering(t *testing.T) {
+	modDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/sample\n\ngo 1.21\n",
+		"sample/open_linux.go": `package sample
+
+// Open opens the file using open(2).
+func Open(name string) error { return nil }
+`,
+		"sample/open_windows.go": `package sample
+
+// Open opens the file using CreateFile.
+func Open(name string) error { return nil }
+
+// Console is the attached console.
+var Console = "CON"
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(modDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	var buff bytes.Buffer
+	p := NewProducer().
+		Writer(&buff).
+		Module(modDir).
+		Include(filepath.Join(modDir, "sample")).
+		AllBuildTags(true).
+		NoIndex()
+
+	overrideAllDefaults(t, p)
+
+	p.Generate()
+
+	doc := buff.String()
+	assert.Equal(t, 1, strings.Count(doc, "=== Open"), "variants are merged")
+	assert.Contains(t, doc, "*Platforms:* `linux`, `windows`\n\n[tabs]\n======\nlinux::\n+\n[source, go]\n----\nfunc Open(name string) error\n----\n+\nOpen opens the file using open(2).\nwindows::\n")
+	assert.Contains(t, doc, "+\nOpen opens the file using CreateFile.\n======")
+	assert.Contains(t, doc, "*Platforms:* `windows`\n\nConsole is the attached console.")
+}
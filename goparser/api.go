@@ -232,6 +232,18 @@ func WithIgnoreMarkdownHeadings(ignore bool) Option {
 	}
 }
 
+// WithGenerated sets how files with a "// Code generated ... DO NOT EDIT." header
+// are handled. By default, generated files are included.
+//
+// Example:
+//
+//	parser := goparser.NewParser(goparser.WithGenerated(goparser.GeneratedExclude))
+func WithGenerated(policy GeneratedPolicy) Option {
+	return func(p *Parser) {
+		p.config.Generated = policy
+	}
+}
+
 // WithPath sets a virtual path for inline code parsing.
 // This is only used when calling Parser.ParseCode without an explicit path.
 //
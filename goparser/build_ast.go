@@ -5,8 +5,11 @@ package goparser
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
+	"math"
+	"strconv"
 	"strings"
 )
 
@@ -28,6 +31,8 @@ func buildVarAssignment(
 			Name:     valueSpec.Names[i].Name,
 			FullDecl: src.slice(genDecl.Pos(), genDecl.End()),
 			Exported: isExported(valueSpec.Names[i].Name),
+			Type:     assignmentType(file, info, genDecl, valueSpec, i, src),
+			Line:     src.line(valueSpec.Names[i].Pos()),
 		}
 
 		if specDecl := strings.TrimSpace(src.slice(valueSpec.Pos(), valueSpec.End())); specDecl != "" {
@@ -39,17 +44,21 @@ func buildVarAssignment(
 		if genDecl.Doc != nil {
 			goVarAssignment.Decl = strings.TrimSpace(src.slice(genDecl.Pos(), genDecl.End()))
 			goVarAssignment.Doc = docString(ctx, genDecl.Doc, valueSpec.Pos())
+			goVarAssignment.Directives = directivesOf(file, genDecl.Doc)
 		}
 
 		if valueSpec.Doc != nil {
 			goVarAssignment.Decl = strings.TrimSpace(src.slice(valueSpec.Pos(), valueSpec.End()))
 			goVarAssignment.Doc = docString(ctx, valueSpec.Doc, valueSpec.Pos())
+			goVarAssignment.Directives = directivesOf(file, valueSpec.Doc)
 		}
 
 		if genDecl.Tok == token.CONST {
 			if decl := renderConstDecl(file, info, valueSpec, i, src); decl != "" {
 				goVarAssignment.Decl = decl
 			}
+
+			goVarAssignment.Value = constantValue(info, valueSpec, i)
 		}
 
 		list = append(list, goVarAssignment)
@@ -136,6 +145,83 @@ func renderConstDeclLeft(
 	return left
 }
 
+// constantValue returns the value of the constant, strings without quotes. Without type
+// information only basic literals are known.
+func constantValue(info *types.Info, valueSpec *ast.ValueSpec, index int) string {
+	var value constant.Value
+	if info != nil {
+		if c, ok := info.Defs[valueSpec.Names[index]].(*types.Const); ok {
+			value = c.Val()
+		}
+	}
+
+	if value == nil && index < len(valueSpec.Values) {
+		if lit, ok := valueSpec.Values[index].(*ast.BasicLit); ok {
+			value = constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+		}
+	}
+
+	if value == nil {
+		return ""
+	}
+
+	switch value.Kind() {
+	case constant.Unknown:
+		return ""
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Float:
+		if f, _ := constant.Float64Val(value); !math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+
+	return value.ExactString()
+}
+
+// assignmentType returns the declared, or for constants in a block the implicitly repeated,
+// type of a variable or constant. It is empty for untyped constants and when unknown.
+func assignmentType(
+	file *GoFile,
+	info *types.Info,
+	genDecl *ast.GenDecl,
+	valueSpec *ast.ValueSpec,
+	index int,
+	src fileSource,
+) string {
+	if valueSpec.Type != nil {
+		return strings.TrimSpace(src.slice(valueSpec.Type.Pos(), valueSpec.Type.End()))
+	}
+
+	if info != nil {
+		if obj := info.Defs[valueSpec.Names[index]]; obj != nil {
+			return renderConstType(file, obj.Type())
+		}
+	}
+
+	if genDecl.Tok != token.CONST || len(valueSpec.Values) > 0 {
+		return ""
+	}
+
+	// Without type information, a constant without an expression list repeats the type of the
+	// last preceding expression list in the block.
+	var last *ast.ValueSpec
+	for _, spec := range genDecl.Specs {
+		if spec == valueSpec {
+			break
+		}
+		if vs, ok := spec.(*ast.ValueSpec); ok && len(vs.Values) > 0 {
+			last = vs
+		}
+	}
+
+	if last == nil || last.Type == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(src.slice(last.Type.Pos(), last.Type.End()))
+}
+
 // renderConstType renders the type name for a constant.
 func renderConstType(file *GoFile, typ types.Type) string {
 	if typ == nil {
@@ -246,6 +332,8 @@ func buildInterfaceMembers(
 				FullDecl:   name + src.slice(fType.Pos(), fType.End()),
 				Doc:        docString(ctx, field.Doc, field.Pos()),
 				TypeParams: buildTypeParamList(ctx, file, info, fType.TypeParams, src),
+				Directives: directivesOf(file, field.Doc),
+				Line:       src.line(field.Pos()),
 			}
 
 			methods = append(methods, goMethod)
@@ -307,6 +395,7 @@ func buildStructMethod(
 			Results:    buildTypeList(ctx, file, info, funcDecl.Type.Results, src),
 			Doc:        docString(ctx, funcDecl.Doc, funcDecl.Pos()),
 			TypeParams: buildTypeParamList(ctx, file, info, funcDecl.Type.TypeParams, src),
+			Directives: directivesOf(file, funcDecl.Doc),
 		},
 	}
 
@@ -416,6 +505,7 @@ func copyType(goType *GoType) *GoType {
 		Name:       goType.Name,
 		Underlying: goType.Underlying,
 		Kind:       goType.Kind,
+		typ:        goType.typ,
 	}
 
 }
@@ -492,10 +582,18 @@ func buildType(ctx *parseContext, file *GoFile, info *types.Info, expr ast.Expr,
 	default:
 		// Log unexpected types for debugging without polluting stdout
 		if file != nil && file.Module != nil {
-			file.Module.AddUnresolvedDeclaration(UnresolvedDecl{
+			u := UnresolvedDecl{
 				Expr:    expr,
 				Message: fmt.Sprintf("unexpected field type: %s (%T)", typeString, specType),
-			})
+				Kind:    UnresolvedType,
+				File:    file.FilePath,
+			}
+			if src.fset != nil {
+				pos := src.fset.Position(expr.Pos())
+				u.Line, u.Column = pos.Line, pos.Column
+			}
+
+			file.Module.AddUnresolvedDeclaration(u)
 		}
 	}
 
@@ -506,6 +604,7 @@ func buildType(ctx *parseContext, file *GoFile, info *types.Info, expr ast.Expr,
 		Underlying: underlyingString,
 		Inner:      innerTypes,
 		Kind:       kind,
+		typ:        info.TypeOf(expr),
 	}
 }
 
@@ -535,13 +634,14 @@ func buildGoStruct(
 			// Derives from other struct
 			typeInfo := buildType(ctx, file, info, field.Type, src)
 			goField := &GoField{
-				Struct:   goStruct,
-				File:     file,
-				Name:     "",
-				Type:     src.slice(field.Type.Pos(), field.Type.End()),
-				Decl:     src.slice(field.Type.Pos(), field.Type.End()),
-				Doc:      docString(ctx, field.Doc, field.Pos()),
-				TypeInfo: copyType(typeInfo),
+				Struct:     goStruct,
+				File:       file,
+				Name:       "",
+				Type:       src.slice(field.Type.Pos(), field.Type.End()),
+				Decl:       src.slice(field.Type.Pos(), field.Type.End()),
+				Doc:        docString(ctx, field.Doc, field.Pos()),
+				TypeInfo:   copyType(typeInfo),
+				Directives: directivesOf(file, field.Doc),
 			}
 
 			goField.Exported = isExported(goField.Type)
@@ -582,6 +682,7 @@ func buildGoStruct(
 				Doc:             docString(ctx, field.Doc, field.Pos()),
 				AnonymousStruct: anonymousStruct,
 				TypeInfo:        copyType(typeInfo),
+				Directives:      directivesOf(file, field.Doc),
 			}
 
 			if field.Tag != nil {
@@ -594,6 +695,8 @@ func buildGoStruct(
 				goField.Tag = goTag
 			}
 
+			buildFieldExamples(file, field, goField, src)
+
 			goStruct.Fields = append(goStruct.Fields, goField)
 		}
 	}
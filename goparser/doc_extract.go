@@ -5,12 +5,96 @@ package goparser
 import (
 	"go/ast"
 	"go/token"
+	"path/filepath"
+	"regexp"
 	"strings"
 )
 
-// extractDocs extracts documentation text from a comment group.
+// generatedByRegex matches the generator in a "// Code generated by X. DO NOT EDIT." header.
+var generatedByRegex = regexp.MustCompile(`^// Code generated by (.+?)[.;,]? DO NOT EDIT\.$`)
+
+// directiveRegex matches a directive comment, i.e. //tool:directive with no space after the
+// slashes, the line, extern and export directives and the nolint directive.
+var directiveRegex = regexp.MustCompile(`^//([a-z0-9]+:[a-z0-9]\S*|line|extern|export|nolint)(?:[ \t]+(.*))?$`)
+
+// parseDirective parses a directive comment or returns nil if the comment is not a directive.
+func parseDirective(file *GoFile, comment *ast.Comment) *GoDirective {
+	m := directiveRegex.FindStringSubmatch(strings.TrimRight(comment.Text, " \t\r"))
+	if m == nil {
+		return nil
+	}
+
+	directive := &GoDirective{File: file, Name: m[1], Args: strings.TrimSpace(m[2]), Text: comment.Text}
+	if strings.HasPrefix(directive.Name, "nolint:") {
+		// The linters are the arguments e.g. //nolint:errcheck,gosec // reason
+		directive.Args = strings.TrimSpace(strings.TrimPrefix(directive.Name, "nolint:") + " " + directive.Args)
+		directive.Name = "nolint"
+	}
+
+	return directive
+}
+
+// directivesOf returns the directives in the comment groups, nil groups are skipped.
+func directivesOf(file *GoFile, groups ...*ast.CommentGroup) GoDirectives {
+	var list GoDirectives
+	seen := map[*ast.CommentGroup]bool{}
+
+	for _, group := range groups {
+		if group == nil || seen[group] {
+			continue
+		}
+		seen[group] = true
+
+		for _, c := range group.List {
+			if directive := parseDirective(file, c); directive != nil {
+				list = append(list, directive)
+			}
+		}
+	}
+
+	return list
+}
+
+// fileDirectives returns the directives outside of the declarations, including their
+// doc comments, and all go:generate directives since those apply to the file.
+func fileDirectives(goFile *GoFile, file *ast.File) GoDirectives {
+	var list GoDirectives
+
+	for _, group := range file.Comments {
+		attached := false
+		for _, decl := range file.Decls {
+			start := decl.Pos()
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				if d.Doc != nil {
+					start = d.Doc.Pos()
+				}
+			case *ast.FuncDecl:
+				if d.Doc != nil {
+					start = d.Doc.Pos()
+				}
+			}
+
+			if group.Pos() >= start && group.End() <= decl.End() {
+				attached = true
+				break
+			}
+		}
+
+		for _, directive := range directivesOf(goFile, group) {
+			if !attached || directive.Name == "go:generate" {
+				list = append(list, directive)
+			}
+		}
+	}
+
+	return list
+}
+
+// extractDocs extracts documentation text from a comment group. Directives are
+// not part of the documentation.
 func extractDocs(doc *ast.CommentGroup) string {
-	d := doc.Text()
+	d := withoutDirectives(doc).Text()
 	if d == "" {
 		return d
 	}
@@ -18,6 +102,30 @@ func extractDocs(doc *ast.CommentGroup) string {
 	return d[:len(d)-1]
 }
 
+// withoutDirectives returns the comment group without directive comments (that
+// are not already removed by ast.CommentGroup.Text).
+func withoutDirectives(doc *ast.CommentGroup) *ast.CommentGroup {
+	if doc == nil {
+		return nil
+	}
+
+	for i, c := range doc.List {
+		if parseDirective(nil, c) == nil {
+			continue
+		}
+
+		list := append([]*ast.Comment{}, doc.List[:i]...)
+		for _, c := range doc.List[i+1:] {
+			if parseDirective(nil, c) == nil {
+				list = append(list, c)
+			}
+		}
+		return &ast.CommentGroup{List: list}
+	}
+
+	return doc
+}
+
 // docString extracts and optionally concatenates documentation from comment groups.
 // It supports both DocConcatenationNone and DocConcatenationFull modes.
 func docString(ctx *parseContext, doc *ast.CommentGroup, declPos token.Pos) string {
@@ -139,3 +247,37 @@ func extractBuildTagsFromComments(comments []*ast.CommentGroup) []string {
 
 	return tags
 }
+
+// generatedBy reports if the file is generated, as defined by https://go.dev/s/generatedcode,
+// and the name of the generator when the header states one. A quoted command line such as
+// "stringer -type=Pill" yields the command, stringer.
+func generatedBy(file *ast.File) (bool, string) {
+	if file == nil || !ast.IsGenerated(file) {
+		return false, ""
+	}
+
+	for _, cg := range file.Comments {
+		if cg.Pos() > file.Package {
+			break
+		}
+
+		for _, c := range cg.List {
+			m := generatedByRegex.FindStringSubmatch(c.Text)
+			if m == nil {
+				continue
+			}
+
+			generator := strings.TrimSpace(m[1])
+			if strings.HasPrefix(generator, "\"") || strings.HasPrefix(generator, "`") {
+				generator = strings.Trim(generator, "\"`")
+				if fields := strings.Fields(generator); len(fields) > 0 {
+					generator = filepath.Base(fields[0])
+				}
+			}
+
+			return true, generator
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,305 @@
+package goparser
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedBy(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		generated bool
+		generator string
+	}{
+		{name: "protoc", header: "// Code generated by protoc-gen-go. DO NOT EDIT.", generated: true, generator: "protoc-gen-go"},
+		{name: "mockgen", header: "// Code generated by MockGen. DO NOT EDIT.", generated: true, generator: "MockGen"},
+		{name: "stringer", header: `// Code generated by "stringer -type=Pill"; DO NOT EDIT.`, generated: true, generator: "stringer"},
+		{name: "sqlc", header: "// Code generated by sqlc. DO NOT EDIT.\n// versions:\n//   sqlc v1.25.0", generated: true, generator: "sqlc"},
+		{name: "no generator", header: "// Code generated from schema.json. DO NOT EDIT.", generated: true},
+		{name: "hand written", header: "// Package app does things."},
+		{name: "not a header", header: "// Code generated by hand, please edit."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "app.go", tt.header+"\n\npackage app\n", parser.ParseComments)
+			require.NoError(t, err)
+
+			generated, generator := generatedBy(file)
+			assert.Equal(t, tt.generated, generated)
+			assert.Equal(t, tt.generator, generator)
+		})
+	}
+}
+
+func TestGeneratedPolicy(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/app\n\ngo 1.24\n",
+		"app.go":         "package app\n\n// Server serves.\ntype Server struct{}\n",
+		"app_string.go":  "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n\npackage app\n\ntype Kind int\n",
+		"pb/app.pb.go":   "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage pb\n\ntype Msg struct{}\n",
+		"pb/app_grpc.go": "// Code generated by protoc-gen-go-grpc. DO NOT EDIT.\n\npackage pb\n\ntype Client struct{}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	mod, err := NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	walk := func(policy GeneratedPolicy) map[string]*GoPackage {
+		packages := map[string]*GoPackage{}
+		err := ParseSinglePackageWalker(ParseConfig{Module: mod, Generated: policy}, func(pkg *GoPackage) error {
+			packages[pkg.Package] = pkg
+			return nil
+		}, dir)
+		require.NoError(t, err)
+		return packages
+	}
+
+	packages := walk(GeneratedInclude)
+	require.Len(t, packages, 2)
+	assert.False(t, packages["app"].Generated)
+	require.Len(t, packages["app"].Files, 2)
+	assert.True(t, packages["app"].Files[1].Generated)
+	assert.Equal(t, "stringer", packages["app"].Files[1].Generator)
+	assert.True(t, packages["pb"].Generated)
+	assert.Empty(t, packages["pb"].Generator, "files have different generators")
+
+	packages = walk(GeneratedExclude)
+	require.Len(t, packages, 1)
+	require.Len(t, packages["app"].Files, 1)
+	assert.Empty(t, packages["app"].CustomTypes)
+}
+
+func TestDirectives(t *testing.T) {
+	code := `//go:build linux
+
+// Package app does things.
+package app
+
+import "embed"
+
+//go:generate stringer -type=Kind
+
+// Kind is a kind.
+//
+//go:generate enumer -type=Kind
+type Kind int
+
+// Assets are the static files.
+//
+//go:embed static/*.html "with space.txt" ` + "`raw.txt`" + `
+var Assets embed.FS
+
+// Add adds.
+//
+//nolint
+//go:noinline
+func Add(a, b int) int { return a + b }
+
+// Close closes.
+//nolint:errcheck,gosec // closed on exit
+func Close() {}
+
+// Thing is a thing.
+type Thing struct {
+	//go:notinheap
+	Value int
+}
+`
+
+	file, err := ParseInlineFile(nil, "app.go", code)
+	require.NoError(t, err)
+
+	names := func(list GoDirectives) []string {
+		var result []string
+		for _, d := range list {
+			result = append(result, d.Name+" "+d.Args)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{
+		"go:build linux",
+		"go:generate stringer -type=Kind",
+		"go:generate enumer -type=Kind",
+	}, names(file.Directives))
+	assert.Equal(t, "//go:build linux", file.Directives[0].Text)
+	assert.Equal(t, "app.go", file.Directives[0].FileName())
+	assert.Len(t, file.Directives.Named("go:generate"), 2)
+
+	require.Len(t, file.CustomTypes, 1)
+	assert.Equal(t, []string{"go:generate enumer -type=Kind"}, names(file.CustomTypes[0].Directives))
+	assert.Equal(t, "Kind is a kind.", file.CustomTypes[0].Doc)
+
+	require.Len(t, file.VarAssignments, 1)
+	assert.Equal(t, []string{"static/*.html", "with space.txt", "raw.txt"}, file.VarAssignments[0].EmbedPatterns())
+	assert.Equal(t, "Assets are the static files.", file.VarAssignments[0].Doc)
+
+	require.Len(t, file.StructMethods, 2)
+	assert.Equal(t, []string{"nolint ", "go:noinline "}, names(file.StructMethods[0].Directives))
+	assert.Equal(t, "Add adds.", file.StructMethods[0].Doc)
+	assert.Equal(t, []string{"nolint errcheck,gosec // closed on exit"}, names(file.StructMethods[1].Directives))
+	assert.Equal(t, "Close closes.", file.StructMethods[1].Doc)
+
+	require.Len(t, file.Structs, 1)
+	assert.Empty(t, file.Structs[0].Directives)
+}
+
+func TestDirectivesNotInConcatenatedDoc(t *testing.T) {
+	code := `package app
+
+// First part.
+
+//nolint
+// Second part.
+//go:noinline
+func F() {}
+`
+
+	file, err := ParseInlineFileWithConfig(ParseConfig{DocConcatenation: DocConcatenationFull}, "app.go", code)
+	require.NoError(t, err)
+	require.Len(t, file.StructMethods, 1)
+
+	assert.Equal(t, "First part.\n\nSecond part.", file.StructMethods[0].Doc)
+	assert.Len(t, file.StructMethods[0].Directives, 2)
+}
+
+func TestGoasciidocDirectives(t *testing.T) {
+	code := `package app
+
+// Login logs in.
+//
+//goasciidoc:group "Authentication"
+//goasciidoc:order 10
+func Login() {}
+
+// Logout logs out.
+//goasciidoc:group Sessions
+//goasciidoc:order last
+func Logout() {}
+
+// Helper must stay exported.
+//
+//goasciidoc:ignore
+type Helper struct {
+	// Secret is hidden.
+	//goasciidoc:ignore
+	Secret string
+}
+`
+
+	file, err := ParseInlineFile(nil, "app.go", code)
+	require.NoError(t, err)
+	require.Len(t, file.StructMethods, 2)
+	require.Len(t, file.Structs, 1)
+
+	login := file.StructMethods[0].Directives
+	assert.Equal(t, "Login logs in.", file.StructMethods[0].Doc)
+	assert.Equal(t, "Authentication", login.Group())
+	assert.False(t, login.Ignored())
+	order, ok := login.Order()
+	assert.True(t, ok)
+	assert.Equal(t, 10, order)
+
+	logout := file.StructMethods[1].Directives
+	assert.Equal(t, "Logout logs out.", file.StructMethods[1].Doc)
+	assert.Equal(t, "Sessions", logout.Group())
+	_, ok = logout.Order()
+	assert.False(t, ok)
+
+	assert.True(t, file.Structs[0].Directives.Ignored())
+	assert.Equal(t, "", file.Structs[0].Directives.Group())
+	assert.Equal(t, "Helper must stay exported.", file.Structs[0].Doc)
+	require.Len(t, file.Structs[0].Fields, 1)
+	assert.True(t, file.Structs[0].Fields[0].Directives.Ignored())
+	assert.Equal(t, "Secret is hidden.", file.Structs[0].Fields[0].Doc)
+}
+
+func TestGoasciidocCodeDirective(t *testing.T) {
+	code := `// Package app is an app.
+//
+//goasciidoc:code
+package app
+
+// Login logs in.
+//
+//goasciidoc:code off
+func Login() {}
+
+// Logout logs out.
+//goasciidoc:code
+func Logout() {}
+
+// Reset resets.
+//goasciidoc:code sometimes
+func Reset() {}
+
+// Close closes.
+func Close() {}
+`
+
+	file, err := ParseInlineFile(nil, "app.go", code)
+	require.NoError(t, err)
+	require.Len(t, file.StructMethods, 4)
+
+	enabled, ok := file.Directives.Code()
+	assert.True(t, ok)
+	assert.True(t, enabled)
+
+	tests := []struct {
+		enabled bool
+		ok      bool
+	}{
+		{false, true},
+		{true, true},
+		{false, false},
+		{false, false},
+	}
+
+	for i, tc := range tests {
+		enabled, ok := file.StructMethods[i].Directives.Code()
+		assert.Equal(t, tc.enabled, enabled, file.StructMethods[i].Name)
+		assert.Equal(t, tc.ok, ok, file.StructMethods[i].Name)
+	}
+}
+
+func TestGoasciidocNoLintDirective(t *testing.T) {
+	code := `//goasciidoc:nolint missing-overview
+package app
+
+//goasciidoc:nolint
+func Login() {}
+
+// Logout logs out.
+//goasciidoc:nolint doc-prefix, unresolved-reference // generated wording
+func Logout() {}
+
+// Close closes.
+func Close() {}
+`
+
+	file, err := ParseInlineFile(nil, "app.go", code)
+	require.NoError(t, err)
+	require.Len(t, file.StructMethods, 3)
+
+	assert.True(t, file.Directives.NoLint("missing-overview"))
+	assert.False(t, file.Directives.NoLint("missing-doc"))
+
+	assert.True(t, file.StructMethods[0].Directives.NoLint("missing-doc"))
+	assert.True(t, file.StructMethods[1].Directives.NoLint("doc-prefix"))
+	assert.True(t, file.StructMethods[1].Directives.NoLint("unresolved-reference"))
+	assert.False(t, file.StructMethods[1].Directives.NoLint("generated"))
+	assert.False(t, file.StructMethods[2].Directives.NoLint("missing-doc"))
+}
@@ -0,0 +1,381 @@
+package goparser
+
+import (
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// structField is a struct field as seen by a tag driven encoder such as
+// encoding/json. It is created either from a parsed GoField or from go/types
+// information when the struct was not parsed (e.g. it resides in a dependency).
+type structField struct {
+	// name is the Go field name, empty for embedded fields.
+	name     string
+	embedded bool
+	exported bool
+	tag      reflect.StructTag
+	doc      string
+	// typ is the go/types representation or nil when not available.
+	typ types.Type
+	// typeStr is the source representation of the type.
+	typeStr string
+	// anonymous is set when the field is declared with an inline struct.
+	anonymous *GoStruct
+	// file is the file the field was declared in, if known.
+	file *GoFile
+	// example and defaultValue are JSON encoded user provided values, if any.
+	example      string
+	defaultValue string
+}
+
+// encodedField is a structField after the tag and embedding rules of an
+// encoder have been applied.
+type encodedField struct {
+	structField
+	// key is the name of the field in the encoded document.
+	key       string
+	omitEmpty bool
+	// asString is set when the ",string" option is present.
+	asString bool
+	// opts are all options of the tag e.g. attr for `xml:"id,attr"`.
+	opts   map[string]bool
+	depth  int
+	tagged bool
+}
+
+// encoderRules describes how an encoder interprets struct tags.
+type encoderRules struct {
+	// tagKey is the struct tag key e.g. json.
+	tagKey string
+	// flattenEmbedded inlines untagged embedded structs (encoding/json semantics).
+	flattenEmbedded bool
+	// inlineOption is the tag option that inlines a field e.g. yaml ",inline".
+	inlineOption string
+	// lowerCaseKeys lower cases the Go field name when the tag has no name (yaml semantics).
+	lowerCaseKeys bool
+	// skipName is a Go field name that is never encoded as a member e.g. XMLName.
+	skipName string
+}
+
+var jsonRules = encoderRules{tagKey: "json", flattenEmbedded: true}
+
+// fieldsOfGoStruct converts the fields of a parsed struct into structFields.
+func fieldsOfGoStruct(s *GoStruct) []structField {
+	if s == nil {
+		return nil
+	}
+
+	fields := make([]structField, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		sf := structField{
+			name:         f.Name,
+			embedded:     f.Name == "",
+			exported:     f.Exported,
+			doc:          strings.TrimSpace(f.Doc),
+			typeStr:      strings.TrimSpace(f.Type),
+			anonymous:    f.AnonymousStruct,
+			file:         f.File,
+			example:      f.Example,
+			defaultValue: f.Default,
+		}
+
+		if f.Tag != nil {
+			sf.tag = reflect.StructTag(strings.ReplaceAll(f.Tag.Value, "`", ""))
+		}
+
+		if f.TypeInfo != nil {
+			sf.typ = f.TypeInfo.typ
+		}
+
+		fields = append(fields, sf)
+	}
+
+	return fields
+}
+
+// fieldsOfTypesStruct converts go/types struct fields into structFields. When
+// the parsed declaration is known, field documentation is taken from it.
+func fieldsOfTypesStruct(st *types.Struct, decl *GoStruct) []structField {
+	docs := map[string]string{}
+	var file *GoFile
+	if decl != nil {
+		file = decl.File
+		for _, f := range decl.Fields {
+			if f.Name != "" {
+				docs[f.Name] = strings.TrimSpace(f.Doc)
+			}
+		}
+	}
+
+	fields := make([]structField, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+
+		sf := structField{
+			embedded: v.Embedded(),
+			exported: v.Exported(),
+			tag:      reflect.StructTag(st.Tag(i)),
+			typ:      v.Type(),
+			typeStr:  types.TypeString(v.Type(), relativeQualifier(v.Pkg())),
+			file:     file,
+		}
+
+		if !v.Embedded() {
+			sf.name = v.Name()
+			sf.doc = docs[v.Name()]
+		}
+
+		sf.example = tagValue(sf.tag, "example", sf.typ)
+		sf.defaultValue = tagValue(sf.tag, "default", sf.typ)
+
+		fields = append(fields, sf)
+	}
+
+	return fields
+}
+
+// relativeQualifier renders package qualifiers by name, omitting pkg itself.
+func relativeQualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if pkg != nil && other.Path() == pkg.Path() {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+// parseTagValue splits a struct tag value into its name and options.
+func parseTagValue(value string) (string, map[string]bool) {
+	parts := strings.Split(value, ",")
+	opts := map[string]bool{}
+	for _, opt := range parts[1:] {
+		if opt = strings.TrimSpace(opt); opt != "" {
+			opts[opt] = true
+		}
+	}
+
+	return parts[0], opts
+}
+
+// structFieldsFor resolves the fields of the struct denoted by typ, or by the
+// type name typeStr when no type information is present. The parsed
+// declaration is used when it is registered in the module so documentation is
+// retained. It returns nil when the type is not a struct.
+func structFieldsFor(typ types.Type, typeStr string, file *GoFile) []structField {
+	if typ != nil {
+		typ = derefType(typ)
+
+		if named, ok := typ.(*types.Named); ok {
+			st, ok := named.Underlying().(*types.Struct)
+			if !ok {
+				return nil
+			}
+
+			decl := lookupNamedStruct(named, file)
+			if decl != nil && named.TypeArgs().Len() == 0 {
+				return fieldsOfGoStruct(decl)
+			}
+
+			return fieldsOfTypesStruct(st, decl)
+		}
+
+		if st, ok := typ.Underlying().(*types.Struct); ok {
+			return fieldsOfTypesStruct(st, nil)
+		}
+
+		return nil
+	}
+
+	if decl := lookupLocalStruct(typeStr, file); decl != nil {
+		return fieldsOfGoStruct(decl)
+	}
+
+	return nil
+}
+
+// lookupNamedStruct finds the parsed declaration of a named struct type.
+func lookupNamedStruct(named *types.Named, file *GoFile) *GoStruct {
+	if file == nil || file.Module == nil || named.Obj().Pkg() == nil {
+		return nil
+	}
+
+	return file.Module.LookupStruct(named.Obj().Pkg().Path(), named.Obj().Name())
+}
+
+// lookupLocalStruct finds a parsed struct declared in the same package as file
+// by its (possibly pointer) type name.
+func lookupLocalStruct(typeStr string, file *GoFile) *GoStruct {
+	if file == nil || file.Module == nil {
+		return nil
+	}
+
+	name := strings.TrimPrefix(strings.TrimSpace(typeStr), "*")
+	if name == "" || strings.ContainsAny(name, ".[]{}() ") {
+		return nil
+	}
+
+	return file.Module.LookupStruct(file.FqPackage, name)
+}
+
+// qualifiedName returns the fully qualified name of a package level object e.g.
+// encoding/json.RawMessage, or an empty string for universe objects.
+func qualifiedName(obj types.Object) string {
+	if obj.Pkg() == nil {
+		return ""
+	}
+
+	return obj.Pkg().Path() + "." + obj.Name()
+}
+
+// derefType strips any pointer indirections.
+func derefType(typ types.Type) types.Type {
+	for {
+		ptr, ok := types.Unalias(typ).(*types.Pointer)
+		if !ok {
+			return types.Unalias(typ)
+		}
+		typ = ptr.Elem()
+	}
+}
+
+// encodedFields applies the encoder rules to fields, including inlining of
+// embedded structs and the encoding/json dominance rules for duplicate keys.
+// The fields are returned in declaration order.
+func encodedFields(rules encoderRules, fields []structField) []encodedField {
+	all := collectEncodedFields(rules, fields, 0, map[string]bool{})
+
+	// As encoding/json, the shallowest field of a key dominates. Among fields at the same depth
+	// a single tagged field dominates, otherwise the fields conflict and the key is dropped.
+	best := map[string]int{}
+	dropped := map[string]bool{}
+	for i, f := range all {
+		j, seen := best[f.key]
+		if !seen {
+			best[f.key] = i
+			continue
+		}
+
+		other := all[j]
+		switch {
+		case f.depth < other.depth:
+			best[f.key] = i
+			delete(dropped, f.key)
+		case f.depth > other.depth:
+		case f.tagged && !other.tagged:
+			best[f.key] = i
+			delete(dropped, f.key)
+		case f.tagged == other.tagged:
+			dropped[f.key] = true
+		}
+	}
+
+	result := make([]encodedField, 0, len(best))
+	for i, f := range all {
+		if best[f.key] == i && !dropped[f.key] {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+func collectEncodedFields(
+	rules encoderRules,
+	fields []structField,
+	depth int,
+	visiting map[string]bool,
+) []encodedField {
+	result := []encodedField{}
+
+	for _, f := range fields {
+		tagValue, hasTag := f.tag.Lookup(rules.tagKey)
+		if tagValue == "-" || (rules.skipName != "" && f.name == rules.skipName) {
+			continue
+		}
+
+		name, opts := parseTagValue(tagValue)
+
+		inline := (f.embedded && rules.flattenEmbedded && name == "") ||
+			(rules.inlineOption != "" && opts[rules.inlineOption])
+
+		if inline {
+			key := embeddedTypeKey(f)
+			if !visiting[key] {
+				if nested := structFieldsFor(f.typ, f.typeStr, f.file); nested != nil {
+					visiting[key] = true
+					result = append(result, collectEncodedFields(rules, nested, depth+1, visiting)...)
+					delete(visiting, key)
+					continue
+				}
+			}
+		}
+
+		exported := f.exported
+		if f.embedded {
+			exported = token.IsExported(embeddedFieldName(f))
+		}
+		if !exported {
+			continue
+		}
+
+		key := name
+		if key == "" {
+			key = f.name
+		}
+		if key == "" {
+			key = embeddedFieldName(f)
+		}
+		if name == "" && rules.lowerCaseKeys {
+			key = strings.ToLower(key)
+		}
+		if key == "" {
+			continue
+		}
+
+		result = append(result, encodedField{
+			structField: f,
+			key:         key,
+			omitEmpty:   opts["omitempty"] || opts["omitzero"],
+			asString:    opts["string"],
+			opts:        opts,
+			depth:       depth,
+			tagged:      hasTag && name != "",
+		})
+	}
+
+	return result
+}
+
+// embeddedFieldName is the implicit field name of an embedded field.
+func embeddedFieldName(f structField) string {
+	if f.name != "" {
+		return f.name
+	}
+
+	if f.typ != nil {
+		if named, ok := derefType(f.typ).(*types.Named); ok {
+			return named.Obj().Name()
+		}
+	}
+
+	name := strings.TrimPrefix(f.typeStr, "*")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+// embeddedTypeKey identifies the type of an embedded field to detect cycles.
+func embeddedTypeKey(f structField) string {
+	if f.typ != nil {
+		return derefType(f.typ).String()
+	}
+
+	return strings.TrimPrefix(f.typeStr, "*")
+}
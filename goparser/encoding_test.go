@@ -0,0 +1,38 @@
+package goparser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodedFieldsDominance(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields [][2]string
+		expect []string
+	}{
+		{"unique", [][2]string{{"A", `json:"a"`}, {"B", ""}}, []string{"a=A", "B=B"}},
+		{"tagged conflict", [][2]string{{"A", `json:"X"`}, {"B", `json:"X"`}}, []string{}},
+		{"tagged dominates untagged", [][2]string{{"X", ""}, {"A", `json:"X"`}}, []string{"X=A"}},
+		{"untagged after tagged conflict", [][2]string{{"A", `json:"X"`}, {"B", `json:"X"`}, {"X", ""}}, []string{}},
+		{"tagged conflict around untagged", [][2]string{{"A", `json:"X"`}, {"X", ""}, {"B", `json:"X"`}}, []string{}},
+		{"omitempty name", [][2]string{{"A", `json:",omitempty"`}, {"B", `json:"A"`}}, []string{"A=B"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var fields []structField
+			for _, f := range tc.fields {
+				fields = append(fields, structField{name: f[0], exported: true, tag: reflect.StructTag(f[1])})
+			}
+
+			keys := []string{}
+			for _, f := range encodedFields(jsonRules, fields) {
+				keys = append(keys, f.key+"="+f.name)
+			}
+			assert.Equal(t, tc.expect, keys)
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package goparser
+
+import (
+	"go/types"
+	"strings"
+)
+
+// exampleMaxDepth is the maximum number of nested named structs an example
+// recurses into before rendering an empty object.
+const exampleMaxDepth = 6
+
+// exampleKind classifies a node in an example document.
+type exampleKind int
+
+const (
+	// exampleScalar is a value that is already encoded e.g. "example" or 0.
+	exampleScalar exampleKind = iota
+	exampleObject
+	exampleArray
+)
+
+// exampleNode is an encoder neutral example value that is serialized into JSON
+// or YAML.
+type exampleNode struct {
+	kind exampleKind
+	// scalar is the encoded value when kind is exampleScalar.
+	scalar string
+	// keys and values are the object members in order, options are the
+	// struct tag options of each member.
+	keys    []string
+	values  []*exampleNode
+	options []map[string]bool
+	// items are the array elements.
+	items []*exampleNode
+}
+
+func scalarExample(value string) *exampleNode {
+	return &exampleNode{kind: exampleScalar, scalar: value}
+}
+
+func (n *exampleNode) set(key string, value *exampleNode) {
+	n.setWithOptions(key, value, nil)
+}
+
+func (n *exampleNode) setWithOptions(key string, value *exampleNode, options map[string]bool) {
+	n.keys = append(n.keys, key)
+	n.values = append(n.values, value)
+	n.options = append(n.options, options)
+}
+
+var yamlRules = encoderRules{tagKey: "yaml", inlineOption: "inline", lowerCaseKeys: true}
+
+// exampleBuilder creates example documents by following the field types
+// through go/types, or the parsed package set when type information is missing.
+type exampleBuilder struct {
+	rules    encoderRules
+	registry *ExampleRegistry
+	// visiting contains the named structs currently being expanded.
+	visiting map[string]bool
+	depth    int
+}
+
+func newExampleBuilder(rules encoderRules, registry *ExampleRegistry) *exampleBuilder {
+	return &exampleBuilder{rules: rules, registry: registry, visiting: map[string]bool{}}
+}
+
+// example builds the example document for the struct.
+func (s *GoStruct) example(rules encoderRules) *exampleNode {
+	var b *exampleBuilder
+	if s.File != nil {
+		b = newExampleBuilder(rules, s.File.Module.exampleRegistry())
+	} else {
+		b = newExampleBuilder(rules, defaultExampleRegistry)
+	}
+
+	if s.File != nil && s.File.FqPackage != "" {
+		b.visiting[s.File.FqPackage+"."+s.Name] = true
+	}
+
+	return b.structExample(fieldsOfGoStruct(s))
+}
+
+func (b *exampleBuilder) structExample(fields []structField) *exampleNode {
+	node := &exampleNode{kind: exampleObject}
+
+	for _, f := range encodedFields(b.rules, fields) {
+		var value *exampleNode
+		switch {
+		case f.example != "":
+			value = scalarExample(f.example)
+		case f.defaultValue != "":
+			value = scalarExample(f.defaultValue)
+		case f.anonymous != nil:
+			value = b.structExample(fieldsOfGoStruct(f.anonymous))
+		default:
+			value = b.exampleFor(f.typ, f.typeStr, f.file)
+		}
+
+		if f.asString && value.kind == exampleScalar && !strings.ContainsAny(value.scalar[:1], `"{[`) {
+			value = scalarExample(`"` + value.scalar + `"`)
+		}
+
+		node.setWithOptions(f.key, value, f.opts)
+	}
+
+	return node
+}
+
+func (b *exampleBuilder) exampleFor(typ types.Type, typeStr string, file *GoFile) *exampleNode {
+	if typ == nil {
+		return b.exampleForTypeString(typeStr, file)
+	}
+
+	// Well-known types may be aliases e.g. encoding/json.RawMessage
+	if alias, ok := typ.(*types.Alias); ok {
+		if value, ok := b.registry.lookupEncoded(qualifiedName(alias.Obj())); ok {
+			return scalarExample(value)
+		}
+	}
+
+	switch t := types.Unalias(typ).(type) {
+	case *types.Named:
+		return b.namedExample(t, file)
+	case *types.Pointer:
+		return b.exampleFor(t.Elem(), "", file)
+	case *types.Basic:
+		return scalarExample(basicExample(t))
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			// base64 of "example"
+			return scalarExample(`"ZXhhbXBsZQ=="`)
+		}
+		return &exampleNode{kind: exampleArray, items: []*exampleNode{b.exampleFor(t.Elem(), "", file)}}
+	case *types.Array:
+		return &exampleNode{kind: exampleArray, items: []*exampleNode{b.exampleFor(t.Elem(), "", file)}}
+	case *types.Map:
+		node := &exampleNode{kind: exampleObject}
+		node.set(mapKeyExample(t.Key()), b.exampleFor(t.Elem(), "", file))
+		return node
+	case *types.Struct:
+		return b.structExample(fieldsOfTypesStruct(t, nil))
+	case *types.Interface:
+		return &exampleNode{kind: exampleObject}
+	}
+
+	return scalarExample("null")
+}
+
+func (b *exampleBuilder) namedExample(named *types.Named, file *GoFile) *exampleNode {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		// Universe types such as error
+		return scalarExample(`"value"`)
+	}
+
+	if value, ok := b.registry.lookupEncoded(qualifiedName(obj)); ok {
+		return scalarExample(value)
+	}
+
+	// Custom marshallers do not reveal their encoding, render them as strings
+	if hasMethod(named, "MarshalJSON") || hasMethod(named, "MarshalText") {
+		return scalarExample(`"value"`)
+	}
+
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return b.exampleFor(named.Underlying(), "", file)
+	}
+
+	key := named.String()
+	if b.visiting[key] {
+		return scalarExample("null")
+	}
+
+	if b.depth >= exampleMaxDepth {
+		return &exampleNode{kind: exampleObject}
+	}
+
+	b.visiting[key] = true
+	b.depth++
+	defer func() {
+		delete(b.visiting, key)
+		b.depth--
+	}()
+
+	return b.structExample(structFieldsFor(named, "", file))
+}
+
+// exampleForTypeString derives an example from the source representation of a
+// type when no type information is available.
+func (b *exampleBuilder) exampleForTypeString(typeStr string, file *GoFile) *exampleNode {
+	typeStr = strings.TrimSpace(typeStr)
+
+	switch {
+	case strings.HasPrefix(typeStr, "*"):
+		return b.exampleForTypeString(typeStr[1:], file)
+	case strings.HasPrefix(typeStr, "[]"):
+		return &exampleNode{kind: exampleArray, items: []*exampleNode{b.exampleForTypeString(typeStr[2:], file)}}
+	case strings.HasPrefix(typeStr, "map["):
+		return &exampleNode{kind: exampleObject}
+	}
+
+	if decl := lookupLocalStruct(typeStr, file); decl != nil {
+		key := file.FqPackage + "." + decl.Name
+		if b.visiting[key] {
+			return scalarExample("null")
+		}
+
+		if b.depth >= exampleMaxDepth {
+			return &exampleNode{kind: exampleObject}
+		}
+
+		b.visiting[key] = true
+		b.depth++
+		defer func() {
+			delete(b.visiting, key)
+			b.depth--
+		}()
+
+		return b.structExample(fieldsOfGoStruct(decl))
+	}
+
+	if value, ok := b.registry.lookupSource(typeStr); ok {
+		return scalarExample(value)
+	}
+
+	value := generateExampleValueForType(typeStr)
+	if value == "{}" {
+		return &exampleNode{kind: exampleObject}
+	}
+
+	return scalarExample(value)
+}
+
+func basicExample(t *types.Basic) string {
+	info := t.Info()
+
+	switch {
+	case info&types.IsBoolean != 0:
+		return "false"
+	case info&types.IsString != 0:
+		return `"example"`
+	case info&types.IsFloat != 0:
+		return "0.0"
+	case info&types.IsInteger != 0:
+		return "0"
+	}
+
+	return "null"
+}
+
+// mapKeyExample returns an example key for a map; integer keys are encoded as
+// strings by encoding/json.
+func mapKeyExample(key types.Type) string {
+	if basic, ok := types.Unalias(key).Underlying().(*types.Basic); ok && basic.Info()&types.IsInteger != 0 {
+		return "0"
+	}
+
+	return "key"
+}
+
+// json serializes the node as JSON where nested values are indented relative
+// to indent.
+func (n *exampleNode) json(indent int) string {
+	switch n.kind {
+	case exampleObject:
+		if len(n.keys) == 0 {
+			return "{}"
+		}
+
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for i, key := range n.keys {
+			if i > 0 {
+				sb.WriteString(",\n")
+			}
+			sb.WriteString(makeIndent(indent+1) + `"` + key + `": `)
+			sb.WriteString(n.values[i].json(indent + 1))
+		}
+		sb.WriteString("\n" + makeIndent(indent) + "}")
+		return sb.String()
+	case exampleArray:
+		if len(n.items) == 0 {
+			return "[]"
+		}
+
+		if !n.hasComposite() {
+			values := make([]string, 0, len(n.items))
+			for _, item := range n.items {
+				values = append(values, item.scalar)
+			}
+			return "[" + strings.Join(values, ", ") + "]"
+		}
+
+		var sb strings.Builder
+		sb.WriteString("[\n")
+		for i, item := range n.items {
+			if i > 0 {
+				sb.WriteString(",\n")
+			}
+			sb.WriteString(makeIndent(indent+1) + item.json(indent+1))
+		}
+		sb.WriteString("\n" + makeIndent(indent) + "]")
+		return sb.String()
+	}
+
+	return n.scalar
+}
+
+// yamlFields serializes the members of an object node as YAML block mappings
+// at indent.
+func (n *exampleNode) yamlFields(indent int) string {
+	lines := make([]string, 0, len(n.keys))
+	for i, key := range n.keys {
+		value := n.values[i].yamlValue(indent)
+		if strings.HasPrefix(value, "\n") {
+			lines = append(lines, makeIndent(indent)+key+":"+value)
+		} else {
+			lines = append(lines, makeIndent(indent)+key+": "+value)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// yamlValue serializes the node as the value of a mapping key at indent.
+// Block values start with a newline.
+func (n *exampleNode) yamlValue(indent int) string {
+	switch n.kind {
+	case exampleObject:
+		if len(n.keys) == 0 {
+			return "{}"
+		}
+		return "\n" + n.yamlFields(indent+1)
+	case exampleArray:
+		if len(n.items) == 0 {
+			return "[]"
+		}
+
+		var sb strings.Builder
+		for _, item := range n.items {
+			sb.WriteString("\n" + makeIndent(indent+1) + "- ")
+			switch {
+			case item.kind == exampleObject && len(item.keys) > 0:
+				// First member shares the line with the sequence indicator
+				sb.WriteString(strings.TrimPrefix(item.yamlFields(indent+2), makeIndent(indent+2)))
+			case item.kind == exampleScalar:
+				sb.WriteString(item.scalar)
+			default:
+				sb.WriteString(item.json(0))
+			}
+		}
+		return sb.String()
+	}
+
+	return n.scalar
+}
+
+// hasComposite reports whether any array item is an object or array.
+func (n *exampleNode) hasComposite() bool {
+	for _, item := range n.items {
+		if item.kind != exampleScalar {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,194 @@
+package goparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+)
+
+// ExampleEncoding determines how an ExampleValue is encoded in a document.
+type ExampleEncoding string
+
+const (
+	// ExampleEncodingString encodes the value as a string (default).
+	ExampleEncodingString ExampleEncoding = "string"
+	// ExampleEncodingNumber encodes the value as a number.
+	ExampleEncodingNumber ExampleEncoding = "number"
+	// ExampleEncodingBool encodes the value as a boolean.
+	ExampleEncodingBool ExampleEncoding = "bool"
+	// ExampleEncodingJSON uses the value verbatim as a JSON literal e.g. an object.
+	ExampleEncodingJSON ExampleEncoding = "json"
+)
+
+// ExampleValue is the example rendered for a type.
+//
+// In JSON it is either an object, {"value": "12.34", "encoding": "number"}, or a
+// plain string that is a short form for a string encoded value.
+type ExampleValue struct {
+	Value    string          `json:"value"`
+	Encoding ExampleEncoding `json:"encoding,omitempty"`
+}
+
+// UnmarshalJSON accepts both the object and the plain string form.
+func (v *ExampleValue) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = ExampleValue{Value: value, Encoding: ExampleEncodingString}
+		return nil
+	}
+
+	type plain ExampleValue
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	*v = ExampleValue(p)
+	return nil
+}
+
+// Encoded returns the value as a JSON literal.
+func (v ExampleValue) Encoded() (string, error) {
+	switch v.Encoding {
+	case "", ExampleEncodingString:
+		return quoteExample(v.Value), nil
+	case ExampleEncodingNumber:
+		var n json.Number
+		if err := json.Unmarshal([]byte(v.Value), &n); err != nil {
+			return "", fmt.Errorf("%q is not a number", v.Value)
+		}
+		return v.Value, nil
+	case ExampleEncodingBool:
+		if v.Value != "true" && v.Value != "false" {
+			return "", fmt.Errorf("%q is not a bool", v.Value)
+		}
+		return v.Value, nil
+	case ExampleEncodingJSON:
+		if !json.Valid([]byte(v.Value)) {
+			return "", fmt.Errorf("%q is not valid JSON", v.Value)
+		}
+		return v.Value, nil
+	}
+
+	return "", fmt.Errorf("unknown example encoding %q", v.Encoding)
+}
+
+// ExampleRegistry maps fully qualified type names, e.g. time.Time or
+// github.com/google/uuid.UUID, onto the example values rendered for them.
+//
+// It is safe for concurrent use.
+type ExampleRegistry struct {
+	mu     sync.RWMutex
+	values map[string]ExampleValue
+}
+
+// NewExampleRegistry creates a registry with the built-in examples for the
+// standard library and a few commonly used types.
+func NewExampleRegistry() *ExampleRegistry {
+	r := &ExampleRegistry{values: map[string]ExampleValue{}}
+
+	r.Register("time.Time", ExampleValue{Value: "2006-01-02T15:04:05Z"})
+	r.Register("time.Duration", ExampleValue{Value: "1000000000", Encoding: ExampleEncodingNumber})
+	r.Register("time.Month", ExampleValue{Value: "1", Encoding: ExampleEncodingNumber})
+	r.Register("time.Weekday", ExampleValue{Value: "1", Encoding: ExampleEncodingNumber})
+	r.Register("encoding/json.RawMessage", ExampleValue{Value: "{}", Encoding: ExampleEncodingJSON})
+	r.Register("encoding/json.Number", ExampleValue{Value: "0", Encoding: ExampleEncodingNumber})
+	r.Register("net.IP", ExampleValue{Value: "192.0.2.1"})
+	r.Register("net.IPNet", ExampleValue{Value: "192.0.2.0/24"})
+	r.Register("net.HardwareAddr", ExampleValue{Value: "00:00:5e:00:53:01"})
+	r.Register("net/netip.Addr", ExampleValue{Value: "192.0.2.1"})
+	r.Register("net/netip.AddrPort", ExampleValue{Value: "192.0.2.1:8080"})
+	r.Register("net/netip.Prefix", ExampleValue{Value: "192.0.2.0/24"})
+	r.Register("net/url.URL", ExampleValue{Value: "https://example.com/path"})
+	r.Register("net/mail.Address", ExampleValue{Value: "jane@example.com"})
+	r.Register("math/big.Int", ExampleValue{Value: "0", Encoding: ExampleEncodingNumber})
+	r.Register("math/big.Float", ExampleValue{Value: "0.0", Encoding: ExampleEncodingNumber})
+	r.Register("github.com/google/uuid.UUID", ExampleValue{Value: "00000000-0000-0000-0000-000000000000"})
+	r.Register("github.com/shopspring/decimal.Decimal", ExampleValue{Value: "12.34"})
+
+	return r
+}
+
+// defaultExampleRegistry is used when a module has no registry of its own.
+var defaultExampleRegistry = NewExampleRegistry()
+
+// Register adds or replaces the example for the fully qualified type name.
+func (r *ExampleRegistry) Register(typeName string, value ExampleValue) *ExampleRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.values == nil {
+		r.values = map[string]ExampleValue{}
+	}
+
+	r.values[typeName] = value
+	return r
+}
+
+// Lookup returns the example for the fully qualified type name.
+func (r *ExampleRegistry) Lookup(typeName string) (ExampleValue, bool) {
+	if r == nil {
+		return ExampleValue{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	value, ok := r.values[typeName]
+	return value, ok
+}
+
+// lookupEncoded returns the JSON literal example for the fully qualified type name.
+func (r *ExampleRegistry) lookupEncoded(typeName string) (string, bool) {
+	value, ok := r.Lookup(typeName)
+	if !ok {
+		return "", false
+	}
+
+	encoded, err := value.Encoded()
+	if err != nil {
+		return "", false
+	}
+
+	return encoded, true
+}
+
+// lookupSource returns the JSON literal example for a package qualified type
+// as written in source, e.g. uuid.UUID, by matching the last element of the
+// registered package paths.
+func (r *ExampleRegistry) lookupSource(typeStr string) (string, bool) {
+	if r == nil || !strings.Contains(typeStr, ".") {
+		return "", false
+	}
+
+	r.mu.RLock()
+	var match string
+	for name := range r.values {
+		dot := strings.LastIndex(name, ".")
+		if dot < 0 || path.Base(name[:dot])+name[dot:] != typeStr {
+			continue
+		}
+		// Prefer a deterministic match when several packages share a name
+		if match == "" || name < match {
+			match = name
+		}
+	}
+	r.mu.RUnlock()
+
+	if match == "" {
+		return "", false
+	}
+
+	return r.lookupEncoded(match)
+}
+
+// exampleRegistry returns the module registry or the built-in one.
+func (gm *GoModule) exampleRegistry() *ExampleRegistry {
+	if gm == nil || gm.Examples == nil {
+		return defaultExampleRegistry
+	}
+
+	return gm.Examples
+}
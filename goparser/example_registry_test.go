@@ -0,0 +1,74 @@
+package goparser
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleRegistryBuiltinsAndMarshalers(t *testing.T) {
+	s := parseExampleStruct(t, map[string]string{
+		"cfg/cfg.go": `package cfg
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+type Level int
+
+func (l Level) MarshalText() ([]byte, error) { return []byte("info"), nil }
+
+type Config struct {
+	Timeout  time.Duration   ` + "`json:\"timeout\"`" + `
+	Endpoint url.URL         ` + "`json:\"endpoint\"`" + `
+	Raw      json.RawMessage ` + "`json:\"raw\"`" + `
+	Level    Level           ` + "`json:\"level\"`" + `
+}
+`,
+	}, "Config")
+
+	assert.Equal(t, `{
+  "timeout": 1000000000,
+  "endpoint": "https://example.com/path",
+  "raw": {},
+  "level": "value"
+}`, s.ToJSON())
+
+	assert.Equal(t, "string", s.JSONSchema().Properties.Get("level").Type)
+
+	s.File.Module.Examples = NewExampleRegistry().
+		Register("time.Duration", ExampleValue{Value: "5s"}).
+		Register("example.com/app/cfg.Level", ExampleValue{Value: "debug"})
+
+	example := s.ToJSON()
+	assert.Contains(t, example, `"timeout": "5s"`)
+	assert.Contains(t, example, `"level": "debug"`)
+}
+
+func TestExampleValueEncodingAndUnmarshal(t *testing.T) {
+	var values map[string]ExampleValue
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"a": "12.34",
+		"b": {"value": "42", "encoding": "number"},
+		"c": {"value": "{\"k\":1}", "encoding": "json"}
+	}`), &values))
+
+	encoded, err := values["a"].Encoded()
+	require.NoError(t, err)
+	assert.Equal(t, `"12.34"`, encoded)
+
+	encoded, err = values["b"].Encoded()
+	require.NoError(t, err)
+	assert.Equal(t, "42", encoded)
+
+	encoded, err = values["c"].Encoded()
+	require.NoError(t, err)
+	assert.Equal(t, `{"k":1}`, encoded)
+
+	_, err = ExampleValue{Value: "abc", Encoding: ExampleEncodingNumber}.Encoded()
+	assert.Error(t, err)
+}
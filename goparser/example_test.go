@@ -0,0 +1,118 @@
+package goparser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseExampleStruct writes files into a temporary module and returns the
+// named struct after parsing all packages.
+func parseExampleStruct(t *testing.T, files map[string]string, name string) *GoStruct {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.24\n"
+
+	for file, content := range files {
+		path := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	mod, err := NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	var found *GoStruct
+	err = ParseSinglePackageWalker(ParseConfig{Module: mod}, func(pkg *GoPackage) error {
+		for _, s := range pkg.Structs {
+			if s.Name == name {
+				found = s
+			}
+		}
+		return nil
+	}, dir)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	return found
+}
+
+func TestExamplesFollowNamedStructs(t *testing.T) {
+	s := parseExampleStruct(t, map[string]string{
+		"customer/customer.go": `package customer
+
+type Customer struct {
+	Name string ` + "`json:\"name\" yaml:\"name\"`" + `
+}
+`,
+		"order/order.go": `package order
+
+import "example.com/app/customer"
+
+type Audit struct {
+	Version int ` + "`json:\"version\" yaml:\"version\"`" + `
+}
+
+type Item struct {
+	SKU string ` + "`json:\"sku\" yaml:\"sku\"`" + `
+}
+
+type Order struct {
+	Audit    ` + "`yaml:\",inline\"`" + `
+	Customer customer.Customer ` + "`json:\"customer\" yaml:\"customer\"`" + `
+	Items    []Item            ` + "`json:\"items\" yaml:\"items\"`" + `
+	Counts   map[int]bool      ` + "`json:\"counts\" yaml:\"counts\"`" + `
+	Next     *Order            ` + "`json:\"next\" yaml:\"next\"`" + `
+}
+`,
+	}, "Order")
+
+	assert.Equal(t, `{
+  "version": 0,
+  "customer": {
+    "name": "example"
+  },
+  "items": [
+    {
+      "sku": "example"
+    }
+  ],
+  "counts": {
+    "0": false
+  },
+  "next": null
+}`, s.ToJSON())
+
+	assert.Equal(t, `version: 0
+customer:
+  name: "example"
+items:
+  - sku: "example"
+counts:
+  0: false
+next: null`, s.ToYAML())
+}
+
+func TestExamplesDepthLimit(t *testing.T) {
+	s := parseExampleStruct(t, map[string]string{
+		"deep/deep.go": `package deep
+
+type L7 struct{ V int ` + "`json:\"v\"`" + ` }
+type L6 struct{ N L7 ` + "`json:\"n\"`" + ` }
+type L5 struct{ N L6 ` + "`json:\"n\"`" + ` }
+type L4 struct{ N L5 ` + "`json:\"n\"`" + ` }
+type L3 struct{ N L4 ` + "`json:\"n\"`" + ` }
+type L2 struct{ N L3 ` + "`json:\"n\"`" + ` }
+type L1 struct{ N L2 ` + "`json:\"n\"`" + ` }
+type Root struct{ N L1 ` + "`json:\"n\"`" + ` }
+`,
+	}, "Root")
+
+	json := s.ToJSON()
+	assert.Contains(t, json, `"n": {}`)
+	assert.NotContains(t, json, `"v"`)
+}
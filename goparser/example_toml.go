@@ -0,0 +1,151 @@
+package goparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var tomlRules = encoderRules{tagKey: "toml", flattenEmbedded: true}
+
+var tomlBareKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ToTOML generates an example TOML representation of the struct. Nested structs
+// and maps become tables, slices of structs arrays of tables and fields tagged
+// with the inline option are rendered as inline tables.
+func (s *GoStruct) ToTOML() string {
+	if s == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	s.example(tomlRules).writeTOML(&sb, nil)
+	return strings.TrimSpace(sb.String())
+}
+
+// writeTOML writes the key/value pairs of an object node followed by its
+// tables, as TOML requires, where path is the key path of the table.
+func (n *exampleNode) writeTOML(sb *strings.Builder, path []string) {
+	for i, key := range n.keys {
+		value := n.values[i]
+		if !value.isTOMLTable(n.options[i]) && !value.isTOMLArrayOfTables(n.options[i]) && !value.isNull() {
+			sb.WriteString(tomlKey(key) + " = " + value.tomlInline() + "\n")
+		}
+	}
+
+	for i, key := range n.keys {
+		value := n.values[i]
+		tablePath := append(append([]string{}, path...), tomlKey(key))
+
+		switch {
+		case value.isTOMLTable(n.options[i]):
+			sb.WriteString("\n[" + strings.Join(tablePath, ".") + "]\n")
+			value.writeTOML(sb, tablePath)
+		case value.isTOMLArrayOfTables(n.options[i]):
+			for _, item := range value.items {
+				sb.WriteString("\n[[" + strings.Join(tablePath, ".") + "]]\n")
+				item.writeTOML(sb, tablePath)
+			}
+		}
+	}
+}
+
+func (n *exampleNode) isNull() bool {
+	return n.kind == exampleScalar && n.scalar == "null"
+}
+
+func (n *exampleNode) isTOMLTable(opts map[string]bool) bool {
+	return n.kind == exampleObject && !opts["inline"]
+}
+
+func (n *exampleNode) isTOMLArrayOfTables(opts map[string]bool) bool {
+	if n.kind != exampleArray || len(n.items) == 0 || opts["inline"] {
+		return false
+	}
+
+	for _, item := range n.items {
+		if item.kind != exampleObject {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tomlInline renders the node as an inline TOML value.
+func (n *exampleNode) tomlInline() string {
+	switch n.kind {
+	case exampleObject:
+		members := make([]string, 0, len(n.keys))
+		for i, key := range n.keys {
+			if !n.values[i].isNull() {
+				members = append(members, tomlKey(key)+" = "+n.values[i].tomlInline())
+			}
+		}
+		if len(members) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(members, ", ") + " }"
+	case exampleArray:
+		items := make([]string, 0, len(n.items))
+		for _, item := range n.items {
+			items = append(items, item.tomlInline())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+
+	if strings.HasPrefix(n.scalar, "{") || strings.HasPrefix(n.scalar, "[") {
+		var value interface{}
+		if err := json.Unmarshal([]byte(n.scalar), &value); err == nil {
+			return tomlValue(value)
+		}
+	}
+
+	return n.scalar
+}
+
+// tomlValue renders a decoded JSON value as an inline TOML value.
+func tomlValue(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		members := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if v[key] != nil {
+				members = append(members, tomlKey(key)+" = "+tomlValue(v[key]))
+			}
+		}
+		if len(members) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(members, ", ") + " }"
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, tomlValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case string:
+		return quoteExample(v)
+	case nil:
+		return `""`
+	}
+
+	return fmt.Sprint(value)
+}
+
+// tomlKey quotes keys that are not valid bare keys.
+func tomlKey(key string) string {
+	if tomlBareKeyRegex.MatchString(key) {
+		return key
+	}
+
+	return quoteExample(key)
+}
@@ -0,0 +1,170 @@
+package goparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+var xmlRules = encoderRules{tagKey: "xml", flattenEmbedded: true, skipName: "XMLName"}
+
+// xmlElement is an element of an example XML document.
+type xmlElement struct {
+	name    string
+	attrs   [][2]string
+	text    string
+	inner   string
+	comment string
+	// wrapper is set for the parent elements created by a>b paths.
+	wrapper  bool
+	children []*xmlElement
+}
+
+// ToXML generates an example XML representation of the struct as encoded by
+// encoding/xml. The root element is named by the XMLName field, if any.
+func (s *GoStruct) ToXML() string {
+	if s == nil {
+		return ""
+	}
+
+	root := xmlElementsFor(xmlRootName(s), s.example(xmlRules))
+	if len(root) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	root[0].write(&sb, 0)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// xmlRootName returns the element name from the XMLName field or the struct name.
+func xmlRootName(s *GoStruct) string {
+	for _, f := range s.Fields {
+		if f.Name != "XMLName" || f.Tag == nil {
+			continue
+		}
+
+		if name, _ := parseTagValue(f.Tag.Get("xml")); name != "" {
+			return xmlLocalName(name)
+		}
+	}
+
+	return s.Name
+}
+
+// xmlLocalName strips a namespace from a "namespace-URL name" tag name.
+func xmlLocalName(name string) string {
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+// xmlElementsFor creates the elements for a node; arrays produce one element
+// per item.
+func xmlElementsFor(name string, n *exampleNode) []*xmlElement {
+	switch n.kind {
+	case exampleArray:
+		var elements []*xmlElement
+		for _, item := range n.items {
+			elements = append(elements, xmlElementsFor(name, item)...)
+		}
+		return elements
+	case exampleScalar:
+		return []*xmlElement{{name: name, text: n.text()}}
+	}
+
+	el := &xmlElement{name: name}
+
+	for i, key := range n.keys {
+		value, opts := n.values[i], n.options[i]
+
+		switch {
+		case opts["omitempty"] && value.isNull():
+			continue
+		case opts["attr"]:
+			el.attrs = append(el.attrs, [2]string{xmlLocalName(key), value.text()})
+		case opts["chardata"]:
+			el.text += value.text()
+		case opts["innerxml"]:
+			el.inner += value.text()
+		case opts["comment"]:
+			el.comment += value.text()
+		default:
+			path := strings.Split(key, ">")
+			parent := el
+			for _, segment := range path[:len(path)-1] {
+				parent = parent.wrapperFor(segment)
+			}
+			parent.children = append(parent.children, xmlElementsFor(xmlLocalName(path[len(path)-1]), value)...)
+		}
+	}
+
+	return []*xmlElement{el}
+}
+
+// wrapperFor returns the wrapper element named name, reusing the last child
+// when it is the same wrapper as encoding/xml does for consecutive a>b paths.
+func (el *xmlElement) wrapperFor(name string) *xmlElement {
+	if n := len(el.children); n > 0 && el.children[n-1].wrapper && el.children[n-1].name == name {
+		return el.children[n-1]
+	}
+
+	wrapper := &xmlElement{name: name, wrapper: true}
+	el.children = append(el.children, wrapper)
+	return wrapper
+}
+
+func (el *xmlElement) write(sb *strings.Builder, indent int) {
+	sb.WriteString(makeIndent(indent) + "<" + el.name)
+	for _, attr := range el.attrs {
+		sb.WriteString(" " + attr[0] + `="` + escapeXML(attr[1]) + `"`)
+	}
+	sb.WriteString(">")
+
+	if len(el.children) == 0 && el.comment == "" {
+		sb.WriteString(escapeXML(el.text) + el.inner + "</" + el.name + ">\n")
+		return
+	}
+
+	sb.WriteString("\n")
+	if el.comment != "" {
+		sb.WriteString(makeIndent(indent+1) + "<!--" + el.comment + "-->\n")
+	}
+	if el.text != "" || el.inner != "" {
+		sb.WriteString(makeIndent(indent+1) + escapeXML(el.text) + el.inner + "\n")
+	}
+	for _, child := range el.children {
+		child.write(sb, indent+1)
+	}
+	sb.WriteString(makeIndent(indent) + "</" + el.name + ">\n")
+}
+
+// text returns the node as plain text, strings are unquoted and composite
+// values are rendered as JSON.
+func (n *exampleNode) text() string {
+	if n.kind != exampleScalar {
+		return n.json(0)
+	}
+
+	if strings.HasPrefix(n.scalar, `"`) {
+		var value string
+		if err := json.Unmarshal([]byte(n.scalar), &value); err == nil {
+			return value
+		}
+	}
+
+	if n.scalar == "null" {
+		return ""
+	}
+
+	return n.scalar
+}
+
+func escapeXML(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,91 @@
+package goparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToXML(t *testing.T) {
+	s := parseExampleStruct(t, map[string]string{
+		"app.go": `package app
+
+import "encoding/xml"
+
+type Item struct {
+	ID   int    ` + "`xml:\"id,attr\"`" + `
+	Text string ` + "`xml:\",chardata\"`" + `
+}
+
+type Order struct {
+	XMLName xml.Name ` + "`xml:\"urn:orders order\"`" + `
+	ID      string   ` + "`xml:\"id,attr\"`" + `
+	Note    string   ` + "`xml:\",comment\"`" + `
+	Items   []Item   ` + "`xml:\"items>item\"`" + `
+	Owner   string   ` + "`xml:\"meta>owner\"`" + `
+	Tags    []string ` + "`xml:\"meta>tag\"`" + `
+	Raw     string   ` + "`xml:\",innerxml\"`" + `
+	Ref     *Item    ` + "`xml:\"ref,omitempty\"`" + `
+	Skip    string   ` + "`xml:\"-\"`" + `
+}
+`,
+	}, "Order")
+
+	assert.True(t, s.HasXMLTag())
+	assert.False(t, s.HasTOMLTag())
+	assert.Equal(t, `<order id="example">
+  <!--example-->
+  example
+  <items>
+    <item id="0">example</item>
+  </items>
+  <meta>
+    <owner>example</owner>
+    <tag>example</tag>
+  </meta>
+  <ref id="0">example</ref>
+</order>`, s.ToXML())
+}
+
+func TestToTOML(t *testing.T) {
+	s := parseExampleStruct(t, map[string]string{
+		"app.go": `package app
+
+type Endpoint struct {
+	URL  string ` + "`toml:\"url\"`" + `
+	Port int    ` + "`toml:\"port\"`" + `
+}
+
+type Config struct {
+	Name      string            ` + "`toml:\"name\"`" + `
+	Weights   []float64         ` + "`toml:\"weights\"`" + `
+	Primary   Endpoint          ` + "`toml:\"primary\"`" + `
+	Fallback  Endpoint          ` + "`toml:\"fallback,inline\"`" + `
+	Endpoints []Endpoint        ` + "`toml:\"endpoints\"`" + `
+	Labels    map[string]string ` + "`toml:\"labels\"`" + `
+	Optional  *string           ` + "`toml:\"optional,omitempty\"`" + `
+	Ignored   string            ` + "`toml:\"-\"`" + `
+	Quoted    string            ` + "`toml:\"a.key\"`" + `
+}
+`,
+	}, "Config")
+
+	assert.True(t, s.HasTOMLTag())
+	assert.False(t, s.HasXMLTag())
+	assert.Equal(t, `name = "example"
+weights = [0.0]
+fallback = { url = "example", port = 0 }
+optional = "example"
+"a.key" = "example"
+
+[primary]
+url = "example"
+port = 0
+
+[[endpoints]]
+url = "example"
+port = 0
+
+[labels]
+key = "example"`, s.ToTOML())
+}
@@ -0,0 +1,158 @@
+package goparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// exampleDirectiveRegex matches an "Example: <value>" line in a field doc.
+var exampleDirectiveRegex = regexp.MustCompile(`(?m)^[ \t]*Example:[ \t]*(\S.*)$`)
+
+// buildFieldExamples sets the GoField.Example and GoField.Default values from
+// the example and default tags and an Example: directive in the field doc. The
+// example tag takes precedence over the doc directive. Invalid values are
+// reported as unresolved declarations with the position of the field.
+func buildFieldExamples(file *GoFile, field *ast.Field, goField *GoField, src fileSource) {
+	var typ types.Type
+	if goField.TypeInfo != nil {
+		typ = goField.TypeInfo.typ
+	}
+
+	report := func(source, value string, err error) {
+		if file == nil || file.Module == nil {
+			return
+		}
+
+		u := UnresolvedDecl{
+			Expr: field.Type,
+			Message: fmt.Sprintf(
+				"field %s: invalid %s value %q: %v", goField.Name, source, value, err,
+			),
+			Kind: UnresolvedExample,
+			File: file.FilePath,
+		}
+		if src.fset != nil {
+			pos := src.fset.Position(field.Pos())
+			u.Line, u.Column = pos.Line, pos.Column
+		}
+
+		file.Module.AddUnresolvedDeclaration(u)
+	}
+
+	parse := func(source, value string) string {
+		example, err := parseExampleValue(value, typ)
+		if err != nil {
+			report(source, value, err)
+			return ""
+		}
+		return example
+	}
+
+	if goField.Tag != nil {
+		if value, ok := goField.Tag.Lookup("example"); ok {
+			goField.Example = parse("example tag", value)
+		}
+
+		if value, ok := goField.Tag.Lookup("default"); ok {
+			goField.Default = parse("default tag", value)
+		}
+	}
+
+	if goField.Example == "" {
+		if m := exampleDirectiveRegex.FindStringSubmatch(goField.Doc); m != nil {
+			goField.Example = parse("Example directive", m[1])
+		}
+	}
+}
+
+// tagValue returns the JSON encoded value of the key tag, e.g. example or default, ignoring
+// invalid values.
+func tagValue(tag reflect.StructTag, key string, typ types.Type) string {
+	if value, ok := tag.Lookup(key); ok {
+		if parsed, err := parseExampleValue(value, typ); err == nil {
+			return parsed
+		}
+	}
+
+	return ""
+}
+
+// parseExampleValue converts a user provided example into a JSON encoded value
+// that is valid for typ. Values starting with '"', '{' or '[' must be valid JSON,
+// other values are quoted when typ is a string or not a JSON literal.
+func parseExampleValue(raw string, typ types.Type) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("empty value")
+	}
+
+	quoted := strings.HasPrefix(raw, `"`)
+	composite := strings.HasPrefix(raw, "{") || strings.HasPrefix(raw, "[")
+
+	if quoted || composite {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(raw)); err != nil {
+			return "", fmt.Errorf("invalid JSON: %w", err)
+		}
+		raw = buf.String()
+	}
+
+	var basic *types.Basic
+	if typ != nil {
+		basic, _ = derefType(typ).Underlying().(*types.Basic)
+	}
+
+	if basic == nil {
+		if quoted || composite || json.Valid([]byte(raw)) {
+			return raw, nil
+		}
+		return quoteExample(raw), nil
+	}
+
+	info := basic.Info()
+	switch {
+	case info&types.IsString != 0:
+		if composite {
+			return "", fmt.Errorf("expected a string for %s", basic.Name())
+		}
+		if quoted {
+			return raw, nil
+		}
+		return quoteExample(raw), nil
+	case quoted || composite:
+		return "", fmt.Errorf("expected a %s literal", basic.Name())
+	case info&types.IsBoolean != 0:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "", fmt.Errorf("expected a bool")
+		}
+		return strconv.FormatBool(b), nil
+	case info&types.IsUnsigned != 0:
+		if _, err := strconv.ParseUint(raw, 10, 64); err != nil {
+			return "", fmt.Errorf("expected an unsigned integer")
+		}
+	case info&types.IsInteger != 0:
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return "", fmt.Errorf("expected an integer")
+		}
+	case info&types.IsFloat != 0:
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return "", fmt.Errorf("expected a number")
+		}
+	}
+
+	return raw, nil
+}
+
+func quoteExample(value string) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
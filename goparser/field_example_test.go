@@ -0,0 +1,154 @@
+package goparser
+
+import (
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldExamplesFromDocAndTags(t *testing.T) {
+	dir := t.TempDir()
+	mod := &GoModule{Name: "example.com/app", Base: dir}
+
+	code := `package app
+
+type Server struct {
+	// Email of the administrator.
+	//
+	// Example: "jane@example.com"
+	Email string ` + "`json:\"email\" yaml:\"email\"`" + `
+	Port int ` + "`json:\"port\" yaml:\"port\" example:\"8080\"`" + `
+	Host string ` + "`json:\"host\" yaml:\"host\" default:\"localhost\"`" + `
+	// Example: {"env": "prod"}
+	Labels map[string]string ` + "`json:\"labels\" yaml:\"labels\"`" + `
+	Debug bool ` + "`json:\"debug\" yaml:\"debug\" example:\"maybe\"`" + `
+}
+`
+
+	file, err := ParseInlineFile(mod, filepath.Join(dir, "server.go"), code)
+	require.NoError(t, err)
+	require.Len(t, file.Structs, 1)
+
+	s := file.Structs[0]
+	assert.Equal(t, `"jane@example.com"`, s.Fields[0].Example)
+	assert.Equal(t, "8080", s.Fields[1].Example)
+	assert.Equal(t, `"localhost"`, s.Fields[2].Default)
+	assert.Equal(t, `{"env":"prod"}`, s.Fields[3].Example)
+	assert.Empty(t, s.Fields[4].Example)
+
+	assert.Equal(t, `{
+  "email": "jane@example.com",
+  "port": 8080,
+  "host": "localhost",
+  "labels": {"env":"prod"},
+  "debug": false
+}`, s.ToJSON())
+
+	assert.Equal(t, `email: "jane@example.com"
+port: 8080
+host: "localhost"
+labels: {"env":"prod"}
+debug: false`, s.ToYAML())
+
+	schema := s.JSONSchema()
+	assert.Equal(t, `"localhost"`, string(schema.Properties.Get("host").Default))
+	require.Len(t, schema.Properties.Get("port").Examples, 1)
+	assert.Equal(t, "8080", string(schema.Properties.Get("port").Examples[0]))
+
+	var unresolved []UnresolvedDecl
+	for _, u := range mod.Unresolved {
+		if strings.Contains(u.Message, "invalid example tag") {
+			unresolved = append(unresolved, u)
+		}
+	}
+	require.Len(t, unresolved, 1)
+	assert.Equal(t, UnresolvedExample, unresolved[0].Kind)
+	assert.Equal(t, "server.go", filepath.Base(unresolved[0].File))
+	assert.Equal(t, 12, unresolved[0].Line)
+	assert.Equal(t, 2, unresolved[0].Column)
+	assert.Contains(t, unresolved[0].Message, "field Debug")
+	assert.Contains(t, unresolved[0].Message, "expected a bool")
+}
+
+func TestFieldDefaultsOfInstantiatedStructs(t *testing.T) {
+	s := parseExampleStruct(t, map[string]string{
+		"list/list.go": `package list
+
+type Page[T any] struct {
+	Size  int    ` + "`json:\"size\" yaml:\"size\" default:\"10\"`" + `
+	Sort  string ` + "`json:\"sort\" yaml:\"sort\" example:\"name\" default:\"id\"`" + `
+	Items []T    ` + "`json:\"items\" yaml:\"items\"`" + `
+}
+
+type Names struct {
+	Page Page[string] ` + "`json:\"page\" yaml:\"page\"`" + `
+}
+`,
+	}, "Names")
+
+	assert.Equal(t, `{
+  "page": {
+    "size": 10,
+    "sort": "name",
+    "items": ["example"]
+  }
+}`, s.ToJSON())
+
+	schema := s.JSONSchema()
+	ref := schema.Properties.Get("page").Ref
+	require.True(t, strings.HasPrefix(ref, "#/$defs/"), ref)
+
+	page := schema.Defs.Get(strings.TrimPrefix(ref, "#/$defs/"))
+	require.NotNil(t, page)
+	assert.Equal(t, "10", string(page.Properties.Get("size").Default))
+	assert.Equal(t, `"id"`, string(page.Properties.Get("sort").Default))
+	require.Len(t, page.Properties.Get("sort").Examples, 1)
+	assert.Equal(t, `"name"`, string(page.Properties.Get("sort").Examples[0]))
+}
+
+func TestParseExampleValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		code     string
+		expected string
+		err      bool
+	}{
+		{name: "bare string is quoted", raw: "jane", code: "string", expected: `"jane"`},
+		{name: "number for string is quoted", raw: "8080", code: "string", expected: `"8080"`},
+		{name: "quoted string kept", raw: `"a b"`, code: "string", expected: `"a b"`},
+		{name: "integer", raw: "42", code: "int", expected: "42"},
+		{name: "integer rejects text", raw: "abc", code: "int", err: true},
+		{name: "unsigned rejects negative", raw: "-1", code: "uint", err: true},
+		{name: "float", raw: "1.5", code: "float64", expected: "1.5"},
+		{name: "bool normalized", raw: "TRUE", code: "bool", expected: "true"},
+		{name: "invalid json", raw: `{"a":`, code: "", err: true},
+		{name: "unknown type keeps json literal", raw: "null", code: "", expected: "null"},
+		{name: "unknown type quotes text", raw: "hello", code: "", expected: `"hello"`},
+		{name: "empty", raw: " ", code: "", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExampleValue(tt.raw, basicTypeByName(tt.code))
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+// basicTypeByName returns the predeclared type with name or nil.
+func basicTypeByName(name string) types.Type {
+	if obj := types.Universe.Lookup(name); obj != nil {
+		return obj.Type()
+	}
+	return nil
+}
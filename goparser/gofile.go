@@ -29,6 +29,17 @@ type GoFile struct {
 	CustomFuncs      []*GoMethod
 	VarAssignments   []*GoAssignment
 	ConstAssignments []*GoAssignment
+	// Generated is set when the file has a "// Code generated ... DO NOT EDIT." header.
+	Generated bool
+	// Generator is the name of the generator, e.g. protoc-gen-go, if stated in the header.
+	Generator string
+	// Directives are the directives that are not part of a declaration doc comment, e.g.
+	// //go:build, and all //go:generate directives in the file.
+	Directives GoDirectives
+	// Constraint is the build constraint of the file, i.e. the //go:build expression
+	// combined with the GOOS and GOARCH of a name_GOOS_GOARCH.go file name, e.g.
+	// linux && amd64. It is empty when the file builds on all platforms.
+	Constraint string
 }
 
 // FindMethodsByReceiver searches the file / package after struct and custom type receiver
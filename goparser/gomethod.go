@@ -1,10 +1,30 @@
 package goparser
 
+import "strings"
+
 // GoStructMethod is a GoMethod but has receivers and is positioned on a struct or custom type.
 type GoStructMethod struct {
 	GoMethod
 	Receivers     []string
 	ReceiverTypes []*GoType
+	// Variants are all definitions of the method, sorted by build constraint, when it
+	// is defined in several files with different build constraints (otherwise nil).
+	Variants []*GoStructMethod
+}
+
+// ReceiverName returns the name of the receiver type, without pointer and type parameters, or an
+// empty string for a function.
+func (m *GoStructMethod) ReceiverName() string {
+	if len(m.ReceiverTypes) == 0 {
+		return ""
+	}
+
+	owner := strings.TrimLeft(m.ReceiverTypes[0].Type, "*")
+	if i := strings.Index(owner, "["); i != -1 {
+		owner = owner[:i]
+	}
+
+	return owner
 }
 
 // GoMethod is a method on a struct, custom type, interface or just plain function
@@ -18,4 +38,11 @@ type GoMethod struct {
 	Params     []*GoType
 	Results    []*GoType
 	TypeParams []*GoType
+	// Line is the line, starting from 1, of the declaration in its file.
+	Line int
+	// Directives are the directives, e.g. //go:noinline, in the doc comment.
+	Directives GoDirectives
+	// Variants are all definitions of the symbol, sorted by build constraint, when it
+	// is defined in several files with different build constraints (otherwise nil).
+	Variants []*GoMethod
 }
@@ -12,11 +12,36 @@ import (
 	"golang.org/x/mod/modfile"
 )
 
+// UnresolvedDecl is a declaration, or package, that could not be fully resolved while parsing
+// e.g. a type-check error. The documentation may be degraded, e.g. have missing links.
 type UnresolvedDecl struct {
 	Expr    ast.Expr
 	Message string
+	// Kind is the kind of problem e.g. UnresolvedTypeCheck.
+	Kind UnresolvedKind
+	// File is the path of the file the problem is in, empty when unknown.
+	File string
+	// Line and Column, starting from 1, of the problem in the file or zero when unknown.
+	Line, Column int
 }
 
+// UnresolvedKind is the kind of an unresolved declaration.
+type UnresolvedKind string
+
+const (
+	// UnresolvedTypeCheck is a type-check error, type information may be missing.
+	UnresolvedTypeCheck UnresolvedKind = "type-check"
+	// UnresolvedLegacyFallback is a fallback to the legacy, per directory, parser since the
+	// packages could not be loaded.
+	UnresolvedLegacyFallback UnresolvedKind = "legacy-fallback"
+	// UnresolvedPackage is a file whose package could not be resolved within the module.
+	UnresolvedPackage UnresolvedKind = "package"
+	// UnresolvedType is a type expression that is not supported.
+	UnresolvedType UnresolvedKind = "unsupported-type"
+	// UnresolvedExample is an invalid example, or default, value of a field.
+	UnresolvedExample UnresolvedKind = "example"
+)
+
 var (
 	ErrModuleNotConfigured  = errors.New("module not configured")
 	ErrPackageOutsideModule = errors.New("path does not belong to module")
@@ -42,14 +67,21 @@ type GoModule struct {
 	GoVersion string
 	// UnresolvedDecl contains all unresolved declarations.
 	Unresolved []UnresolvedDecl
+	// Examples is the registry of example values for well-known types used when
+	// rendering struct examples. When nil, the built-in registry is used.
+	Examples *ExampleRegistry
 
 	importerMu sync.Mutex
 	importer   *moduleImporter
 
 	pkgLoaderMu sync.Mutex
 	pkgLoader   *packageLoader
+
+	structsMu sync.RWMutex
+	structs   map[string]*GoStruct
 }
 
+// AddUnresolvedDeclaration records a declaration that could not be fully resolved.
 func (gm *GoModule) AddUnresolvedDeclaration(u UnresolvedDecl) *GoModule {
 
 	gm.Unresolved = append(gm.Unresolved, u)
@@ -57,6 +89,35 @@ func (gm *GoModule) AddUnresolvedDeclaration(u UnresolvedDecl) *GoModule {
 
 }
 
+// LookupStruct returns a struct that has been parsed within this module by its
+// fully qualified package path and name, or nil if none has been parsed.
+func (gm *GoModule) LookupStruct(pkgPath, name string) *GoStruct {
+	if gm == nil {
+		return nil
+	}
+
+	gm.structsMu.RLock()
+	defer gm.structsMu.RUnlock()
+
+	return gm.structs[pkgPath+"."+name]
+}
+
+// registerStruct records a parsed struct so it may be found using LookupStruct.
+func (gm *GoModule) registerStruct(pkgPath string, s *GoStruct) {
+	if gm == nil || pkgPath == "" || s == nil {
+		return
+	}
+
+	gm.structsMu.Lock()
+	defer gm.structsMu.Unlock()
+
+	if gm.structs == nil {
+		gm.structs = map[string]*GoStruct{}
+	}
+
+	gm.structs[pkgPath+"."+s.Name] = s
+}
+
 func (gm *GoModule) getModuleImporter(debug DebugFunc) *moduleImporter {
 	gm.importerMu.Lock()
 	defer gm.importerMu.Unlock()
@@ -88,8 +149,14 @@ func (gm *GoModule) ResolvePackage(path string) (string, error) {
 		return "", fmt.Errorf("resolve package for %q: %w", path, err)
 	}
 
+	// The base is relative the working directory when the module was loaded from a relative path.
+	base, err := filepath.Abs(gm.Base)
+	if err != nil {
+		return "", fmt.Errorf("resolve package for %q: %w", path, err)
+	}
+
 	dir := filepath.Dir(absPath)
-	rel, err := filepath.Rel(gm.Base, dir)
+	rel, err := filepath.Rel(base, dir)
 	if err != nil {
 		return "", fmt.Errorf("resolve package for %q: %w", path, err)
 	}
@@ -107,6 +174,69 @@ func (gm *GoModule) ResolvePackage(path string) (string, error) {
 
 }
 
+// ModuleVersion is the module, and its version, that provides an import path.
+type ModuleVersion struct {
+	// Module is the module path e.g. github.com/stretchr/testify or std for the standard library.
+	Module string
+	// Version is the module version e.g. v1.8.4 or go1.22 for the standard library.
+	Version string
+	// Package is the import path, within Module, of the package. It differs from the resolved
+	// import path when the module is replaced by another module.
+	Package string
+}
+
+// ResolveVersion resolves the module version that provides the import path using the require
+// and replace directives of go.mod. Standard library packages resolve to the version of the go
+// directive. It returns false when the version is unknown, e.g. not required or replaced by a
+// local directory.
+func (gm *GoModule) ResolveVersion(importPath string) (ModuleVersion, bool) {
+
+	if gm == nil || gm.File == nil || importPath == "" {
+		return ModuleVersion{}, false
+	}
+
+	if first, _, _ := strings.Cut(importPath, "/"); !strings.Contains(first, ".") {
+		if gm.GoVersion == "" {
+			return ModuleVersion{}, false
+		}
+
+		return ModuleVersion{Module: "std", Version: "go" + gm.GoVersion, Package: importPath}, true
+	}
+
+	var req *modfile.Require
+	for _, r := range gm.File.Require {
+		path := r.Mod.Path
+		if importPath != path && !strings.HasPrefix(importPath, path+"/") {
+			continue
+		}
+		if req == nil || len(path) > len(req.Mod.Path) {
+			req = r
+		}
+	}
+
+	if req == nil {
+		return ModuleVersion{}, false
+	}
+
+	mv := ModuleVersion{Module: req.Mod.Path, Version: req.Mod.Version, Package: importPath}
+	for _, r := range gm.File.Replace {
+		if r.Old.Path != req.Mod.Path || (r.Old.Version != "" && r.Old.Version != req.Mod.Version) {
+			continue
+		}
+
+		if r.New.Version == "" {
+			// Replaced by a local directory
+			return ModuleVersion{}, false
+		}
+
+		mv.Module = r.New.Path
+		mv.Version = r.New.Version
+		mv.Package = r.New.Path + strings.TrimPrefix(importPath, req.Mod.Path)
+	}
+
+	return mv, true
+}
+
 // NewModule creates a new module from go.mod pointed out in the
 // in param path parameter.
 func NewModule(path string) (*GoModule, error) {
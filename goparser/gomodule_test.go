@@ -23,6 +23,20 @@ func TestModuleBasePathIsTakenFromPathParam(t *testing.T) {
 	assert.Equal(t, filepath.Dir(path), m.Base)
 }
 
+func TestResolvePackageWithRelativeBase(t *testing.T) {
+	m, err := NewModuleFromBuff("go.mod", []byte(`module github.com/mariotoffia/goasciidoc`))
+	assert.NoError(t, err)
+	assert.Equal(t, ".", m.Base)
+
+	fq, err := m.ResolvePackage(filepath.Join("goparser", "parser.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/mariotoffia/goasciidoc/goparser", fq)
+
+	fq, err = m.ResolvePackage(filepath.Join(getPwd(), "goparser", "parser.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/mariotoffia/goasciidoc/goparser", fq)
+}
+
 func TestParseWithOnlyModuleLine(t *testing.T) {
 	data := `module github.com/mariotoffia/goasciidoc`
 	path := getPwd() + "go.mod"
@@ -143,3 +157,49 @@ func TestLoadModule(t *testing.T) {
 	assert.Equal(t, modPath, module.FilePath)
 	assert.Equal(t, singleModRoot, module.Base)
 }
+
+func TestResolveVersion(t *testing.T) {
+	data := `module github.com/acme/app
+
+go 1.22
+
+require (
+	github.com/acme/lib v1.2.0
+	github.com/acme/lib/v2 v2.0.1
+	github.com/old/dep v0.3.0
+	github.com/local/dep v0.1.0
+)
+
+replace github.com/old/dep => github.com/new/dep v0.4.0
+
+replace github.com/local/dep => ../dep
+`
+	m, err := NewModuleFromBuff(getPwd()+"go.mod", []byte(data))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		importPath string
+		expect     ModuleVersion
+		ok         bool
+	}{
+		{"github.com/acme/lib", ModuleVersion{"github.com/acme/lib", "v1.2.0", "github.com/acme/lib"}, true},
+		{"github.com/acme/lib/sub", ModuleVersion{"github.com/acme/lib", "v1.2.0", "github.com/acme/lib/sub"}, true},
+		{"github.com/acme/lib/v2/x", ModuleVersion{"github.com/acme/lib/v2", "v2.0.1", "github.com/acme/lib/v2/x"}, true},
+		{"github.com/acme/library", ModuleVersion{}, false},
+		{"github.com/old/dep/pkg", ModuleVersion{"github.com/new/dep", "v0.4.0", "github.com/new/dep/pkg"}, true},
+		{"github.com/local/dep", ModuleVersion{}, false},
+		{"net/http", ModuleVersion{"std", "go1.22", "net/http"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.importPath, func(t *testing.T) {
+			got, ok := m.ResolveVersion(tc.importPath)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+
+	var none *GoModule
+	_, ok := none.ResolveVersion("net/http")
+	assert.False(t, ok)
+}
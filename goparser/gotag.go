@@ -17,3 +17,9 @@ func (g *GoTag) Get(key string) string {
 	tag := strings.Replace(g.Value, "`", "", -1)
 	return reflect.StructTag(tag).Get(key)
 }
+
+// Lookup returns the struct tag value with the specified name and if it was present.
+func (g *GoTag) Lookup(key string) (string, bool) {
+	tag := strings.Replace(g.Value, "`", "", -1)
+	return reflect.StructTag(tag).Lookup(key)
+}
@@ -0,0 +1,364 @@
+package goparser
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// SymbolHistory is the release history of an exported symbol.
+type SymbolHistory struct {
+	// Since is the first tag where the symbol is present e.g. v1.2.0.
+	Since string
+	// Changed is the last tag where the signature of the symbol changed, empty when the
+	// signature is unchanged since it was introduced.
+	Changed string
+}
+
+// APIHistory maps exported symbols, keyed by HistoryKey, onto their release history.
+type APIHistory map[string]SymbolHistory
+
+// HistoryKey is the key of a symbol in an APIHistory. The dir is the package directory,
+// slash separated and relative the module root ("." for the root package), and name is
+// the symbol name where methods are prefixed with the receiver type e.g. Token.Verify.
+func HistoryKey(dir, name string) string {
+	if dir == "" {
+		dir = "."
+	}
+
+	return dir + ":" + name
+}
+
+// Lookup returns the history of the symbol declared in file, where base is the module root.
+func (h APIHistory) Lookup(base string, file *GoFile, name string) (SymbolHistory, bool) {
+	if len(h) == 0 || file == nil || file.FilePath == "" {
+		return SymbolHistory{}, false
+	}
+
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return SymbolHistory{}, false
+	}
+
+	path, err := filepath.Abs(file.FilePath)
+	if err != nil {
+		return SymbolHistory{}, false
+	}
+
+	dir, err := filepath.Rel(base, filepath.Dir(path))
+	if err != nil {
+		return SymbolHistory{}, false
+	}
+
+	history, ok := h[HistoryKey(filepath.ToSlash(dir), name)]
+	return history, ok
+}
+
+// BuildAPIHistory walks the local semver git tags of the module, oldest first, and parses the
+// exported API at each tag to find the tag each symbol first appeared in and the tag where its
+// signature last changed. It shells out to git and never accesses the network. Tags of a module
+// in a sub directory of the repository are prefixed with the directory e.g. sub/v1.0.0.
+func BuildAPIHistory(mod *GoModule, debug DebugFunc) (APIHistory, error) {
+	if mod == nil || mod.Base == "" {
+		return nil, ErrModuleNotConfigured
+	}
+
+	root, err := git(mod.Base, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	root = strings.TrimSpace(root)
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	base := mod.Base
+	if resolved, err := filepath.EvalSymlinks(base); err == nil {
+		base = resolved
+	}
+
+	subdir, err := filepath.Rel(root, base)
+	if err != nil {
+		return nil, err
+	}
+	subdir = filepath.ToSlash(subdir)
+
+	out, err := git(mod.Base, "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+
+	tags := semverTags(strings.Fields(out), subdir)
+	debugf(debug, "history: found %d semver tag(s) for module %s", len(tags), mod.Name)
+
+	history := APIHistory{}
+	signatures := map[string]string{}
+	for _, tag := range tags {
+		api, err := apiAtTag(mod, tag, subdir, debug)
+		if err != nil {
+			debugf(debug, "history: skipping tag %s: %v", tag.name, err)
+			continue
+		}
+
+		for key, signature := range api {
+			h, ok := history[key]
+			switch {
+			case !ok:
+				h = SymbolHistory{Since: tag.version}
+			case signatures[key] != signature:
+				h.Changed = tag.version
+			}
+
+			history[key] = h
+			signatures[key] = signature
+		}
+	}
+
+	return history, nil
+}
+
+// versionTag is a git tag and its semantic version.
+type versionTag struct {
+	name    string
+	version string
+}
+
+// semverTags returns the valid semver tags of the module in subdir, sorted by version.
+func semverTags(names []string, subdir string) []versionTag {
+	prefix := ""
+	if subdir != "." && subdir != "" {
+		prefix = subdir + "/"
+	}
+
+	var tags []versionTag
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		version := strings.TrimPrefix(name, prefix)
+		if semver.IsValid(version) {
+			tags = append(tags, versionTag{name: name, version: version})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return semver.Compare(tags[i].version, tags[j].version) < 0
+	})
+
+	return tags
+}
+
+// apiAtTag extracts the module at the tag, using git archive, and returns the signatures of
+// its exported symbols keyed by HistoryKey.
+func apiAtTag(mod *GoModule, tag versionTag, subdir string, debug DebugFunc) (map[string]string, error) {
+	tmp, err := os.MkdirTemp("", "goasciidoc-history-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	args := []string{"archive", "--format=tar", tag.name}
+	if subdir != "." {
+		args = append(args, "--", subdir)
+	}
+
+	archive, err := git(mod.Base, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := extractTar(strings.NewReader(archive), tmp); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(tmp, filepath.FromSlash(subdir))
+
+	var tagModule *GoModule
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		tagModule, err = NewModuleFromBuff(filepath.Join(dir, "go.mod"), data)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Tags before the go.mod was added
+		tagModule, err = NewModuleFromBuff(filepath.Join(dir, "go.mod"), []byte("module "+mod.Name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	debugf(debug, "history: parsing tag %s", tag.name)
+
+	// Only the names and signatures are needed, the snapshot is parsed without type-checking so
+	// no dependency of the tag is downloaded.
+	config := ParseConfig{Module: tagModule, Internal: true, Debug: debug, syntaxOnly: true}
+
+	api := map[string]string{}
+	err = ParseSinglePackageWalker(config, func(pkg *GoPackage) error {
+		for _, file := range pkg.Files {
+			rel, err := filepath.Rel(dir, filepath.Dir(file.FilePath))
+			if err != nil {
+				continue
+			}
+
+			addFileAPI(api, filepath.ToSlash(rel), file)
+		}
+
+		return nil
+	}, dir)
+
+	return api, err
+}
+
+// addFileAPI adds the signatures of all exported symbols in the file to api.
+func addFileAPI(api map[string]string, dir string, file *GoFile) {
+	for _, s := range file.Structs {
+		if s.Exported {
+			api[HistoryKey(dir, s.Name)] = structSignature(s)
+		}
+	}
+
+	for _, i := range file.Interfaces {
+		if !i.Exported {
+			continue
+		}
+
+		methods := make([]string, 0, len(i.Methods))
+		for _, m := range i.Methods {
+			methods = append(methods, m.Name+methodSignature(m))
+		}
+		sort.Strings(methods)
+
+		api[HistoryKey(dir, i.Name)] = strings.Join(methods, ";")
+	}
+
+	for _, c := range file.CustomTypes {
+		if c.Exported {
+			api[HistoryKey(dir, c.Name)] = c.Type
+		}
+	}
+
+	for _, f := range file.CustomFuncs {
+		if f.Exported {
+			api[HistoryKey(dir, f.Name)] = methodSignature(f)
+		}
+	}
+
+	for _, fn := range file.StructMethods {
+		if !fn.Exported {
+			continue
+		}
+
+		name := fn.Name
+		if owner := fn.ReceiverName(); owner != "" {
+			if !isExported(owner) {
+				continue
+			}
+			name = owner + "." + fn.Name
+		}
+
+		api[HistoryKey(dir, name)] = methodSignature(&fn.GoMethod)
+	}
+
+	for _, a := range append(append([]*GoAssignment{}, file.VarAssignments...), file.ConstAssignments...) {
+		if a.Exported {
+			api[HistoryKey(dir, a.Name)] = a.Type
+		}
+	}
+}
+
+// structSignature returns the type parameter types and the sorted exported field names and
+// types of the struct. Unexported fields are not part of the signature.
+func structSignature(s *GoStruct) string {
+	fields := make([]string, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		if f.Exported {
+			fields = append(fields, f.Name+" "+f.Type)
+		}
+	}
+	sort.Strings(fields)
+
+	return "[" + typeList(s.TypeParams) + "]{" + strings.Join(fields, ";") + "}"
+}
+
+// methodSignature returns the type parameter, parameter and result types of the method. The
+// parameter names are not part of the signature.
+func methodSignature(m *GoMethod) string {
+	return "[" + typeList(m.TypeParams) + "](" + typeList(m.Params) + ")(" + typeList(m.Results) + ")"
+}
+
+// typeList returns the comma separated types of the list.
+func typeList(list []*GoType) string {
+	names := make([]string, 0, len(list))
+	for _, t := range list {
+		names = append(names, t.Type)
+	}
+
+	return strings.Join(names, ",")
+}
+
+// git runs git in dir and returns its standard output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// extractTar extracts the regular files and directories of the tar archive into dir.
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(target, data, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+}
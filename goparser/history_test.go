@@ -0,0 +1,194 @@
+package goparser
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func commitAndTag(t *testing.T, dir, tag string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", tag},
+		{"tag", tag},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestBuildAPIHistory(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	commitAndTag(t, dir, "v1.0.0", map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.21\n",
+		"auth/auth.go": `package auth
+
+type Token string
+
+func Issue() Token { return "" }
+
+func (t Token) Verify() error { return nil }
+`,
+	})
+	commitAndTag(t, dir, "v1.1.0", map[string]string{
+		"auth/auth.go": `package auth
+
+type Token string
+
+func Issue(subject string) Token { return "" }
+
+func (t Token) Verify() error { return nil }
+
+type Claims struct{}
+`,
+	})
+	commitAndTag(t, dir, "not-a-version", map[string]string{
+		"auth/extra.go": "package auth\n\nconst Extra = 1\n",
+	})
+
+	mod, err := NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	history, err := BuildAPIHistory(mod, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0"}, history[HistoryKey("auth", "Token")])
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0"}, history[HistoryKey("auth", "Token.Verify")])
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0", Changed: "v1.1.0"}, history[HistoryKey("auth", "Issue")])
+	assert.Equal(t, SymbolHistory{Since: "v1.1.0"}, history[HistoryKey("auth", "Claims")])
+	assert.NotContains(t, history, HistoryKey("auth", "Extra"))
+
+	got, ok := history.Lookup(dir, &GoFile{FilePath: filepath.Join(dir, "auth", "auth.go")}, "Issue")
+	assert.True(t, ok)
+	assert.Equal(t, "v1.1.0", got.Changed)
+}
+
+func TestBuildAPIHistoryStructAndVarChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	commitAndTag(t, dir, "v1.0.0", map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.21\n",
+		"auth/auth.go": `package auth
+
+type Claims struct {
+	Subject string
+	issuer  string
+}
+
+type Scope struct {
+	Name string
+}
+
+var Timeout int
+
+const Issuer = "lib"
+`,
+	})
+	commitAndTag(t, dir, "v1.1.0", map[string]string{
+		"auth/auth.go": `package auth
+
+type Claims struct {
+	Subject []byte
+	issuer  string
+}
+
+type Scope struct {
+	Name  string
+	clock int
+}
+
+var Timeout int64
+
+const Issuer = "lib"
+`,
+	})
+
+	mod, err := NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	history, err := BuildAPIHistory(mod, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0", Changed: "v1.1.0"}, history[HistoryKey("auth", "Claims")])
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0", Changed: "v1.1.0"}, history[HistoryKey("auth", "Timeout")])
+	// Unexported fields are not part of the API.
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0"}, history[HistoryKey("auth", "Scope")])
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0"}, history[HistoryKey("auth", "Issuer")])
+}
+
+func TestBuildAPIHistoryIsOffline(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// A tag that depends on a module that is not in the module cache must neither be
+	// downloaded nor loaded.
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	dir := t.TempDir()
+	out, err := exec.Command("git", "init", "-q", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	commitAndTag(t, dir, "v1.0.0", map[string]string{
+		"go.mod": "module example.com/lib\n\ngo 1.21\n\nrequire example.invalid/dep v1.2.3\n",
+		"auth/auth.go": `package auth
+
+import "example.invalid/dep"
+
+func Issue(c dep.Claims) dep.Token { return dep.Token{} }
+`,
+	})
+
+	mod, err := NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	var lines []string
+	history, err := BuildAPIHistory(mod, func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, SymbolHistory{Since: "v1.0.0"}, history[HistoryKey("auth", "Issue")])
+	for _, line := range lines {
+		assert.NotContains(t, line, "packageLoader")
+		assert.NotContains(t, line, "typeCheck")
+		assert.NotContains(t, line, "skipping tag")
+	}
+}
+
+func TestSemverTags(t *testing.T) {
+	tags := semverTags([]string{"v1.10.0", "v1.2.0", "latest", "sub/v2.0.0", "v1.2.0-rc.1"}, ".")
+	assert.Equal(t, []versionTag{
+		{name: "v1.2.0-rc.1", version: "v1.2.0-rc.1"},
+		{name: "v1.2.0", version: "v1.2.0"},
+		{name: "v1.10.0", version: "v1.10.0"},
+	}, tags)
+
+	assert.Equal(t, []versionTag{{name: "sub/v2.0.0", version: "v2.0.0"}}, semverTags([]string{"v1.0.0", "sub/v2.0.0"}, "sub"))
+}
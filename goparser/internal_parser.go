@@ -124,6 +124,15 @@ type parseContext struct {
 	docCtx *docConcatContext
 }
 
+// line returns the line, starting from 1, of the position or zero if unknown.
+func (fs fileSource) line(pos token.Pos) int {
+	if fs.fset == nil || !pos.IsValid() {
+		return 0
+	}
+
+	return fs.fset.PositionFor(pos, false).Line
+}
+
 func (fs fileSource) slice(start, end token.Pos) string {
 	if len(fs.data) == 0 || fs.fset == nil || !start.IsValid() || !end.IsValid() {
 		return ""
@@ -196,12 +205,17 @@ func parseFileWithContext(
 		BuildTags: extractBuildTagsFromComments(file.Comments),
 		Structs:   []*GoStruct{},
 	}
+	goFile.Generated, goFile.Generator = generatedBy(file)
+	goFile.Directives = fileDirectives(goFile, file)
+	goFile.Constraint = fileConstraint(path, file)
 
 	if mod != nil {
 		if fq, err := mod.ResolvePackage(path); err == nil {
 			goFile.FqPackage = fq
 		} else {
 			mod.AddUnresolvedDeclaration(UnresolvedDecl{
+				Kind:    UnresolvedPackage,
+				File:    path,
 				Message: fmt.Sprintf("resolve package: %v", err),
 			})
 		}
@@ -222,6 +236,8 @@ func parseFileWithContext(
 				// TypeSpec: A TypeSpec node represents a type declaration: https://golang.org/pkg/go/ast/#TypeSpec
 				case *ast.TypeSpec:
 					typeSpec := genSpecType
+					directives := directivesOf(goFile, declType.Doc, typeSpec.Doc)
+					line := src.line(typeSpec.Pos())
 					// typeSpec.Type: an Expr (expression) node: https://golang.org/pkg/go/ast/#Expr
 					switch typeSpecType := typeSpec.Type.(type) {
 
@@ -230,14 +246,19 @@ func parseFileWithContext(
 						structType := typeSpecType
 						goStruct := buildGoStruct(ctx, src, goFile, info, typeSpec.Name.Name, typeSpec.TypeParams, structType)
 						goStruct.Doc = docString(ctx, declType.Doc, decl.Pos())
+						goStruct.Directives = directives
+						goStruct.Line = line
 						goStruct.Decl = "type " + NameWithTypeParams(genSpecType.Name.Name, goStruct.TypeParams) + " struct"
 						goStruct.FullDecl = src.slice(decl.Pos(), decl.End())
 						goFile.Structs = append(goFile.Structs, goStruct)
+						mod.registerStruct(goFile.FqPackage, goStruct)
 					// InterfaceType: An InterfaceType node represents an interface type. https://golang.org/pkg/go/ast/#InterfaceType
 					case (*ast.InterfaceType):
 						interfaceType := typeSpecType
 						goInterface := buildGoInterface(ctx, src, goFile, info, typeSpec, interfaceType)
 						goInterface.Doc = docString(ctx, declType.Doc, decl.Pos())
+						goInterface.Directives = directives
+						goInterface.Line = line
 						goInterface.Decl = "type " + NameWithTypeParams(genSpecType.Name.Name, goInterface.TypeParams) + " interface"
 						goInterface.FullDecl = src.slice(decl.Pos(), decl.End())
 						goFile.Interfaces = append(goFile.Interfaces, goInterface)
@@ -253,6 +274,9 @@ func parseFileWithContext(
 						}
 
 						goCustomType.TypeParams = buildTypeParamList(ctx, goFile, info, typeSpec.TypeParams, src)
+						goCustomType.TypeInfo = buildType(ctx, goFile, info, typeSpec.Type, src)
+						goCustomType.Directives = directives
+						goCustomType.Line = line
 
 						goFile.CustomTypes = append(goFile.CustomTypes, goCustomType)
 					case (*ast.FuncType):
@@ -272,6 +296,8 @@ func parseFileWithContext(
 						aliasParams := buildTypeParamList(ctx, goFile, info, typeSpec.TypeParams, src)
 						funcParams := buildTypeParamList(ctx, goFile, info, funcType.TypeParams, src)
 						goMethod.TypeParams = append(aliasParams, funcParams...)
+						goMethod.Directives = directives
+						goMethod.Line = line
 
 						goFile.CustomFuncs = append(goFile.CustomFuncs, goMethod)
 					case (*ast.SelectorExpr):
@@ -295,6 +321,9 @@ func parseFileWithContext(
 						}
 
 						goCustomType.TypeParams = buildTypeParamList(ctx, goFile, info, typeSpec.TypeParams, src)
+						goCustomType.TypeInfo = buildType(ctx, goFile, info, typeSpec.Type, src)
+						goCustomType.Directives = directives
+						goCustomType.Line = line
 
 						goFile.CustomTypes = append(goFile.CustomTypes, goCustomType)
 					case (*ast.ArrayType):
@@ -322,6 +351,9 @@ func parseFileWithContext(
 						}
 
 						goCustomType.TypeParams = buildTypeParamList(ctx, goFile, info, typeSpec.TypeParams, src)
+						goCustomType.TypeInfo = buildType(ctx, goFile, info, typeSpec.Type, src)
+						goCustomType.Directives = directives
+						goCustomType.Line = line
 
 						goFile.CustomTypes = append(goFile.CustomTypes, goCustomType)
 					case (*ast.MapType):
@@ -342,6 +374,9 @@ func parseFileWithContext(
 						}
 
 						goCustomType.TypeParams = buildTypeParamList(ctx, goFile, info, typeSpec.TypeParams, src)
+						goCustomType.TypeInfo = buildType(ctx, goFile, info, typeSpec.Type, src)
+						goCustomType.Directives = directives
+						goCustomType.Line = line
 
 						goFile.CustomTypes = append(goFile.CustomTypes, goCustomType)
 
@@ -359,6 +394,9 @@ func parseFileWithContext(
 						}
 
 						goCustomType.TypeParams = buildTypeParamList(ctx, goFile, info, typeSpec.TypeParams, src)
+						goCustomType.TypeInfo = buildType(ctx, goFile, info, typeSpec.Type, src)
+						goCustomType.Directives = directives
+						goCustomType.Line = line
 
 						goFile.CustomTypes = append(goFile.CustomTypes, goCustomType)
 
@@ -374,9 +412,14 @@ func parseFileWithContext(
 							ast.Fprint(buf, fset, typeSpecType, ast.NotNilFilter)
 							fmt.Fprintf(buf, "----------------------")
 
+							pos := fset.Position(typeSpec.Type.Pos())
 							mod.AddUnresolvedDeclaration(UnresolvedDecl{
 								Expr:    typeSpec.Type,
 								Message: buf.String(),
+								Kind:    UnresolvedType,
+								File:    path,
+								Line:    pos.Line,
+								Column:  pos.Column,
 							})
 						}
 					}
@@ -406,6 +449,7 @@ func parseFileWithContext(
 			goStructMethod := buildStructMethod(ctx, goFile, info, funcDecl, src)
 			goStructMethod.Decl = src.slice(funcDecl.Type.Pos(), funcDecl.Type.End())
 			goStructMethod.FullDecl = src.slice(decl.Pos(), decl.End())
+			goStructMethod.Line = src.line(funcDecl.Pos())
 			goFile.StructMethods = append(goFile.StructMethods, goStructMethod)
 
 		default:
@@ -91,3 +91,53 @@ const (
 		t.Fatalf("expected no doc for Decl %q, got %q", want, assignments[0].Doc)
 	}
 }
+
+func TestConstImplicitTypeWithoutTypeInfo(t *testing.T) {
+	src := `package foo
+
+type Kind int
+
+const (
+	A Kind = iota
+	B
+	C
+	D = 10
+	E
+	F, G Kind = 1, 2
+	H, I
+)
+
+var V, W int
+`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "kind.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	// No type information, as on the legacy path when type-checking is not possible.
+	goFile, err := parseFileWithContext(&parseContext{}, nil, "kind.go", []byte(src), file, fset, nil)
+	if err != nil {
+		t.Fatalf("parseFileWithContext: %v", err)
+	}
+
+	want := map[string]string{
+		"A": "Kind", "B": "Kind", "C": "Kind", "D": "", "E": "",
+		"F": "Kind", "G": "Kind", "H": "Kind", "I": "Kind",
+	}
+	if len(goFile.ConstAssignments) != len(want) {
+		t.Fatalf("expected %d constants, got %d", len(want), len(goFile.ConstAssignments))
+	}
+	for _, c := range goFile.ConstAssignments {
+		if c.Type != want[c.Name] {
+			t.Errorf("constant %s: expected type %q, got %q", c.Name, want[c.Name], c.Type)
+		}
+	}
+
+	for _, v := range goFile.VarAssignments {
+		if v.Type != "int" {
+			t.Errorf("variable %s: expected type int, got %q", v.Name, v.Type)
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package goparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+	"regexp"
+	"strings"
+)
+
+// JSONSchemaDialect is the JSON Schema dialect emitted by GoStruct.JSONSchema.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema (draft 2020-12) document or sub-schema. Only the
+// keywords needed to describe Go types encoded by encoding/json are present.
+type JSONSchema struct {
+	Schema               string                `json:"$schema,omitempty"`
+	ID                   string                `json:"$id,omitempty"`
+	Ref                  string                `json:"$ref,omitempty"`
+	Title                string                `json:"title,omitempty"`
+	Description          string                `json:"description,omitempty"`
+	Default              json.RawMessage       `json:"default,omitempty"`
+	Examples             []json.RawMessage     `json:"examples,omitempty"`
+	Type                 string                `json:"type,omitempty"`
+	Format               string                `json:"format,omitempty"`
+	ContentEncoding      string                `json:"contentEncoding,omitempty"`
+	Minimum              *int64                `json:"minimum,omitempty"`
+	Items                *JSONSchema           `json:"items,omitempty"`
+	MinItems             *int64                `json:"minItems,omitempty"`
+	MaxItems             *int64                `json:"maxItems,omitempty"`
+	Properties           *JSONSchemaProperties `json:"properties,omitempty"`
+	Required             []string              `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema           `json:"additionalProperties,omitempty"`
+	Defs                 *JSONSchemaProperties `json:"$defs,omitempty"`
+}
+
+// JSONSchemaProperties is a set of named schemas that keeps insertion order
+// when marshalled, so properties are listed in field declaration order.
+type JSONSchemaProperties struct {
+	Names   []string
+	Schemas map[string]*JSONSchema
+}
+
+// Set adds or replaces the schema for name.
+func (p *JSONSchemaProperties) Set(name string, schema *JSONSchema) {
+	if p.Schemas == nil {
+		p.Schemas = map[string]*JSONSchema{}
+	}
+
+	if _, ok := p.Schemas[name]; !ok {
+		p.Names = append(p.Names, name)
+	}
+
+	p.Schemas[name] = schema
+}
+
+// Get returns the schema for name or nil if not present.
+func (p *JSONSchemaProperties) Get(name string) *JSONSchema {
+	if p == nil {
+		return nil
+	}
+
+	return p.Schemas[name]
+}
+
+// MarshalJSON renders the properties in insertion order.
+func (p *JSONSchemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for i, name := range p.Names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(p.Schemas[name])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// String renders the schema as indented JSON.
+func (js *JSONSchema) String() string {
+	data, err := json.MarshalIndent(js, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+
+	return string(data)
+}
+
+// ToJSONSchema generates a JSON Schema (draft 2020-12) for the struct as
+// indented JSON.
+func (s *GoStruct) ToJSONSchema() string {
+	return s.JSONSchema().String()
+}
+
+// JSONSchema generates a JSON Schema (draft 2020-12) describing how the struct
+// is encoded by encoding/json. The json tags are honoured, descriptions are
+// taken from the documentation and referenced named structs, also from other
+// packages, are placed in $defs.
+func (s *GoStruct) JSONSchema() *JSONSchema {
+	if s == nil {
+		return &JSONSchema{Schema: JSONSchemaDialect}
+	}
+
+	b := &schemaBuilder{
+		defs:  &JSONSchemaProperties{},
+		names: map[string]string{},
+	}
+
+	b.registry = defaultExampleRegistry
+	if s.File != nil {
+		b.registry = s.File.Module.exampleRegistry()
+	}
+
+	if s.File != nil && s.File.FqPackage != "" {
+		b.root = s.File.FqPackage + "." + s.Name
+	}
+
+	schema := b.structSchema(fieldsOfGoStruct(s))
+	schema.Schema = JSONSchemaDialect
+	schema.Title = s.Name
+	schema.Description = strings.TrimSpace(s.Doc)
+
+	if len(b.defs.Names) > 0 {
+		schema.Defs = b.defs
+	}
+
+	return schema
+}
+
+// schemaBuilder keeps track of the $defs while building a schema.
+type schemaBuilder struct {
+	// root is the fully qualified name of the struct the schema is built for.
+	root     string
+	registry *ExampleRegistry
+	defs     *JSONSchemaProperties
+	// names maps the fully qualified type name onto the $defs name.
+	names map[string]string
+}
+
+var schemaDefNameRegex = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// wellKnownSchemas are types whose encoding is not derivable from their structure.
+var wellKnownSchemas = map[string]func() *JSONSchema{
+	"time.Time":                   func() *JSONSchema { return &JSONSchema{Type: "string", Format: "date-time"} },
+	"time.Duration":               func() *JSONSchema { return &JSONSchema{Type: "integer"} },
+	"encoding/json.RawMessage":    func() *JSONSchema { return &JSONSchema{} },
+	"encoding/json.Number":        func() *JSONSchema { return &JSONSchema{Type: "number"} },
+	"net.IP":                      func() *JSONSchema { return &JSONSchema{Type: "string"} },
+	"net/netip.Addr":              func() *JSONSchema { return &JSONSchema{Type: "string"} },
+	"net/netip.Prefix":            func() *JSONSchema { return &JSONSchema{Type: "string"} },
+	"math/big.Int":                func() *JSONSchema { return &JSONSchema{Type: "integer"} },
+	"github.com/google/uuid.UUID": func() *JSONSchema { return &JSONSchema{Type: "string", Format: "uuid"} },
+}
+
+func (b *schemaBuilder) structSchema(fields []structField) *JSONSchema {
+	schema := &JSONSchema{
+		Type:       "object",
+		Properties: &JSONSchemaProperties{},
+	}
+
+	for _, f := range encodedFields(jsonRules, fields) {
+		var prop *JSONSchema
+		if f.anonymous != nil {
+			prop = b.structSchema(fieldsOfGoStruct(f.anonymous))
+		} else {
+			prop = b.schemaFor(f.typ, f.typeStr, f.file)
+		}
+
+		if f.asString {
+			prop = stringOptionSchema(prop)
+		}
+
+		if f.doc != "" && prop.Ref == "" {
+			prop.Description = f.doc
+		} else if f.doc != "" {
+			prop = &JSONSchema{Description: f.doc, Ref: prop.Ref}
+		}
+
+		if f.defaultValue != "" {
+			prop.Default = json.RawMessage(f.defaultValue)
+		}
+
+		if f.example != "" {
+			prop.Examples = []json.RawMessage{json.RawMessage(f.example)}
+		}
+
+		schema.Properties.Set(f.key, prop)
+
+		if !f.omitEmpty {
+			schema.Required = append(schema.Required, f.key)
+		}
+	}
+
+	return schema
+}
+
+// stringOptionSchema applies the ",string" json option, which encodes scalars
+// as JSON strings.
+func stringOptionSchema(schema *JSONSchema) *JSONSchema {
+	switch schema.Type {
+	case "integer", "number", "boolean":
+		return &JSONSchema{Type: "string"}
+	}
+
+	return schema
+}
+
+// schemaFor returns the schema for a type. When no type information is
+// present, the schema is derived from the source representation.
+func (b *schemaBuilder) schemaFor(typ types.Type, typeStr string, file *GoFile) *JSONSchema {
+	if typ == nil {
+		return b.schemaForTypeString(typeStr, file)
+	}
+
+	// Well-known types may be aliases e.g. encoding/json.RawMessage
+	if alias, ok := typ.(*types.Alias); ok {
+		if schema := b.wellKnownSchema(qualifiedName(alias.Obj())); schema != nil {
+			return schema
+		}
+	}
+
+	switch t := types.Unalias(typ).(type) {
+	case *types.Named:
+		return b.namedSchema(t, file)
+	case *types.Pointer:
+		return b.schemaFor(t.Elem(), "", file)
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		if isByte(t.Elem()) {
+			return &JSONSchema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &JSONSchema{Type: "array", Items: b.schemaFor(t.Elem(), "", file)}
+	case *types.Array:
+		n := t.Len()
+		return &JSONSchema{
+			Type:     "array",
+			Items:    b.schemaFor(t.Elem(), "", file),
+			MinItems: &n,
+			MaxItems: &n,
+		}
+	case *types.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: b.schemaFor(t.Elem(), "", file)}
+	case *types.Struct:
+		return b.structSchema(fieldsOfTypesStruct(t, nil))
+	}
+
+	return &JSONSchema{}
+}
+
+func (b *schemaBuilder) namedSchema(named *types.Named, file *GoFile) *JSONSchema {
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		// Universe types such as error
+		return &JSONSchema{}
+	}
+
+	fqName := qualifiedName(obj)
+	if schema := b.wellKnownSchema(fqName); schema != nil {
+		return schema
+	}
+
+	if hasMethod(named, "MarshalJSON") {
+		return &JSONSchema{}
+	}
+
+	if hasMethod(named, "MarshalText") {
+		return &JSONSchema{Type: "string"}
+	}
+
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return b.schemaFor(named.Underlying(), "", file)
+	}
+
+	if fqName == b.root && named.TypeArgs().Len() == 0 {
+		return &JSONSchema{Ref: "#"}
+	}
+
+	key := named.String()
+	if name, ok := b.names[key]; ok {
+		return &JSONSchema{Ref: "#/$defs/" + name}
+	}
+
+	name := b.defName(named)
+	b.names[key] = name
+	// Reserve the slot before recursing so self references terminate
+	b.defs.Set(name, &JSONSchema{})
+
+	decl := lookupNamedStruct(named, file)
+	def := b.structSchema(structFieldsFor(named, "", file))
+	def.Title = obj.Name()
+	if decl != nil {
+		def.Description = strings.TrimSpace(decl.Doc)
+	}
+
+	b.defs.Set(name, def)
+
+	return &JSONSchema{Ref: "#/$defs/" + name}
+}
+
+// defName returns a unique $defs name for a named type, qualifying it with the
+// package name or path on collisions.
+func (b *schemaBuilder) defName(named *types.Named) string {
+	obj := named.Obj()
+	base := obj.Name()
+	if named.TypeArgs().Len() > 0 {
+		base = types.TypeString(named, relativeQualifier(obj.Pkg()))
+	}
+
+	candidates := []string{
+		base,
+		obj.Pkg().Name() + "." + base,
+		obj.Pkg().Path() + "." + base,
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.Trim(schemaDefNameRegex.ReplaceAllString(candidate, "_"), "_")
+		if b.defs.Get(candidate) == nil {
+			return candidate
+		}
+	}
+
+	return strings.Trim(schemaDefNameRegex.ReplaceAllString(named.String(), "_"), "_")
+}
+
+// schemaForTypeString derives a schema from the source representation of a
+// type. It is used when type checking did not produce any information.
+func (b *schemaBuilder) schemaForTypeString(typeStr string, file *GoFile) *JSONSchema {
+	typeStr = strings.TrimSpace(typeStr)
+
+	switch {
+	case strings.HasPrefix(typeStr, "*"):
+		return b.schemaForTypeString(typeStr[1:], file)
+	case typeStr == "[]byte":
+		return &JSONSchema{Type: "string", ContentEncoding: "base64"}
+	case strings.HasPrefix(typeStr, "[]"):
+		return &JSONSchema{Type: "array", Items: b.schemaForTypeString(typeStr[2:], file)}
+	case strings.HasPrefix(typeStr, "map["):
+		value := &JSONSchema{}
+		if end := matchingBracket(typeStr, len("map")); end > 0 {
+			value = b.schemaForTypeString(typeStr[end+1:], file)
+		}
+		return &JSONSchema{Type: "object", AdditionalProperties: value}
+	}
+
+	switch typeStr {
+	case "string":
+		return &JSONSchema{Type: "string"}
+	case "bool":
+		return &JSONSchema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return &JSONSchema{Type: "integer"}
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte":
+		return unsignedSchema()
+	case "float32", "float64":
+		return &JSONSchema{Type: "number"}
+	case "time.Time":
+		return wellKnownSchemas["time.Time"]()
+	case "time.Duration":
+		return wellKnownSchemas["time.Duration"]()
+	}
+
+	if decl := lookupLocalStruct(typeStr, file); decl != nil {
+		key := file.FqPackage + "." + decl.Name
+		if key == b.root {
+			return &JSONSchema{Ref: "#"}
+		}
+
+		if name, ok := b.names[key]; ok {
+			return &JSONSchema{Ref: "#/$defs/" + name}
+		}
+
+		b.names[key] = decl.Name
+		b.defs.Set(decl.Name, &JSONSchema{})
+
+		def := b.structSchema(fieldsOfGoStruct(decl))
+		def.Title = decl.Name
+		def.Description = strings.TrimSpace(decl.Doc)
+		b.defs.Set(decl.Name, def)
+
+		return &JSONSchema{Ref: "#/$defs/" + decl.Name}
+	}
+
+	return &JSONSchema{}
+}
+
+// matchingBracket returns the index of the bracket closing the one at start.
+func matchingBracket(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// wellKnownSchema returns the schema for a well-known or registered type, or nil.
+func (b *schemaBuilder) wellKnownSchema(fqName string) *JSONSchema {
+	if wellKnown, ok := wellKnownSchemas[fqName]; ok {
+		return wellKnown()
+	}
+
+	if value, ok := b.registry.Lookup(fqName); ok {
+		return registrySchema(value)
+	}
+
+	return nil
+}
+
+// registrySchema derives the schema of a registered type from its example encoding.
+func registrySchema(value ExampleValue) *JSONSchema {
+	switch value.Encoding {
+	case "", ExampleEncodingString:
+		return &JSONSchema{Type: "string"}
+	case ExampleEncodingNumber:
+		return &JSONSchema{Type: "number"}
+	case ExampleEncodingBool:
+		return &JSONSchema{Type: "boolean"}
+	}
+
+	return &JSONSchema{}
+}
+
+func basicSchema(t *types.Basic) *JSONSchema {
+	info := t.Info()
+
+	switch {
+	case info&types.IsBoolean != 0:
+		return &JSONSchema{Type: "boolean"}
+	case info&types.IsString != 0:
+		return &JSONSchema{Type: "string"}
+	case info&types.IsUnsigned != 0:
+		return unsignedSchema()
+	case info&types.IsInteger != 0:
+		return &JSONSchema{Type: "integer"}
+	case info&types.IsFloat != 0:
+		return &JSONSchema{Type: "number"}
+	}
+
+	return &JSONSchema{}
+}
+
+func unsignedSchema() *JSONSchema {
+	var zero int64
+	return &JSONSchema{Type: "integer", Minimum: &zero}
+}
+
+func isByte(typ types.Type) bool {
+	basic, ok := types.Unalias(typ).(*types.Basic)
+	return ok && basic.Kind() == types.Byte
+}
+
+// hasMethod reports whether the named type or a pointer to it has the method.
+func hasMethod(named *types.Named, method string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), method)
+	_, ok := obj.(*types.Func)
+	return ok
+}
@@ -0,0 +1,159 @@
+package goparser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSchemaModule(t *testing.T) (*GoModule, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.24\n",
+		"address/address.go": `package address
+
+// Address is a postal address.
+type Address struct {
+	// Street including number.
+	Street string ` + "`json:\"street\"`" + `
+	Zip    string ` + "`json:\"zip,omitempty\"`" + `
+}
+`,
+		"order/order.go": `package order
+
+import (
+	"time"
+
+	"example.com/shop/address"
+)
+
+// Base carries common fields.
+type Base struct {
+	ID int64 ` + "`json:\"id,string\"`" + `
+}
+
+// Line is a single order line.
+type Line struct {
+	SKU      string ` + "`json:\"sku\"`" + `
+	Quantity uint   ` + "`json:\"qty\"`" + `
+}
+
+// Order is placed by a customer.
+type Order struct {
+	Base
+	// Created is when the order was placed.
+	Created  time.Time          ` + "`json:\"created\"`" + `
+	Lines    []Line             ` + "`json:\"lines\"`" + `
+	Ship     *address.Address   ` + "`json:\"ship,omitempty\"`" + `
+	Labels   map[string]string  ` + "`json:\"labels,omitempty\"`" + `
+	Payload  []byte             ` + "`json:\"payload\"`" + `
+	Parent   *Order             ` + "`json:\"parent,omitempty\"`" + `
+	Secret   string             ` + "`json:\"-\"`" + `
+	internal string
+}
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	mod, err := NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	return mod, dir
+}
+
+func TestJSONSchemaFromStruct(t *testing.T) {
+	mod, dir := writeSchemaModule(t)
+
+	var order *GoStruct
+	err := ParseSinglePackageWalker(
+		ParseConfig{Module: mod},
+		func(pkg *GoPackage) error {
+			for _, s := range pkg.Structs {
+				if s.Name == "Order" {
+					order = s
+				}
+			}
+			return nil
+		},
+		dir,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, order)
+
+	schema := order.JSONSchema()
+
+	assert.Equal(t, JSONSchemaDialect, schema.Schema)
+	assert.Equal(t, "Order", schema.Title)
+	assert.Equal(t, "Order is placed by a customer.", schema.Description)
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"id", "created", "lines", "payload"}, schema.Required)
+	assert.Equal(
+		t,
+		[]string{"id", "created", "lines", "ship", "labels", "payload", "parent"},
+		schema.Properties.Names,
+	)
+
+	id := schema.Properties.Get("id")
+	assert.Equal(t, "string", id.Type, "the ,string option encodes integers as strings")
+
+	created := schema.Properties.Get("created")
+	assert.Equal(t, "string", created.Type)
+	assert.Equal(t, "date-time", created.Format)
+	assert.Equal(t, "Created is when the order was placed.", created.Description)
+
+	lines := schema.Properties.Get("lines")
+	assert.Equal(t, "array", lines.Type)
+	assert.Equal(t, "#/$defs/Line", lines.Items.Ref)
+
+	assert.Equal(t, "#/$defs/Address", schema.Properties.Get("ship").Ref)
+	assert.Equal(t, "string", schema.Properties.Get("labels").AdditionalProperties.Type)
+	assert.Equal(t, "base64", schema.Properties.Get("payload").ContentEncoding)
+	assert.Equal(t, "#", schema.Properties.Get("parent").Ref)
+
+	require.NotNil(t, schema.Defs)
+	address := schema.Defs.Get("Address")
+	require.NotNil(t, address)
+	assert.Equal(t, "Address is a postal address.", address.Description)
+	assert.Equal(t, []string{"street"}, address.Required)
+	assert.Equal(t, "Street including number.", address.Properties.Get("street").Description)
+
+	line := schema.Defs.Get("Line")
+	require.NotNil(t, line)
+	require.NotNil(t, line.Properties.Get("qty").Minimum)
+	assert.Equal(t, int64(0), *line.Properties.Get("qty").Minimum)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(order.ToJSONSchema()), &decoded))
+	assert.Equal(t, JSONSchemaDialect, decoded["$schema"])
+}
+
+func TestJSONSchemaWithoutTypeInformation(t *testing.T) {
+	s := &GoStruct{
+		Name: "Plain",
+		Fields: []*GoField{
+			{Name: "Name", Type: "string", Exported: true, Tag: &GoTag{Value: "`json:\"name\"`"}},
+			{Name: "Tags", Type: "[]string", Exported: true, Tag: &GoTag{Value: "`json:\"tags,omitempty\"`"}},
+			{Name: "Counts", Type: "map[string]int", Exported: true},
+			{Name: "hidden", Type: "string"},
+		},
+	}
+
+	schema := s.JSONSchema()
+
+	assert.Equal(t, []string{"name", "tags", "Counts"}, schema.Properties.Names)
+	assert.Equal(t, []string{"name", "Counts"}, schema.Required)
+	assert.Equal(t, "string", schema.Properties.Get("tags").Items.Type)
+	assert.Equal(t, "integer", schema.Properties.Get("Counts").AdditionalProperties.Type)
+	assert.Nil(t, schema.Defs)
+}
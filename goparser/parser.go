@@ -1,12 +1,14 @@
 package goparser
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"go/types"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -27,10 +29,52 @@ func recordTypeCheckError(mod *GoModule, context string, err error) {
 		return
 	}
 
-	message := fmt.Sprintf("type-check error for %s: %v", context, err)
-	mod.AddUnresolvedDeclaration(UnresolvedDecl{
-		Message: message,
-	})
+	u := UnresolvedDecl{
+		Kind:    UnresolvedTypeCheck,
+		Message: fmt.Sprintf("type-check error for %s: %v", context, err),
+	}
+
+	var (
+		typeErr types.Error
+		pkgErr  packages.Error
+	)
+	// The position is kept separate from the message when known.
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Fset != nil:
+		pos := typeErr.Fset.Position(typeErr.Pos)
+		u.File, u.Line, u.Column = pos.Filename, pos.Line, pos.Column
+		u.Message = fmt.Sprintf("type-check error for %s: %s", context, typeErr.Msg)
+	case errors.As(err, &pkgErr) && pkgErr.Pos != "" && pkgErr.Pos != "-":
+		u.File, u.Line, u.Column = splitPosition(pkgErr.Pos)
+		u.Message = fmt.Sprintf("type-check error for %s: %s", context, pkgErr.Msg)
+	}
+
+	mod.AddUnresolvedDeclaration(u)
+}
+
+// splitPosition splits a file:line:col, or file:line, position. Line and column are zero when
+// not present.
+func splitPosition(pos string) (string, int, int) {
+	file, line, col := pos, 0, 0
+	for i := 0; i < 2; i++ {
+		idx := strings.LastIndex(file, ":")
+		if idx < 0 {
+			break
+		}
+
+		n, err := strconv.Atoi(file[idx+1:])
+		if err != nil {
+			break
+		}
+
+		file, line, col = file[:idx], n, line
+	}
+
+	if line == 0 {
+		col = 0
+	}
+
+	return file, line, col
 }
 
 // ParseSingleFile parses a single file at the same time
@@ -82,20 +126,48 @@ func parseFiles(config ParseConfig, paths ...string) ([]*GoFile, error) {
 		return nil, fmt.Errorf("must specify at least one path to file to parse")
 	}
 
-	if config.Module == nil {
-		return parseFilesLegacy(config, paths...)
+	if config.Module == nil || config.syntaxOnly {
+		goFiles, err := parseFilesLegacy(config, paths...)
+		if err != nil {
+			return nil, err
+		}
+		return filterGenerated(config, goFiles), nil
 	}
 
 	goFiles, err := parseFilesWithPackages(config, paths...)
 	if err != nil {
 		if shouldFallbackToLegacy(err) {
 			debugf(config.Debug, "ParseFiles: falling back to legacy parser due to: %v", err)
-			return parseFilesLegacy(config, paths...)
+			config.Module.AddUnresolvedDeclaration(UnresolvedDecl{
+				Kind:    UnresolvedLegacyFallback,
+				Message: fmt.Sprintf("falling back to the legacy parser, type information may be missing: %v", err),
+			})
+			goFiles, err = parseFilesLegacy(config, paths...)
+		}
+		if err != nil {
+			return nil, err
 		}
-		return nil, err
 	}
 
-	return goFiles, nil
+	return filterGenerated(config, goFiles), nil
+}
+
+// filterGenerated removes the generated files when the config excludes those.
+func filterGenerated(config ParseConfig, goFiles []*GoFile) []*GoFile {
+	if config.Generated != GeneratedExclude {
+		return goFiles
+	}
+
+	filtered := goFiles[:0]
+	for _, gf := range goFiles {
+		if gf.Generated {
+			debugf(config.Debug, "ParseFiles: skipping generated file %s", gf.FilePath)
+			continue
+		}
+		filtered = append(filtered, gf)
+	}
+
+	return filtered
 }
 
 func shouldFallbackToLegacy(err error) bool {
@@ -123,6 +195,18 @@ func parseFilesWithPackages(config ParseConfig, paths ...string) ([]*GoFile, err
 		file *ast.File
 	}
 
+	parseExcludedFile := func(path string) (fileContext, bool) {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			debugf(debug, "ParseFiles: failed to parse excluded file %s: %v", path, err)
+			return fileContext{}, false
+		}
+
+		debugf(debug, "ParseFiles: parsed %s without type information (excluded by build constraints)", path)
+		return fileContext{pkg: &packages.Package{Fset: fset}, file: file}, true
+	}
+
 	contexts := make([]fileContext, len(paths))
 	absPaths := make([]string, len(paths))
 	dirIndexes := make(map[string][]int)
@@ -199,6 +283,11 @@ func parseFilesWithPackages(config ParseConfig, paths ...string) ([]*GoFile, err
 		for _, idx := range indexes {
 			abs := absPaths[idx]
 			ctx, ok := fileMap[abs]
+			if !ok && config.AllBuildTags {
+				// Excluded by build constraints, e.g. a _windows.go file, parse it without
+				// type information to document all platform variants
+				ctx, ok = parseExcludedFile(abs)
+			}
 			if !ok {
 				// File not loaded - likely excluded by build constraints
 				debugf(debug, "ParseFiles: skipping %s (excluded by build constraints)", abs)
@@ -307,6 +396,10 @@ func parseFilesLegacy(config ParseConfig, paths ...string) ([]*GoFile, error) {
 	}
 
 	for key, bucket := range buckets {
+		if config.syntaxOnly {
+			continue
+		}
+
 		debugf(debug, "ParseFiles[legacy]: type-checking %s (%d file(s))", key, len(bucket.files))
 
 		info, typeErr := typeCheckPackage(mod, bucket.fset, bucket.files, debug)
@@ -401,6 +494,18 @@ const (
 	DocConcatenationFull
 )
 
+// GeneratedPolicy determines how generated files are handled when parsing.
+type GeneratedPolicy string
+
+const (
+	// GeneratedInclude documents generated files as any other file (default).
+	GeneratedInclude GeneratedPolicy = "include"
+	// GeneratedExclude skips generated files.
+	GeneratedExclude GeneratedPolicy = "exclude"
+	// GeneratedBadge documents generated files and marks them as generated.
+	GeneratedBadge GeneratedPolicy = "include-with-badge"
+)
+
 type ParseConfig struct {
 	// Test denotes if test files (ending with _test.go) should be included or not
 	// (default not included)
@@ -429,6 +534,14 @@ type ParseConfig struct {
 	// Excludes specifies regular expressions (or glb:-prefixed glob-like patterns) for paths to exclude from documentation generation.
 	// Patterns are applied to slash-separated absolute and relative paths.
 	Excludes []string
+	// Generated determines how files with a "// Code generated ... DO NOT EDIT." header
+	// are handled (default GeneratedInclude).
+	Generated GeneratedPolicy
+	// SymbolFilter, when set, removes symbols by name and kind from each parsed package.
+	SymbolFilter *SymbolFilter
+	// syntaxOnly parses the files without loading, or type-checking, the packages. Nothing, e.g.
+	// a dependency, is then resolved using the go command or the network.
+	syntaxOnly bool
 }
 
 // GetModuleForPath returns the appropriate module for a given file path
@@ -527,6 +640,11 @@ func ParseSingleFileWalker(
 			return err
 		}
 
+		if goFile.Generated && config.Generated == GeneratedExclude {
+			debugf(config.Debug, "ParseSingleFileWalker: skipping generated file %s", f)
+			continue
+		}
+
 		debugf(config.Debug, "ParseSingleFileWalker: processing %s", f)
 
 		if err := process(goFile); err != nil {
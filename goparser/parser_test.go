@@ -956,3 +956,147 @@ func TestCustomSlicePtr(t *testing.T) {
 	assert.Equal(t, "type LabelSet []*Label", f.CustomTypes[1].Decl)
 	assert.Equal(t, "LabelSet is a custom type", f.CustomTypes[1].Doc)
 }
+
+func TestDeclarationLinesAndAssignmentTypes(t *testing.T) {
+	src := `package mypkg
+
+import "time"
+
+// Level is a level.
+type Level int
+
+const (
+	Low Level = iota
+	High
+)
+
+const Max = 10
+
+var Timeout = 5 * time.Second
+
+var Default *Item
+
+type Item struct{}
+
+type Store interface {
+	Get() Item
+}
+
+type Handler func()
+
+func NewItem() *Item { return nil }
+`
+
+	m := dummyModule()
+	f, err := ParseInlineFile(m, m.Base+"/mypkg/file.go", src)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, f.CustomTypes[0].Line)
+	assert.Equal(t, 19, f.Structs[0].Line)
+	assert.Equal(t, 21, f.Interfaces[0].Line)
+	assert.Equal(t, 22, f.Interfaces[0].Methods[0].Line)
+	assert.Equal(t, 25, f.CustomFuncs[0].Line)
+	assert.Equal(t, 27, f.StructMethods[0].Line)
+
+	var consts, vars []string
+	for _, a := range f.ConstAssignments {
+		consts = append(consts, fmt.Sprintf("%s:%s:%d", a.Name, a.Type, a.Line))
+	}
+	for _, a := range f.VarAssignments {
+		vars = append(vars, fmt.Sprintf("%s:%s:%d", a.Name, a.Type, a.Line))
+	}
+
+	assert.Equal(t, []string{"Low:Level:9", "High:Level:10", "Max::13"}, consts)
+	assert.Equal(t, []string{"Timeout:time.Duration:15", "Default:*Item:17"}, vars)
+}
+
+func TestConstantValues(t *testing.T) {
+	src := `package mypkg
+
+type Level int
+
+const (
+	Low Level = iota
+	High
+)
+
+const (
+	Name    = "shop"
+	Pi      = 3.14
+	Big     = 1 << 70
+	Enabled = true
+	Greeting = Name + " says hi"
+)
+
+var Counter = 10
+`
+
+	m := dummyModule()
+	f, err := ParseInlineFile(m, m.Base+"/mypkg/file.go", src)
+	require.NoError(t, err)
+
+	values := map[string]string{}
+	for _, a := range append(f.ConstAssignments, f.VarAssignments...) {
+		values[a.Name] = a.Value
+	}
+
+	assert.Equal(t, map[string]string{
+		"Low":      "0",
+		"High":     "1",
+		"Name":     "shop",
+		"Pi":       "3.14",
+		"Big":      "1180591620717411303424",
+		"Enabled":  "true",
+		"Greeting": "shop says hi",
+		"Counter":  "",
+	}, values)
+}
+
+// TestTypeCheckErrorPosition verifies that type-check errors are recorded with their kind and
+// position.
+func TestTypeCheckErrorPosition(t *testing.T) {
+	mod, err := NewModuleFromBuff("go.mod", []byte("module github.com/test/example\ngo 1.21"))
+	require.NoError(t, err)
+
+	code := `package test
+
+// Broken returns an undefined type.
+func Broken() Missing { return nil }
+`
+
+	path := filepath.Join(t.TempDir(), "test.go")
+	require.NoError(t, os.WriteFile(path, []byte(code), 0o644))
+
+	_, err = ParseSingleFile(mod, path)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, mod.Unresolved)
+	u := mod.Unresolved[0]
+	assert.Equal(t, UnresolvedTypeCheck, u.Kind)
+	assert.Equal(t, path, u.File)
+	assert.Equal(t, 4, u.Line)
+	assert.Equal(t, 15, u.Column)
+	assert.Contains(t, u.Message, "undefined: Missing")
+}
+
+func TestSplitPosition(t *testing.T) {
+	tests := []struct {
+		pos  string
+		file string
+		line int
+		col  int
+	}{
+		{"a/b.go:3:4", "a/b.go", 3, 4},
+		{"a/b.go:3", "a/b.go", 3, 0},
+		{"a/b.go", "a/b.go", 0, 0},
+		{`C:\src\b.go:3:4`, `C:\src\b.go`, 3, 4},
+		{"", "", 0, 0},
+	}
+
+	for _, tc := range tests {
+		file, line, col := splitPosition(tc.pos)
+		assert.Equal(t, tc.file, file, tc.pos)
+		assert.Equal(t, tc.line, line, tc.pos)
+		assert.Equal(t, tc.col, col, tc.pos)
+	}
+}
@@ -0,0 +1,334 @@
+package goparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mariotoffia/goasciidoc/goparser/utils"
+)
+
+// SymbolKind is the kind of a symbol matched by a SymbolFilter.
+type SymbolKind string
+
+const (
+	// SymbolKindStruct is a struct type.
+	SymbolKindStruct SymbolKind = "struct"
+	// SymbolKindInterface is an interface type.
+	SymbolKindInterface SymbolKind = "interface"
+	// SymbolKindFunc is a function without a receiver.
+	SymbolKindFunc SymbolKind = "func"
+	// SymbolKindMethod is a method of a type or an interface.
+	SymbolKindMethod SymbolKind = "method"
+	// SymbolKindConst is a constant.
+	SymbolKindConst SymbolKind = "const"
+	// SymbolKindVar is a package level variable.
+	SymbolKindVar SymbolKind = "var"
+	// SymbolKindTypedef is any other type definition, e.g. type ID string or
+	// type Handler func().
+	SymbolKindTypedef SymbolKind = "typedef"
+	// SymbolKindField is a struct field.
+	SymbolKindField SymbolKind = "field"
+)
+
+// SymbolKinds are all symbol kinds a SymbolFilter may be restricted to.
+var SymbolKinds = []SymbolKind{
+	SymbolKindStruct,
+	SymbolKindInterface,
+	SymbolKindFunc,
+	SymbolKindMethod,
+	SymbolKindConst,
+	SymbolKindVar,
+	SymbolKindTypedef,
+	SymbolKindField,
+}
+
+// topLevelKinds are the kinds a rule without explicit kinds applies to when including.
+var topLevelKinds = []SymbolKind{
+	SymbolKindStruct,
+	SymbolKindInterface,
+	SymbolKindFunc,
+	SymbolKindConst,
+	SymbolKindVar,
+	SymbolKindTypedef,
+}
+
+// SymbolFilter removes symbols, matched by name and kind, from parsed packages.
+//
+// Each rule is written as [KIND[,KIND...]=]PATTERN where the pattern is a regular expression,
+// or a glb: prefixed glob, that is matched against the fully qualified name, e.g.
+// example.com/shop/model.Item.Price, and the name within the package, e.g. Item.Price. Methods
+// and fields are named by their owner and themselves. An empty pattern matches all symbols of
+// the kinds e.g. const= matches all constants.
+//
+// A symbol is removed when an exclude rule matches it. When there are include rules for the kind
+// of a symbol, it is also removed unless one of them matches it. Include rules without kinds
+// apply to the top level kinds, i.e. not to methods and fields, since those follow their
+// owner.
+type SymbolFilter struct {
+	includes []symbolRule
+	excludes []symbolRule
+}
+
+// symbolRule is a single, compiled, include or exclude rule.
+type symbolRule struct {
+	kinds   map[SymbolKind]struct{}
+	matcher interface {
+		Match(paths ...string) (bool, string)
+	}
+}
+
+// NewSymbolFilter compiles the include and exclude rules into a SymbolFilter. It returns nil
+// when there are no rules.
+func NewSymbolFilter(includes, excludes []string) (*SymbolFilter, error) {
+	if len(includes) == 0 && len(excludes) == 0 {
+		return nil, nil
+	}
+
+	filter := &SymbolFilter{}
+
+	for _, rule := range includes {
+		compiled, err := compileSymbolRule(rule, topLevelKinds)
+		if err != nil {
+			return nil, err
+		}
+		filter.includes = append(filter.includes, compiled)
+	}
+
+	for _, rule := range excludes {
+		compiled, err := compileSymbolRule(rule, SymbolKinds)
+		if err != nil {
+			return nil, err
+		}
+		filter.excludes = append(filter.excludes, compiled)
+	}
+
+	return filter, nil
+}
+
+// compileSymbolRule compiles a [KIND[,KIND...]=]PATTERN rule, the defaults are the kinds when
+// the rule does not have any.
+func compileSymbolRule(rule string, defaults []SymbolKind) (symbolRule, error) {
+	compiled := symbolRule{kinds: map[SymbolKind]struct{}{}}
+
+	pattern := strings.TrimSpace(rule)
+	if idx := strings.Index(pattern, "="); idx != -1 {
+		if kinds, ok := parseSymbolKinds(pattern[:idx]); ok {
+			for _, kind := range kinds {
+				compiled.kinds[kind] = struct{}{}
+			}
+			pattern = pattern[idx+1:]
+		}
+	}
+
+	if len(compiled.kinds) == 0 {
+		for _, kind := range defaults {
+			compiled.kinds[kind] = struct{}{}
+		}
+	}
+
+	if strings.TrimSpace(pattern) == "" {
+		pattern = ".*"
+	}
+
+	matcher, err := utils.NewRegexMatcher([]string{pattern})
+	if err != nil {
+		return symbolRule{}, fmt.Errorf("invalid symbol filter %q: %w", rule, err)
+	}
+
+	compiled.matcher = matcher
+	return compiled, nil
+}
+
+// parseSymbolKinds parses a comma separated list of kinds, it returns false if any of them is
+// not a known kind.
+func parseSymbolKinds(list string) ([]SymbolKind, bool) {
+	var kinds []SymbolKind
+	for _, name := range strings.Split(list, ",") {
+		kind := SymbolKind(strings.TrimSpace(name))
+
+		known := false
+		for _, k := range SymbolKinds {
+			if k == kind {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			return nil, false
+		}
+
+		kinds = append(kinds, kind)
+	}
+
+	return kinds, len(kinds) > 0
+}
+
+// Keep returns true if the symbol of kind, with the fully qualified name and the name within
+// its package, passes the filter.
+func (f *SymbolFilter) Keep(kind SymbolKind, fqName, name string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, rule := range f.excludes {
+		if rule.matches(kind, fqName, name) {
+			return false
+		}
+	}
+
+	included, restricted := false, false
+	for _, rule := range f.includes {
+		if _, ok := rule.kinds[kind]; !ok {
+			continue
+		}
+
+		restricted = true
+		if rule.matches(kind, fqName, name) {
+			included = true
+			break
+		}
+	}
+
+	return included || !restricted
+}
+
+func (r symbolRule) matches(kind SymbolKind, fqName, name string) bool {
+	if _, ok := r.kinds[kind]; !ok {
+		return false
+	}
+
+	match, _ := r.matcher.Match(fqName, name)
+	return match
+}
+
+// Apply removes the symbols that do not pass the filter from the package, and its files, and
+// returns the fully qualified names of the removed symbols. The methods of a removed type are
+// removed as well.
+func (f *SymbolFilter) Apply(pkg *GoPackage) []string {
+	if f == nil || pkg == nil {
+		return nil
+	}
+
+	pkgPath := pkg.FqPackage
+	if pkgPath == "" {
+		pkgPath = pkg.Package
+	}
+
+	var (
+		removed     []string
+		removedType = map[string]struct{}{}
+		seen        = map[string]struct{}{}
+		decided     = map[interface{}]bool{}
+	)
+
+	keep := func(node interface{}, kind SymbolKind, name string) bool {
+		if k, ok := decided[node]; ok {
+			return k
+		}
+
+		k := f.Keep(kind, pkgPath+"."+name, name)
+		if !k {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				removed = append(removed, pkgPath+"."+name)
+			}
+
+			switch kind {
+			case SymbolKindStruct, SymbolKindInterface, SymbolKindTypedef:
+				removedType[name] = struct{}{}
+			}
+		}
+
+		decided[node] = k
+		return k
+	}
+
+	filterFile := func(file *GoFile) {
+		file.Structs = filterSlice(file.Structs, func(s *GoStruct) bool {
+			if !keep(s, SymbolKindStruct, s.Name) {
+				return false
+			}
+
+			s.Fields = filterSlice(s.Fields, func(field *GoField) bool {
+				name := field.Name
+				if name == "" {
+					name = strings.TrimLeft(field.Type, "*")
+				}
+				return keep(field, SymbolKindField, s.Name+"."+name)
+			})
+			return true
+		})
+
+		file.Interfaces = filterSlice(file.Interfaces, func(i *GoInterface) bool {
+			if !keep(i, SymbolKindInterface, i.Name) {
+				return false
+			}
+
+			i.Methods = filterSlice(i.Methods, func(m *GoMethod) bool {
+				return keep(m, SymbolKindMethod, i.Name+"."+m.Name)
+			})
+			return true
+		})
+
+		file.CustomTypes = filterSlice(file.CustomTypes, func(c *GoCustomType) bool {
+			return keep(c, SymbolKindTypedef, c.Name)
+		})
+
+		file.CustomFuncs = filterSlice(file.CustomFuncs, func(m *GoMethod) bool {
+			return keep(m, SymbolKindTypedef, m.Name)
+		})
+
+		file.ConstAssignments = filterSlice(file.ConstAssignments, func(a *GoAssignment) bool {
+			return keep(a, SymbolKindConst, a.Name)
+		})
+
+		file.VarAssignments = filterSlice(file.VarAssignments, func(a *GoAssignment) bool {
+			return keep(a, SymbolKindVar, a.Name)
+		})
+	}
+
+	// Types first, so the methods of removed types can be removed as well.
+	filterFile(&pkg.GoFile)
+	for _, file := range pkg.Files {
+		filterFile(file)
+	}
+
+	filterMethods := func(methods []*GoStructMethod) []*GoStructMethod {
+		return filterSlice(methods, func(fn *GoStructMethod) bool {
+			owner := fn.ReceiverName()
+			if owner == "" {
+				return keep(fn, SymbolKindFunc, fn.Name)
+			}
+
+			if _, ok := removedType[owner]; ok {
+				return false
+			}
+
+			return keep(fn, SymbolKindMethod, owner+"."+fn.Name)
+		})
+	}
+
+	pkg.StructMethods = filterMethods(pkg.StructMethods)
+	for _, file := range pkg.Files {
+		file.StructMethods = filterMethods(file.StructMethods)
+	}
+
+	return removed
+}
+
+// filterSlice returns the items for which keep returns true, in order.
+func filterSlice[T any](items []T, keep func(T) bool) []T {
+	if len(items) == 0 {
+		return items
+	}
+
+	kept := items[:0:0]
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,128 @@
+package goparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolFilterKeep(t *testing.T) {
+	tests := []struct {
+		name     string
+		includes []string
+		excludes []string
+		kind     SymbolKind
+		symbol   string
+		expect   bool
+	}{
+		{"no rules", nil, nil, SymbolKindStruct, "Item", true},
+		{"exclude regex", nil, []string{"Mock"}, SymbolKindStruct, "ItemMock", false},
+		{"exclude glob", nil, []string{"glb:*Mock*"}, SymbolKindInterface, "MockStore", false},
+		{"exclude glob no match", nil, []string{"glb:*Mock*"}, SymbolKindStruct, "Item", true},
+		{"exclude qualified glob", nil, []string{"glb:example.com/shop/**.Item"}, SymbolKindStruct, "Item", false},
+		{"exclude kind", nil, []string{"func=glb:New*Option"}, SymbolKindFunc, "NewSizeOption", false},
+		{"exclude other kind", nil, []string{"func=glb:New*Option"}, SymbolKindTypedef, "NewSizeOption", true},
+		{"exclude all of kind", nil, []string{"const="}, SymbolKindConst, "Max", false},
+		{"exclude kinds", nil, []string{"const,var="}, SymbolKindVar, "Default", false},
+		{"exclude field", nil, []string{"field=glb:*.Secret"}, SymbolKindField, "Item.Secret", false},
+		{"exclude method", nil, []string{"method=^Item\\.Reset$"}, SymbolKindMethod, "Item.Reset", false},
+		{"include match", []string{"glb:Item*"}, nil, SymbolKindStruct, "ItemList", true},
+		{"include no match", []string{"glb:Item*"}, nil, SymbolKindStruct, "Order", false},
+		{"include not methods", []string{"glb:Item*"}, nil, SymbolKindMethod, "Order.Total", true},
+		{"include other kind", []string{"func=glb:New*"}, nil, SymbolKindStruct, "Order", true},
+		{"include then exclude", []string{"glb:Item*"}, []string{"ItemMock"}, SymbolKindStruct, "ItemMock", false},
+		{"unknown kind is pattern", nil, []string{"a=b"}, SymbolKindStruct, "a=b", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := NewSymbolFilter(tc.includes, tc.excludes)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expect, filter.Keep(tc.kind, "example.com/shop/model."+tc.symbol, tc.symbol))
+		})
+	}
+}
+
+func TestSymbolFilterInvalid(t *testing.T) {
+	_, err := NewSymbolFilter(nil, []string{"struct=[a-"})
+	assert.Error(t, err)
+}
+
+func TestSymbolFilterApply(t *testing.T) {
+	code := `package model
+
+// Item is an item.
+type Item struct {
+	Name   string
+	Secret string
+}
+
+// Reset resets the item.
+func (i *Item) Reset() {}
+
+// ItemMock is a mock.
+type ItemMock struct{}
+
+// Touch touches the mock.
+func (m *ItemMock) Touch() {}
+
+// NewSizeOption creates an option.
+func NewSizeOption() {}
+
+// NewItem creates an item.
+func NewItem() *Item { return nil }
+
+// Store stores items.
+type Store interface {
+	Get() Item
+	Debug()
+}
+
+const Max = 10
+
+var Default = Item{}
+`
+
+	file, err := ParseInlineFile(nil, "model.go", code)
+	require.NoError(t, err)
+
+	pkg := aggregatePackage(nil, ".", []*GoFile{file})
+	pkg.FqPackage = "example.com/shop/model"
+
+	filter, err := NewSymbolFilter(nil, []string{
+		"glb:*Mock*",
+		"func=glb:New*Option",
+		"const=",
+		"field=Secret",
+		"method=Debug$",
+	})
+	require.NoError(t, err)
+
+	removed := filter.Apply(pkg)
+	assert.ElementsMatch(t, []string{
+		"example.com/shop/model.ItemMock",
+		"example.com/shop/model.NewSizeOption",
+		"example.com/shop/model.Max",
+		"example.com/shop/model.Item.Secret",
+		"example.com/shop/model.Store.Debug",
+	}, removed)
+
+	for _, f := range []*GoFile{&pkg.GoFile, pkg.Files[0]} {
+		require.Len(t, f.Structs, 1)
+		assert.Equal(t, "Item", f.Structs[0].Name)
+		require.Len(t, f.Structs[0].Fields, 1)
+		assert.Equal(t, "Name", f.Structs[0].Fields[0].Name)
+		require.Len(t, f.Interfaces, 1)
+		require.Len(t, f.Interfaces[0].Methods, 1)
+		assert.Equal(t, "Get", f.Interfaces[0].Methods[0].Name)
+		assert.Empty(t, f.ConstAssignments)
+		assert.Len(t, f.VarAssignments, 1)
+
+		var methods []string
+		for _, m := range f.StructMethods {
+			methods = append(methods, m.Name)
+		}
+		assert.Equal(t, []string{"Reset", "NewItem"}, methods)
+	}
+}
@@ -0,0 +1,494 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GoTestKind is the kind of a test function.
+type GoTestKind string
+
+const (
+	// GoTestKindTest is a TestXxx(t *testing.T) function.
+	GoTestKindTest GoTestKind = "test"
+	// GoTestKindBenchmark is a BenchmarkXxx(b *testing.B) function.
+	GoTestKindBenchmark GoTestKind = "benchmark"
+	// GoTestKindFuzz is a FuzzXxx(f *testing.F) function.
+	GoTestKindFuzz GoTestKind = "fuzz"
+)
+
+// GoTest is a test, benchmark or fuzz target in a _test.go file.
+type GoTest struct {
+	// Kind is the kind of test.
+	Kind GoTestKind
+	// Function is the test function.
+	Function *GoStructMethod
+	// Cases are the names of the cases in the test tables, i.e. the name field of a
+	// []struct{name string ...} literal or the keys of a map[string]struct{...} literal, and
+	// the literal names of the sub tests started with Run.
+	Cases []string
+	// Exercises are the names, sorted, of the production symbols of the package referenced in
+	// the test. Methods are prefixed with the receiver type e.g. Item.Reset.
+	Exercises []string
+}
+
+// Name returns the name of the test function.
+func (t *GoTest) Name() string {
+	return t.Function.Name
+}
+
+// GoTestSuite are the tests, benchmarks and fuzz targets of a package.
+type GoTestSuite struct {
+	// Package is the production package the suite tests.
+	Package *GoPackage
+	// Files are the parsed _test.go files of the package directory.
+	Files []*GoFile
+	// Tests are the tests, benchmarks and fuzz targets in the order they are declared.
+	Tests []*GoTest
+}
+
+// OfKind returns the tests of the kind.
+func (s *GoTestSuite) OfKind(kind GoTestKind) []*GoTest {
+	var tests []*GoTest
+	for _, t := range s.Tests {
+		if t.Kind == kind {
+			tests = append(tests, t)
+		}
+	}
+
+	return tests
+}
+
+// testKinds are the kinds of tests, by name prefix, with the type of their single parameter.
+var testKinds = []struct {
+	prefix string
+	param  string
+	kind   GoTestKind
+}{
+	{"Test", "*testing.T", GoTestKindTest},
+	{"Benchmark", "*testing.B", GoTestKindBenchmark},
+	{"Fuzz", "*testing.F", GoTestKindFuzz},
+}
+
+// TestKindOf returns the kind of the function if it is a test, benchmark or fuzz target as
+// recognised by go test.
+func TestKindOf(fn *GoStructMethod) (GoTestKind, bool) {
+	if fn == nil || len(fn.Receivers) > 0 || len(fn.TypeParams) > 0 ||
+		len(fn.Params) != 1 || len(fn.Results) > 0 {
+		return "", false
+	}
+
+	for _, k := range testKinds {
+		if !strings.HasPrefix(fn.Name, k.prefix) || fn.Params[0].Type != k.param {
+			continue
+		}
+
+		// As go test, TestXxx must not continue with a lower case letter after the prefix.
+		rest := fn.Name[len(k.prefix):]
+		if r, _ := utf8.DecodeRuneInString(rest); rest != "" && unicode.IsLower(r) {
+			return "", false
+		}
+
+		return k.kind, true
+	}
+
+	return "", false
+}
+
+// ParseTestSuite parses the _test.go files in the directory of the package and returns its
+// tests, benchmarks and fuzz targets. The package is the production package, any test files
+// in it are not considered production symbols.
+func ParseTestSuite(config ParseConfig, pkg *GoPackage) (*GoTestSuite, error) {
+	suite := &GoTestSuite{Package: pkg}
+	if pkg == nil || pkg.FilePath == "" {
+		return suite, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(pkg.FilePath, "*_test.go"))
+	if err != nil || len(paths) == 0 {
+		return suite, err
+	}
+
+	sort.Strings(paths)
+	debugf(config.Debug, "ParseTestSuite: parsing %d test file(s) in %s", len(paths), pkg.FilePath)
+
+	config.Test = true
+	suite.Files, err = parseFiles(config, paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := productionSymbols(pkg)
+	tables := tableStructs(pkg, suite.Files)
+
+	for _, file := range suite.Files {
+		for _, fn := range file.StructMethods {
+			kind, ok := TestKindOf(fn)
+			if !ok {
+				continue
+			}
+
+			test := &GoTest{Kind: kind, Function: fn}
+			if body := parseFuncDecl(fn.FullDecl); body != nil {
+				test.Cases = testCases(body, tables)
+				test.Exercises = exercisedSymbols(body, file, pkg, symbols)
+			}
+
+			suite.Tests = append(suite.Tests, test)
+		}
+	}
+
+	debugf(config.Debug, "ParseTestSuite: found %d test(s) in %s", len(suite.Tests), pkg.FilePath)
+	return suite, nil
+}
+
+// isTestFile returns true if the file is a _test.go file.
+func isTestFile(file *GoFile) bool {
+	return file != nil && strings.HasSuffix(file.FilePath, "_test.go")
+}
+
+// symbolTable are the symbols declared in the production files of a package.
+type symbolTable struct {
+	// names are the top level types, functions, constants and variables.
+	names map[string]struct{}
+	// methods are the receiver types, per method name.
+	methods map[string][]string
+}
+
+// productionSymbols returns the symbols declared in the production files of the package.
+func productionSymbols(pkg *GoPackage) *symbolTable {
+	symbols := &symbolTable{names: map[string]struct{}{}, methods: map[string][]string{}}
+	add := func(file *GoFile, name string) {
+		if !isTestFile(file) && name != "" && name != "_" {
+			symbols.names[name] = struct{}{}
+		}
+	}
+
+	for _, s := range pkg.Structs {
+		add(s.File, s.Name)
+	}
+	for _, i := range pkg.Interfaces {
+		add(i.File, i.Name)
+	}
+	for _, c := range pkg.CustomTypes {
+		add(c.File, c.Name)
+	}
+	for _, f := range pkg.CustomFuncs {
+		add(f.File, f.Name)
+	}
+	for _, a := range pkg.ConstAssignments {
+		add(a.File, a.Name)
+	}
+	for _, a := range pkg.VarAssignments {
+		add(a.File, a.Name)
+	}
+	for _, m := range pkg.StructMethods {
+		if isTestFile(m.File) {
+			continue
+		}
+
+		owner := m.ReceiverName()
+		if owner == "" {
+			add(m.File, m.Name)
+			continue
+		}
+
+		symbols.methods[m.Name] = append(symbols.methods[m.Name], owner)
+	}
+	for _, i := range pkg.Interfaces {
+		if isTestFile(i.File) {
+			continue
+		}
+
+		for _, m := range i.Methods {
+			symbols.methods[m.Name] = append(symbols.methods[m.Name], i.Name)
+		}
+	}
+
+	return symbols
+}
+
+// tableStructs returns the fields, in order, of the named structs of the package and its test
+// files that may be used as test table elements.
+func tableStructs(pkg *GoPackage, files []*GoFile) map[string][]string {
+	tables := map[string][]string{}
+	add := func(structs []*GoStruct) {
+		for _, s := range structs {
+			fields := make([]string, 0, len(s.Fields))
+			for _, f := range s.Fields {
+				if f.Type == "string" {
+					fields = append(fields, f.Name)
+				} else {
+					fields = append(fields, "")
+				}
+			}
+
+			tables[s.Name] = fields
+		}
+	}
+
+	add(pkg.Structs)
+	for _, file := range files {
+		add(file.Structs)
+	}
+
+	return tables
+}
+
+// parseFuncDecl parses the source of a function declaration.
+func parseFuncDecl(src string) *ast.FuncDecl {
+	if strings.TrimSpace(src) == "" {
+		return nil
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "", "package p\n\n"+src, parser.SkipObjectResolution)
+	if err != nil || len(file.Decls) == 0 {
+		return nil
+	}
+
+	fn, _ := file.Decls[0].(*ast.FuncDecl)
+	return fn
+}
+
+// caseNameFields are the names, lower case and by priority, of the string field that names a
+// case in a test table.
+var caseNameFields = []string{"name", "desc", "description", "title", "scenario"}
+
+// caseNameField returns the index of the field that names the cases, or -1 if none. The fields
+// are the names of the string fields of the table element, empty for other fields.
+func caseNameField(fields []string) int {
+	for _, candidate := range caseNameFields {
+		for i, f := range fields {
+			if strings.ToLower(f) == candidate {
+				return i
+			}
+		}
+	}
+	for i, f := range fields {
+		if strings.HasSuffix(strings.ToLower(f), "name") {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// structFields returns the names of the string fields, in order, of an inline struct or a named
+// table struct, empty for other fields.
+func structFields(expr ast.Expr, tables map[string][]string) []string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return structFields(e.X, tables)
+	case *ast.Ident:
+		return tables[e.Name]
+	case *ast.StructType:
+		var fields []string
+		for _, field := range e.Fields.List {
+			ident, _ := field.Type.(*ast.Ident)
+			str := ident != nil && ident.Name == "string"
+			for _, name := range field.Names {
+				if str {
+					fields = append(fields, name.Name)
+				} else {
+					fields = append(fields, "")
+				}
+			}
+			if len(field.Names) == 0 {
+				fields = append(fields, "")
+			}
+		}
+
+		return fields
+	}
+
+	return nil
+}
+
+// stringLiteral returns the value of a string literal.
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	s, err := strconv.Unquote(lit.Value)
+	return s, err == nil
+}
+
+// testCases returns the case names of the test tables, and the literal names of the sub tests,
+// in the order they appear in the test function.
+func testCases(fn *ast.FuncDecl, tables map[string][]string) []string {
+	// Table types may also be declared in the test function.
+	scoped := map[string][]string{}
+	for name, fields := range tables {
+		scoped[name] = fields
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			if _, ok := spec.Type.(*ast.StructType); ok {
+				scoped[spec.Name.Name] = structFields(spec.Type, tables)
+			}
+		}
+
+		return true
+	})
+
+	tables = scoped
+
+	var cases []string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CompositeLit:
+			cases = append(cases, tableCases(n, tables)...)
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Run" || len(n.Args) != 2 {
+				break
+			}
+			if name, ok := stringLiteral(n.Args[0]); ok {
+				cases = append(cases, name)
+			}
+		}
+
+		return true
+	})
+
+	return cases
+}
+
+// tableCases returns the case names of a []struct{name string ...} or map[string]struct{...}
+// literal, nil if the literal is not a test table.
+func tableCases(lit *ast.CompositeLit, tables map[string][]string) []string {
+	var cases []string
+	switch typ := lit.Type.(type) {
+	case *ast.MapType:
+		if key, ok := typ.Key.(*ast.Ident); !ok || key.Name != "string" || structFields(typ.Value, tables) == nil {
+			return nil
+		}
+
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if name, ok := stringLiteral(kv.Key); ok {
+					cases = append(cases, name)
+				}
+			}
+		}
+	case *ast.ArrayType:
+		fields := structFields(typ.Elt, tables)
+		index := caseNameField(fields)
+		if index < 0 {
+			return nil
+		}
+
+		for _, elt := range lit.Elts {
+			if unary, ok := elt.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				elt = unary.X
+			}
+
+			row, ok := elt.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+
+			for i, value := range row.Elts {
+				if kv, ok := value.(*ast.KeyValueExpr); ok {
+					key, ok := kv.Key.(*ast.Ident)
+					if !ok || key.Name != fields[index] {
+						continue
+					}
+
+					value = kv.Value
+				} else if i != index {
+					continue
+				}
+
+				if name, ok := stringLiteral(value); ok {
+					cases = append(cases, name)
+				}
+			}
+		}
+	}
+
+	return cases
+}
+
+// exercisedSymbols returns the production symbols referenced in the test function. In package
+// tests these are the unqualified identifiers, in external _test package tests the identifiers
+// qualified with the package under test. Methods are matched by name when only a single type
+// declares a method with that name.
+func exercisedSymbols(fn *ast.FuncDecl, file *GoFile, pkg *GoPackage, symbols *symbolTable) []string {
+	internal := file.Package == pkg.Package
+
+	aliases := map[string]struct{}{}
+	for _, imp := range file.Imports {
+		if pkg.FqPackage != "" && strings.Trim(imp.Path, "\"") == pkg.FqPackage {
+			aliases[imp.Prefix()] = struct{}{}
+		}
+	}
+
+	found := map[string]struct{}{}
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Ident:
+			if _, ok := symbols.names[n.Name]; ok && internal {
+				found[n.Name] = struct{}{}
+			}
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok {
+				if _, ok := aliases[x.Name]; ok {
+					if _, ok := symbols.names[n.Sel.Name]; ok {
+						found[n.Sel.Name] = struct{}{}
+					}
+
+					return false
+				}
+			}
+			if owners := symbols.methods[n.Sel.Name]; len(owners) == 1 {
+				found[owners[0]+"."+n.Sel.Name] = struct{}{}
+			}
+
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.CompositeLit:
+			if _, ok := n.Type.(*ast.MapType); ok {
+				break
+			}
+
+			// The keys of struct literals are field names, not references.
+			if n.Type != nil {
+				ast.Inspect(n.Type, visit)
+			}
+			for _, elt := range n.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if _, ok := kv.Key.(*ast.Ident); ok {
+						elt = kv.Value
+					}
+				}
+
+				ast.Inspect(elt, visit)
+			}
+
+			return false
+		}
+
+		return true
+	}
+
+	ast.Inspect(fn.Body, visit)
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
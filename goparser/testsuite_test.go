@@ -0,0 +1,195 @@
+package goparser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTestSuite(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.21\n",
+		"shop/shop.go": `package shop
+
+// MaxItems is the max.
+const MaxItems = 10
+
+// Item is an item.
+type Item struct {
+	Name string
+}
+
+// NewItem creates an item.
+func NewItem(name string) *Item { return &Item{Name: name} }
+
+// Reset resets the item.
+func (i *Item) Reset() { i.Name = "" }
+
+// Parse parses an item.
+func Parse(s string) (*Item, error) { return NewItem(s), nil }
+`,
+		"shop/shop_test.go": `package shop
+
+import "testing"
+
+type parseCase struct {
+	input string
+	title string
+	want  string
+}
+
+// TestNewItem verifies the constructor.
+func TestNewItem(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{"plain", "apple"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			item := NewItem(tc.input)
+			item.Reset()
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for name, tc := range map[string]struct{ in string }{
+		"blank": {in: ""},
+		"word":  {in: "x"},
+	} {
+		t.Run(name, func(t *testing.T) { Parse(tc.in) })
+	}
+
+	cases := []*parseCase{{input: "a", title: "named"}}
+	_ = cases
+	t.Run("limits", func(t *testing.T) { _ = MaxItems })
+}
+
+func Testhelper(t *testing.T) {}
+
+func TestMain(m *testing.M) {}
+
+// BenchmarkParse measures parsing.
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Parse("apple")
+	}
+}
+`,
+		"shop/fuzz_test.go": `package shop_test
+
+import (
+	"testing"
+
+	market "example.com/shop/shop"
+)
+
+// FuzzParse fuzzes the parser.
+func FuzzParse(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {
+		item, _ := market.Parse(s)
+		_ = market.Item{Name: item.Name}
+	})
+}
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	mod, err := NewModule(filepath.Join(tmpDir, "go.mod"))
+	require.NoError(t, err)
+
+	var pkg *GoPackage
+	require.NoError(t, ParseSinglePackageWalker(ParseConfig{Module: mod}, func(p *GoPackage) error {
+		pkg = p
+		return nil
+	}, filepath.Join(tmpDir, "shop")))
+	require.NotNil(t, pkg)
+
+	suite, err := ParseTestSuite(ParseConfig{Module: mod}, pkg)
+	require.NoError(t, err)
+
+	type result struct {
+		Kind      GoTestKind
+		Doc       string
+		Cases     []string
+		Exercises []string
+	}
+
+	tests := map[string]result{}
+	for _, test := range suite.Tests {
+		tests[test.Name()] = result{test.Kind, test.Function.Doc, test.Cases, test.Exercises}
+	}
+
+	assert.Equal(t, map[string]result{
+		"TestNewItem": {
+			Kind:      GoTestKindTest,
+			Doc:       "TestNewItem verifies the constructor.",
+			Cases:     []string{"empty", "plain"},
+			Exercises: []string{"Item.Reset", "NewItem"},
+		},
+		"TestParse": {
+			Kind:      GoTestKindTest,
+			Cases:     []string{"blank", "word", "named", "limits"},
+			Exercises: []string{"MaxItems", "Parse"},
+		},
+		"BenchmarkParse": {
+			Kind:      GoTestKindBenchmark,
+			Doc:       "BenchmarkParse measures parsing.",
+			Exercises: []string{"Parse"},
+		},
+		"FuzzParse": {
+			Kind:      GoTestKindFuzz,
+			Doc:       "FuzzParse fuzzes the parser.",
+			Exercises: []string{"Item", "Parse"},
+		},
+	}, tests)
+
+	assert.Len(t, suite.OfKind(GoTestKindTest), 2)
+	assert.Len(t, suite.Files, 2)
+}
+
+func TestTestKindOf(t *testing.T) {
+	file, err := ParseInlineFile(nil, "x_test.go", `package x
+
+import "testing"
+
+func Test(t *testing.T) {}
+func TestX(t *testing.T) {}
+func Testx(t *testing.T) {}
+func Test_x(t *testing.T) {}
+func BenchmarkX(b *testing.B) {}
+func BenchmarkY(t *testing.T) {}
+func FuzzX(f *testing.F) {}
+func TestMain(m *testing.M) {}
+func TestHelper(t *testing.T, n int) {}
+`)
+	require.NoError(t, err)
+
+	kinds := map[string]GoTestKind{}
+	for _, fn := range file.StructMethods {
+		if kind, ok := TestKindOf(fn); ok {
+			kinds[fn.Name] = kind
+		}
+	}
+
+	assert.Equal(t, map[string]GoTestKind{
+		"Test":       GoTestKindTest,
+		"TestX":      GoTestKindTest,
+		"Test_x":     GoTestKindTest,
+		"BenchmarkX": GoTestKindBenchmark,
+		"FuzzX":      GoTestKindFuzz,
+	}, kinds)
+}
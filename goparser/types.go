@@ -1,6 +1,135 @@
 package goparser
 
-import "strings"
+import (
+	"go/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GoDirective is a compiler or tool directive comment such as //go:embed or //nolint.
+type GoDirective struct {
+	File *GoFile
+	// Name is the directive name e.g. go:generate, go:embed or nolint.
+	Name string
+	// Args is the text following the name e.g. the command of a go:generate directive
+	// or the linters of a nolint:errcheck,gosec directive.
+	Args string
+	// Text is the complete comment e.g. //go:generate stringer -type=Pill
+	Text string
+}
+
+// FileName returns the base name of the file where the directive resides.
+func (d *GoDirective) FileName() string {
+	if d.File == nil {
+		return ""
+	}
+
+	return filepath.Base(d.File.FilePath)
+}
+
+// GoDirectives is a list of directives in the order they appear in source.
+type GoDirectives []*GoDirective
+
+// Named returns the directives with name e.g. go:generate.
+func (d GoDirectives) Named(name string) GoDirectives {
+	var list GoDirectives
+	for _, directive := range d {
+		if directive.Name == name {
+			list = append(list, directive)
+		}
+	}
+
+	return list
+}
+
+// DirectiveNamespace is the namespace of the goasciidoc directives that control the
+// rendering of a declaration e.g. //goasciidoc:ignore.
+const DirectiveNamespace = "goasciidoc:"
+
+// Ignored returns true if a //goasciidoc:ignore directive hides the declaration from the
+// documentation.
+func (d GoDirectives) Ignored() bool {
+	return len(d.Named(DirectiveNamespace+"ignore")) > 0
+}
+
+// Group returns the group of a //goasciidoc:group "Name" directive, or an empty string
+// if the declaration is not grouped. The name may be quoted or not.
+func (d GoDirectives) Group() string {
+	list := d.Named(DirectiveNamespace + "group")
+	if len(list) == 0 {
+		return ""
+	}
+
+	group := list[len(list)-1].Args
+	if unquoted, err := strconv.Unquote(group); err == nil {
+		return strings.TrimSpace(unquoted)
+	}
+
+	return group
+}
+
+// Order returns the order of a //goasciidoc:order N directive and true, or false if the
+// declaration has no, or an invalid, order.
+func (d GoDirectives) Order() (int, bool) {
+	list := d.Named(DirectiveNamespace + "order")
+	if len(list) == 0 {
+		return 0, false
+	}
+
+	order, err := strconv.Atoi(list[len(list)-1].Args)
+	if err != nil {
+		return 0, false
+	}
+
+	return order, true
+}
+
+// Code returns the setting of a //goasciidoc:code directive, that renders the implementation
+// of a function or method, and true. The setting is false for //goasciidoc:code off (or
+// false) and true otherwise. It returns false, false when there is no, or an invalid, directive.
+func (d GoDirectives) Code() (bool, bool) {
+	list := d.Named(DirectiveNamespace + "code")
+	if len(list) == 0 {
+		return false, false
+	}
+
+	switch strings.ToLower(list[len(list)-1].Args) {
+	case "", "on", "true":
+		return true, true
+	case "off", "false":
+		return false, true
+	}
+
+	return false, false
+}
+
+// NoLint returns true if a //goasciidoc:nolint directive suppresses the documentation lint
+// rule, e.g. //goasciidoc:nolint missing-doc,doc-prefix. A directive without rules suppresses
+// all rules. Text after // in the directive is a comment e.g. the reason.
+func (d GoDirectives) NoLint(rule string) bool {
+	for _, directive := range d.Named(DirectiveNamespace + "nolint") {
+		args := directive.Args
+		if i := strings.Index(args, "//"); i != -1 {
+			args = args[:i]
+		}
+
+		rules := strings.FieldsFunc(args, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(rules) == 0 {
+			return true
+		}
+
+		for _, r := range rules {
+			if r == rule {
+				return true
+			}
+		}
+	}
+
+	return false
+}
 
 // GoAssignment represents a single var assignment e.g. var pelle = 10
 type GoAssignment struct {
@@ -12,6 +141,55 @@ type GoAssignment struct {
 	Decl     string
 	FullDecl string
 	Exported bool
+	// Type is the declared type, empty for untyped constants and when it is unknown.
+	Type string
+	// Value is the value of a constant, strings without quotes, empty for variables and when
+	// it is unknown.
+	Value string
+	// Line is the line, starting from 1, of the declaration in its file.
+	Line int
+	// Directives are the directives, e.g. //go:embed, in the doc comment.
+	Directives GoDirectives
+	// Variants are all definitions of the symbol, sorted by build constraint, when it
+	// is defined in several files with different build constraints (otherwise nil).
+	Variants []*GoAssignment
+}
+
+// EmbedPatterns returns the file patterns of the //go:embed directives, if any.
+func (a *GoAssignment) EmbedPatterns() []string {
+	var patterns []string
+	for _, directive := range a.Directives.Named("go:embed") {
+		patterns = append(patterns, splitEmbedPatterns(directive.Args)...)
+	}
+
+	return patterns
+}
+
+// splitEmbedPatterns splits space separated patterns where a pattern may be a
+// double quoted or back quoted string.
+func splitEmbedPatterns(args string) []string {
+	var patterns []string
+	for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+		end := strings.IndexAny(args, " \t")
+		if args[0] == '"' || args[0] == '`' {
+			if i := strings.IndexByte(args[1:], args[0]); i >= 0 {
+				end = i + 2
+			}
+		}
+		if end < 0 {
+			end = len(args)
+		}
+
+		pattern := args[:end]
+		if unquoted, err := strconv.Unquote(pattern); err == nil {
+			pattern = unquoted
+		}
+
+		patterns = append(patterns, pattern)
+		args = args[end:]
+	}
+
+	return patterns
 }
 
 // GoCustomType is a custom type definition
@@ -20,9 +198,17 @@ type GoCustomType struct {
 	Name       string
 	Doc        string
 	Type       string
+	TypeInfo   *GoType
 	Decl       string
 	Exported   bool
 	TypeParams []*GoType
+	// Line is the line, starting from 1, of the declaration in its file.
+	Line int
+	// Directives are the directives, e.g. //go:generate, in the doc comment.
+	Directives GoDirectives
+	// Variants are all definitions of the symbol, sorted by build constraint, when it
+	// is defined in several files with different build constraints (otherwise nil).
+	Variants []*GoCustomType
 }
 
 // GoInterface specifies a interface definition
@@ -37,6 +223,13 @@ type GoInterface struct {
 	TypeParams  []*GoType
 	TypeSet     []*GoType
 	TypeSetDecl []string
+	// Line is the line, starting from 1, of the declaration in its file.
+	Line int
+	// Directives are the directives, e.g. //go:generate, in the doc comment.
+	Directives GoDirectives
+	// Variants are all definitions of the symbol, sorted by build constraint, when it
+	// is defined in several files with different build constraints (otherwise nil).
+	Variants []*GoInterface
 }
 
 // GoType represents a go type such as a array, map, custom type etc.
@@ -48,6 +241,9 @@ type GoType struct {
 	Exported   bool
 	Inner      []*GoType
 	Kind       TypeKind
+
+	// typ is the go/types representation when type information was available.
+	typ types.Type
 }
 
 // GoStruct represents a struct
@@ -60,6 +256,13 @@ type GoStruct struct {
 	Exported   bool
 	Fields     []*GoField
 	TypeParams []*GoType
+	// Line is the line, starting from 1, of the declaration in its file.
+	Line int
+	// Directives are the directives, e.g. //go:generate, in the doc comment.
+	Directives GoDirectives
+	// Variants are all definitions of the symbol, sorted by build constraint, when it
+	// is defined in several files with different build constraints (otherwise nil).
+	Variants []*GoStruct
 }
 
 // HasJSONTag returns true if any field in the struct has a json tag
@@ -72,6 +275,16 @@ func (s *GoStruct) HasYAMLTag() bool {
 	return s.hasTag("yaml")
 }
 
+// HasXMLTag returns true if any field in the struct has a xml tag
+func (s *GoStruct) HasXMLTag() bool {
+	return s.hasTag("xml")
+}
+
+// HasTOMLTag returns true if any field in the struct has a toml tag
+func (s *GoStruct) HasTOMLTag() bool {
+	return s.hasTag("toml")
+}
+
 // hasTag checks if any field (including nested structs) has the specified tag
 func (s *GoStruct) hasTag(tagName string) bool {
 	for _, field := range s.Fields {
@@ -86,109 +299,30 @@ func (s *GoStruct) hasTag(tagName string) bool {
 	return false
 }
 
-// ToJSON generates an example JSON representation of the struct
+// ToJSON generates an example JSON representation of the struct. Field types
+// are followed into named structs, also in other packages, and embedded structs
+// are flattened as done by encoding/json.
 func (s *GoStruct) ToJSON() string {
-	return s.toJSON(0)
-}
-
-// ToYAML generates an example YAML representation of the struct
-func (s *GoStruct) ToYAML() string {
-	return s.toYAML(0)
-}
-
-// toJSON is the internal recursive implementation for JSON generation
-func (s *GoStruct) toJSON(indent int) string {
-	if s == nil || len(s.Fields) == 0 {
+	if s == nil {
 		return "{}"
 	}
 
-	result := "{\n"
-	indentStr := makeIndent(indent + 1)
-	fieldCount := 0
-
-	for _, field := range s.Fields {
-		if !field.Exported {
-			continue
-		}
-
-		// Get the JSON field name
-		jsonTag := ""
-		if field.Tag != nil {
-			jsonTag = field.Tag.Get("json")
-		}
-
-		// Skip fields with json:"-"
-		if jsonTag == "-" {
-			continue
-		}
-
-		fieldName := getJSONFieldName(field, jsonTag)
-		if fieldName == "" {
-			continue
-		}
-
-		if fieldCount > 0 {
-			result += ",\n"
-		}
-
-		result += indentStr + `"` + fieldName + `": `
-		result += generateJSONValue(field, indent+1)
-		fieldCount++
-	}
-
-	if fieldCount > 0 {
-		result += "\n"
-	}
-	result += makeIndent(indent) + "}"
-	return result
+	return s.example(jsonRules).json(0)
 }
 
-// toYAML is the internal recursive implementation for YAML generation
-func (s *GoStruct) toYAML(indent int) string {
-	if s == nil || len(s.Fields) == 0 {
+// ToYAML generates an example YAML representation of the struct. Field types
+// are followed into named structs, also in other packages.
+func (s *GoStruct) ToYAML() string {
+	if s == nil {
 		return "{}"
 	}
 
-	result := ""
-	indentStr := makeIndent(indent)
-	firstField := true
-
-	for _, field := range s.Fields {
-		if !field.Exported {
-			continue
-		}
-
-		// Get the YAML field name
-		yamlTag := ""
-		if field.Tag != nil {
-			yamlTag = field.Tag.Get("yaml")
-		}
-
-		// Skip fields with yaml:"-"
-		if yamlTag == "-" {
-			continue
-		}
-
-		fieldName := getYAMLFieldName(field, yamlTag)
-		if fieldName == "" {
-			continue
-		}
-
-		if !firstField {
-			result += "\n"
-		}
-		firstField = false
-
-		yamlValue := generateYAMLValue(field, indent)
-		result += indentStr + fieldName + ":"
-		// Don't add space before newline for nested structures
-		if !strings.HasPrefix(yamlValue, "\n") {
-			result += " "
-		}
-		result += yamlValue
+	node := s.example(yamlRules)
+	if len(node.keys) == 0 {
+		return "{}"
 	}
 
-	return result
+	return node.yamlFields(0)
 }
 
 // GoField is a field in a file or struct
@@ -203,6 +337,13 @@ type GoField struct {
 	Tag             *GoTag
 	AnonymousStruct *GoStruct
 	TypeInfo        *GoType
+	// Example is the JSON encoded example value from an example tag or an
+	// "Example: <value>" line in Doc, empty when not specified.
+	Example string
+	// Default is the JSON encoded value of a default tag, empty when not specified.
+	Default string
+	// Directives are the directives, e.g. //goasciidoc:ignore, in the doc comment.
+	Directives GoDirectives
 }
 
 // TypeKind represents the general classification of a Go type expression.
@@ -236,86 +377,6 @@ func makeIndent(level int) string {
 	return strings.Repeat("  ", level)
 }
 
-func getJSONFieldName(field *GoField, jsonTag string) string {
-	if jsonTag != "" {
-		// Parse json tag (could be "name,omitempty" or just "name")
-		parts := strings.Split(jsonTag, ",")
-		return parts[0]
-	}
-	return field.Name
-}
-
-func getYAMLFieldName(field *GoField, yamlTag string) string {
-	if yamlTag != "" {
-		// Parse yaml tag (could be "name,omitempty" or just "name")
-		parts := strings.Split(yamlTag, ",")
-		return parts[0]
-	}
-	return field.Name
-}
-
-func generateJSONValue(field *GoField, indent int) string {
-	// Handle anonymous structs
-	if field.AnonymousStruct != nil {
-		return field.AnonymousStruct.toJSON(indent)
-	}
-
-	// Generate example values based on type
-	typeStr := strings.TrimSpace(field.Type)
-
-	// Handle pointers
-	if after, ok := strings.CutPrefix(typeStr, "*"); ok {
-		typeStr = after
-	}
-
-	// Handle slices and arrays
-	if strings.HasPrefix(typeStr, "[]") {
-		elemType := strings.TrimPrefix(typeStr, "[]")
-		elemValue := generateExampleValueForType(elemType)
-		return "[" + elemValue + "]"
-	}
-
-	// Handle maps
-	if strings.HasPrefix(typeStr, "map[") {
-		return `{}`
-	}
-
-	return generateExampleValueForType(typeStr)
-}
-
-func generateYAMLValue(field *GoField, indent int) string {
-	// Handle anonymous structs
-	if field.AnonymousStruct != nil {
-		yamlContent := field.AnonymousStruct.toYAML(indent + 1)
-		if yamlContent == "" || yamlContent == "{}" {
-			return "{}"
-		}
-		return "\n" + yamlContent
-	}
-
-	// Generate example values based on type
-	typeStr := strings.TrimSpace(field.Type)
-
-	// Handle pointers
-	if after, ok := strings.CutPrefix(typeStr, "*"); ok {
-		typeStr = after
-	}
-
-	// Handle slices and arrays
-	if strings.HasPrefix(typeStr, "[]") {
-		elemType := strings.TrimPrefix(typeStr, "[]")
-		elemValue := generateExampleValueForType(elemType)
-		return "\n" + makeIndent(indent+1) + "- " + elemValue
-	}
-
-	// Handle maps
-	if strings.HasPrefix(typeStr, "map[") {
-		return "{}"
-	}
-
-	return generateExampleValueForType(typeStr)
-}
-
 func generateExampleValueForType(typeStr string) string {
 	typeStr = strings.TrimSpace(typeStr)
 
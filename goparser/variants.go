@@ -0,0 +1,216 @@
+package goparser
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// knownOS and knownArch are the GOOS and GOARCH values recognized as file name
+// suffixes e.g. _linux.go or _windows_amd64.go.
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+		"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+		"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+		"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+		"sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// fileConstraint returns the build constraint of a file as a //go:build expression, e.g.
+// linux && amd64, by combining the GOOS and GOARCH of the file name with the //go:build
+// (or // +build) lines. It returns an empty string when the file builds everywhere.
+func fileConstraint(path string, file *ast.File) string {
+	var exprs []constraint.Expr
+
+	for _, tag := range fileNameTags(path) {
+		exprs = append(exprs, &constraint.TagExpr{Tag: tag})
+	}
+
+	if expr := buildLineConstraint(file); expr != nil {
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 0 {
+		return ""
+	}
+
+	expr := exprs[0]
+	for _, x := range exprs[1:] {
+		expr = &constraint.AndExpr{X: expr, Y: x}
+	}
+
+	return expr.String()
+}
+
+// fileNameTags returns the implicit GOOS and GOARCH constraints of a file name using the
+// same rules as go/build, e.g. name_linux_amd64.go yields linux and amd64.
+func fileNameTags(path string) []string {
+	name := strings.TrimSuffix(filepath.Base(path), ".go")
+	name = strings.TrimSuffix(name, "_test")
+
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+
+	parts := strings.Split(name[i:], "_")
+	n := len(parts)
+	if n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]] {
+		return []string{parts[n-2], parts[n-1]}
+	}
+	if n >= 1 && (knownOS[parts[n-1]] || knownArch[parts[n-1]]) {
+		return []string{parts[n-1]}
+	}
+
+	return nil
+}
+
+// buildLineConstraint returns the //go:build expression of the file or, when there is
+// none, the conjunction of its // +build lines.
+func buildLineConstraint(file *ast.File) constraint.Expr {
+	if file == nil {
+		return nil
+	}
+
+	var plus constraint.Expr
+	for _, group := range file.Comments {
+		if group.Pos() > file.Package {
+			break
+		}
+
+		for _, c := range group.List {
+			switch {
+			case constraint.IsGoBuild(c.Text):
+				if expr, err := constraint.Parse(c.Text); err == nil {
+					return expr
+				}
+			case constraint.IsPlusBuild(c.Text):
+				expr, err := constraint.Parse(c.Text)
+				if err != nil {
+					continue
+				}
+				if plus == nil {
+					plus = expr
+				} else {
+					plus = &constraint.AndExpr{X: plus, Y: expr}
+				}
+			}
+		}
+	}
+
+	return plus
+}
+
+// mergeVariants merges symbols that are defined more than once, in files with different
+// build constraints, into a single symbol. The first definition is kept and all
+// definitions, sorted by constraint, are handed to setVariants. Blank identifiers
+// and init functions are never merged.
+func mergeVariants[T any](
+	symbols []T,
+	key func(T) string,
+	file func(T) *GoFile,
+	setVariants func(T, []T),
+) []T {
+	groups := map[string][]T{}
+	for _, symbol := range symbols {
+		k := key(symbol)
+		groups[k] = append(groups[k], symbol)
+	}
+
+	constraintOf := func(symbol T) string {
+		if f := file(symbol); f != nil {
+			return f.Constraint
+		}
+		return ""
+	}
+
+	merged := make([]T, 0, len(symbols))
+	for _, symbol := range symbols {
+		k := key(symbol)
+		group := groups[k]
+		if k == "_" || k == "init" || len(group) < 2 || !hasConstraint(group, constraintOf) {
+			merged = append(merged, symbol)
+			continue
+		}
+
+		if any(group[0]) != any(symbol) {
+			// Only the first definition is kept.
+			continue
+		}
+
+		variants := append([]T{}, group...)
+		sort.SliceStable(variants, func(i, j int) bool {
+			return constraintOf(variants[i]) < constraintOf(variants[j])
+		})
+
+		setVariants(symbol, variants)
+		merged = append(merged, symbol)
+	}
+
+	return merged
+}
+
+func hasConstraint[T any](group []T, constraintOf func(T) string) bool {
+	for _, symbol := range group {
+		if constraintOf(symbol) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mergePackageVariants merges the symbols of the package that have several build
+// constraint variants.
+func mergePackageVariants(pkg *GoPackage) {
+	pkg.Structs = mergeVariants(pkg.Structs,
+		func(s *GoStruct) string { return s.Name },
+		func(s *GoStruct) *GoFile { return s.File },
+		func(s *GoStruct, v []*GoStruct) { s.Variants = v },
+	)
+	pkg.Interfaces = mergeVariants(pkg.Interfaces,
+		func(i *GoInterface) string { return i.Name },
+		func(i *GoInterface) *GoFile { return i.File },
+		func(i *GoInterface, v []*GoInterface) { i.Variants = v },
+	)
+	pkg.CustomTypes = mergeVariants(pkg.CustomTypes,
+		func(c *GoCustomType) string { return c.Name },
+		func(c *GoCustomType) *GoFile { return c.File },
+		func(c *GoCustomType, v []*GoCustomType) { c.Variants = v },
+	)
+	pkg.CustomFuncs = mergeVariants(pkg.CustomFuncs,
+		func(m *GoMethod) string { return m.Name },
+		func(m *GoMethod) *GoFile { return m.File },
+		func(m *GoMethod, v []*GoMethod) { m.Variants = v },
+	)
+	pkg.StructMethods = mergeVariants(pkg.StructMethods,
+		func(m *GoStructMethod) string {
+			if len(m.Receivers) == 0 {
+				return m.Name
+			}
+			return strings.Join(m.Receivers, ",") + "." + m.Name
+		},
+		func(m *GoStructMethod) *GoFile { return m.File },
+		func(m *GoStructMethod, v []*GoStructMethod) { m.Variants = v },
+	)
+	pkg.VarAssignments = mergeVariants(pkg.VarAssignments,
+		func(a *GoAssignment) string { return a.Name },
+		func(a *GoAssignment) *GoFile { return a.File },
+		func(a *GoAssignment, v []*GoAssignment) { a.Variants = v },
+	)
+	pkg.ConstAssignments = mergeVariants(pkg.ConstAssignments,
+		func(a *GoAssignment) string { return a.Name },
+		func(a *GoAssignment) *GoFile { return a.File },
+		func(a *GoAssignment, v []*GoAssignment) { a.Variants = v },
+	)
+}
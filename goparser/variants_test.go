@@ -0,0 +1,138 @@
+package goparser
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileConstraint(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		expected string
+	}{
+		{name: "no constraint", path: "file.go"},
+		{name: "goos suffix", path: "file_linux.go", expected: "linux"},
+		{name: "goarch suffix", path: "file_arm64.go", expected: "arm64"},
+		{name: "goos and goarch suffix", path: "file_windows_amd64.go", expected: "windows && amd64"},
+		{name: "test file suffix", path: "file_darwin_test.go", expected: "darwin"},
+		{name: "only goos is no suffix", path: "linux.go"},
+		{name: "unknown suffix", path: "file_custom.go"},
+		{name: "go build line", path: "file.go", header: "//go:build integration", expected: "integration"},
+		{
+			name:     "go build line and suffix",
+			path:     "file_linux.go",
+			header:   "//go:build cgo || purego",
+			expected: "linux && (cgo || purego)",
+		},
+		{name: "plus build lines", path: "file.go", header: "// +build linux darwin\n// +build cgo", expected: "(linux || darwin) && cgo"},
+		{name: "go build wins", path: "file.go", header: "//go:build dev\n// +build dev,ignored", expected: "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), tt.path, tt.header+"\n\npackage app\n", parser.ParseComments)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fileConstraint(tt.path, file))
+		})
+	}
+}
+
+func TestMergeBuildConstraintVariants(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.24\n",
+		"file.go": `package app
+
+// File is an open file.
+type File struct {
+	handle fileHandle
+}
+
+// Close closes the file.
+func (f *File) Close() error { return closeHandle(f.handle) }
+
+func init() {}
+`,
+		"file_linux.go": `package app
+
+type fileHandle int
+
+// PathSeparator is the OS path separator.
+const PathSeparator = '/'
+
+// Open opens the file using open(2).
+func Open(name string) (*File, error) { return &File{}, nil }
+
+func closeHandle(h fileHandle) error { return nil }
+
+func init() {}
+`,
+		"file_windows.go": `package app
+
+type fileHandle uintptr
+
+// PathSeparator is the OS path separator.
+const PathSeparator = '\\'
+
+// Open opens the file using CreateFile.
+func Open(name string) (*File, error) { return &File{}, nil }
+
+func closeHandle(h fileHandle) error { return nil }
+
+func init() {}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	mod, err := NewModule(filepath.Join(dir, "go.mod"))
+	require.NoError(t, err)
+
+	var pkg *GoPackage
+	err = ParseSinglePackageWalker(ParseConfig{Module: mod, AllBuildTags: true, Internal: true}, func(p *GoPackage) error {
+		pkg = p
+		return nil
+	}, dir)
+	require.NoError(t, err)
+	require.NotNil(t, pkg)
+	require.Len(t, pkg.Files, 3)
+
+	constraints := map[string]string{}
+	for _, f := range pkg.Files {
+		constraints[filepath.Base(f.FilePath)] = f.Constraint
+	}
+	assert.Equal(t, map[string]string{"file.go": "", "file_linux.go": "linux", "file_windows.go": "windows"}, constraints)
+
+	funcs := map[string]*GoStructMethod{}
+	inits := 0
+	for _, m := range pkg.StructMethods {
+		if m.Name == "init" {
+			inits++
+			continue
+		}
+		_, dup := funcs[m.Name]
+		assert.False(t, dup, "%s is merged", m.Name)
+		funcs[m.Name] = m
+	}
+	assert.Equal(t, 3, inits, "init functions are not merged")
+
+	require.Contains(t, funcs, "Open")
+	require.Len(t, funcs["Open"].Variants, 2)
+	assert.Equal(t, "linux", funcs["Open"].Variants[0].File.Constraint)
+	assert.Equal(t, "Open opens the file using CreateFile.", funcs["Open"].Variants[1].Doc)
+	assert.Nil(t, funcs["Close"].Variants)
+
+	require.Len(t, pkg.CustomTypes, 1)
+	assert.Len(t, pkg.CustomTypes[0].Variants, 2)
+	require.Len(t, pkg.ConstAssignments, 1)
+	assert.Len(t, pkg.ConstAssignments[0].Variants, 2)
+}
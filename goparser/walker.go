@@ -61,6 +61,7 @@ func aggregatePackage(module *GoModule, dir string, goFiles []*GoFile) *GoPackag
 		if len(gf.ConstAssignments) > 0 {
 			pkg.ConstAssignments = append(pkg.ConstAssignments, gf.ConstAssignments...)
 		}
+		pkg.Directives = append(pkg.Directives, gf.Directives...)
 		// Collect unique build tags from all files
 		for _, tag := range gf.BuildTags {
 			buildTagsSet[tag] = struct{}{}
@@ -76,10 +77,28 @@ func aggregatePackage(module *GoModule, dir string, goFiles []*GoFile) *GoPackag
 		sort.Strings(pkg.BuildTags)
 	}
 
+	mergePackageVariants(pkg)
+	pkg.Generated, pkg.Generator = packageGenerated(goFiles)
 	pkg.Doc = strings.TrimSuffix(b.String(), "\n")
 	return pkg
 }
 
+// packageGenerated reports a package as generated when all of its files are, the
+// generator is set when all files share the same generator.
+func packageGenerated(goFiles []*GoFile) (bool, string) {
+	generator := goFiles[0].Generator
+	for _, gf := range goFiles {
+		if !gf.Generated {
+			return false, ""
+		}
+		if gf.Generator != generator {
+			generator = ""
+		}
+	}
+
+	return true, generator
+}
+
 func groupFilesByDir(paths []string) map[string][]string {
 	result := make(map[string][]string)
 	for _, p := range paths {
@@ -126,6 +145,10 @@ func collectPackages(
 
 		pkg := aggregatePackage(module, dir, goFiles)
 		if pkg != nil {
+			if removed := config.SymbolFilter.Apply(pkg); len(removed) > 0 {
+				debugf(debug, "collectPackages: filtered out %s", strings.Join(removed, ", "))
+			}
+
 			packages = append(packages, pkg)
 			debugf(
 				debug,
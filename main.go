@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/alexflint/go-arg"
@@ -44,6 +45,18 @@ var templateStructs string
 //go:embed defaults/receivers.gtpl
 var templateReceivers string
 
+//go:embed defaults/types.gtpl
+var templateTypes string
+
+//go:embed defaults/members.gtpl
+var templateMembers string
+
+//go:embed defaults/testreport.gtpl
+var templateTestReport string
+
+//go:embed defaults/diagnostics.gtpl
+var templateDiagnostics string
+
 //go:embed defaults/var.gtpl
 var templateVarAssignment string
 
@@ -71,6 +84,9 @@ var templateCustomTypeDefinitions string
 //go:embed defaults/module.gtpl
 var templateModule string
 
+//go:embed defaults/implementation.gtpl
+var templateImplementation string
+
 //go:embed defaults/package-ref.gtpl
 var templatePackageRef string
 
@@ -92,19 +108,41 @@ type args struct {
 	Overrides              []string `arg:"-r,separate"                help:"name=template filepath to override default templates"`
 	Paths                  []string `arg:"positional"                 help:"Directory or files to be included in scan (if none, current path is used)"                                placeholder:"PATH"`
 	Excludes               []string `arg:"--exclude,separate"         help:"Regex or glb: prefixed glob-like patterns to exclude paths (e.g., --exclude='glb:**/.temp-files/**'"`
+	IncludeSymbol          []string `arg:"--include-symbol,separate"  help:"Only documents the symbols matching [KIND[,KIND]=]PATTERN, a regex or glb: glob of the (qualified) name (can specify multiple)" placeholder:"FILTER"`
+	ExcludeSymbol          []string `arg:"--exclude-symbol,separate"  help:"Skips the symbols matching [KIND[,KIND]=]PATTERN, e.g. 'glb:*Mock*' or 'func=glb:New*Option' (can specify multiple)"           placeholder:"FILTER"`
 	ListTemplates          bool     `arg:"--list-template"            help:"Lists all default templates in the binary"`
+	CheckTemplates         bool     `arg:"--check-templates"          help:"Validates the templates (names, parsing, and execution against a synthetic model) and exits"`
 	OutputTemplate         string   `arg:"--out-template"             help:"outputs a template to stdout"`
 	PackageDoc             []string `arg:"-d,separate"                help:"set relative package search filepaths for package documentation"                                          placeholder:"FILEPATH"`
 	TemplateDir            string   `                                 help:"Loads template files *.gtpl from a directory, use --list to get valid names of templates"`
 	TypeLinks              string   `arg:"--type-links"               help:"Controls type reference linking: disabled, internal, or external (default disabled)"`
 	Concatenation          string   `arg:"--concatenation"            help:"Controls doc comment concatenation: none or full (default none)"                                                                 default:"none"`
 	Highlighter            string   `arg:"--highlighter"              help:"Source code highlighter to use; available: highlightjs, goasciidoc (custom highlightjs)"                                         default:"highlightjs"`
-	Render                 []string `arg:"--render,separate"          help:"Controls what examples to render for structs: struct-json, struct-yaml (can specify multiple)"`
+	Render                 []string `arg:"--render,separate"          help:"Controls what examples to render for structs: struct-json, struct-yaml, struct-xml, struct-toml, struct-jsonschema (can specify multiple)"`
+	SchemaDir              string   `arg:"--schema-dir"               help:"Writes a standalone JSON Schema (<Struct>.schema.json) per struct into the directory"                     placeholder:"PATH"`
+	TestReport             string   `arg:"--test-report"              help:"Writes a test report (<package>_test.adoc) of the tests, benchmarks and fuzz targets per package into the directory" placeholder:"PATH"`
+	Diagnostics            string   `arg:"--diagnostics"              help:"Writes the problems found when generating, e.g. type-check errors and unresolved declarations, as JSON to the file" placeholder:"PATH"`
+	DiagnosticsAppendix    string   `arg:"--diagnostics-appendix"     help:"Writes the problems found when generating as an asciidoc appendix to the file"                     placeholder:"PATH"`
+	Strict                 bool     `arg:"--strict"                   help:"Exits with status 1 when any problems are found when generating"`
 	BuildTag               []string `arg:"--build-tag,separate"       help:"Build tags to include when parsing (can specify multiple, e.g., --build-tag=integration --build-tag=dev)" placeholder:"TAG"`
 	AllBuildTags           bool     `arg:"--all-build-tags"           help:"Auto-discover and include all build tags found in source files"`
 	IgnoreMarkdownHeadings bool     `arg:"--ignore-markdown-headings" help:"Replace markdown headings (#, ##, etc.) in comments with their text content"`
 	SubModule              string   `arg:"--sub-module"               help:"Submodule processing mode: none, single, or separate (default none)"                                                             default:"none"`
 	PackageMode            string   `arg:"--package-mode"             help:"Package-level rendering mode: none, include, or link (default none)"                                                             default:"none"`
+	IncludeCode            bool     `arg:"--include-code"             help:"Renders the implementation of functions and methods as collapsible blocks (//goasciidoc:code selects per package or symbol)"`
+	Layout                 string   `arg:"--layout"                   help:"Package layout: kind (a section per kind) or godoc (types followed by their constants, variables, constructors and methods)"      default:"kind"`
+	Sort                   string   `arg:"--sort"                     help:"Symbol order within sections: source, alphabetical, or grouped (by associated type)"                                             default:"source"`
+	Generated              string   `arg:"--generated"                help:"Generated code handling: include, exclude, or include-with-badge (default include)"                                              default:"include"`
+	UsedBy                 string   `arg:"--used-by"                  help:"Renders 'Used By' sections for types: none, module, or workspace (default none)"                                                 default:"none"`
+	ExternalLink           []string `arg:"--external-link,separate"   help:"External link rule, e.g. 'corp.com/* -> https://godoc.corp/{path}#{symbol}' (can specify multiple)"       placeholder:"RULE"`
+	Manifest               string   `arg:"--manifest"                 help:"Writes a symbol manifest (JSON) that other projects link to with --link-manifest"                         placeholder:"PATH"`
+	ManifestURL            string   `arg:"--manifest-url"             help:"The URL where the documentation is published, used for the manifest links"                                placeholder:"URL"`
+	LinkManifest           []string `arg:"--link-manifest,separate"   help:"Links external symbols to the docs in a manifest, path or file URL (can specify multiple)"                placeholder:"PATH"`
+	Since                  bool     `arg:"--since"                    help:"Renders 'Since vX.Y.Z' badges on exported symbols derived from the local git tags"`
+	TemplateData           []string `arg:"--template-data,separate"   help:"Named value available in templates as {{.Config.Data.name}} (can specify multiple)"                       placeholder:"NAME=VALUE"`
+	Lint                   bool     `arg:"--lint"                     help:"Lints the documentation comments instead of generating documentation, exits with status 1 on errors"`
+	LintFormat             string   `arg:"--lint-format"              help:"Lint output format: text, json, or sarif (default text)"                                                             default:"text"`
+	LintRule               []string `arg:"--lint-rule,separate"       help:"Sets the severity of a lint rule, e.g. missing-doc=error or doc-prefix=off (can specify multiple)"              placeholder:"RULE=SEVERITY"`
 }
 
 func (args) Version() string {
@@ -139,6 +177,13 @@ func runner(args args) {
 		p.Excludes(args.Excludes...)
 	}
 
+	filter, err := goparser.NewSymbolFilter(args.IncludeSymbol, args.ExcludeSymbol)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	p.SymbolFilter(filter)
+
 	// Handle workspace and sub-module configuration
 	subModuleMode, err := parseSubModuleMode(args.SubModule)
 	if err != nil {
@@ -154,6 +199,69 @@ func runner(args args) {
 	}
 	p.PackageMode(packageMode)
 
+	layout, err := parseLayout(args.Layout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	p.Layout(layout)
+
+	sortOrder, err := parseSortOrder(args.Sort)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	p.SortOrder(sortOrder)
+
+	generated, err := parseGenerated(args.Generated)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	p.Generated(generated)
+
+	usedBy, err := parseUsedBy(args.UsedBy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	p.UsedBy(usedBy)
+
+	for _, link := range args.ExternalLink {
+		rule, err := asciidoc.ParseExternalLinkRule(link)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		p.ExternalLinks(rule)
+	}
+
+	for _, location := range args.LinkManifest {
+		manifest, err := asciidoc.LoadSymbolManifest(location)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		p.LinkManifests(manifest)
+	}
+
+	if args.Manifest != "" {
+		p.SymbolManifest(args.Manifest, args.ManifestURL)
+	}
+
+	if args.Since {
+		p.History(true)
+	}
+
+	for _, data := range args.TemplateData {
+		name, value, err := parseTemplateData(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		p.TemplateData(name, value)
+	}
+
 	// Determine search path
 	searchPath := args.Module
 	if searchPath == "" && len(args.Paths) > 0 {
@@ -258,19 +366,42 @@ func runner(args args) {
 		p.RenderOptions(renderOpts)
 	}
 
+	if args.SchemaDir != "" {
+		p.SchemaOutputDir(args.SchemaDir)
+	}
+
+	if args.TestReport != "" {
+		p.TestReportOutputDir(args.TestReport)
+	}
+
+	if args.Diagnostics != "" {
+		p.DiagnosticsOutput(args.Diagnostics)
+	}
+
+	if args.DiagnosticsAppendix != "" {
+		p.DiagnosticsAppendix(args.DiagnosticsAppendix)
+	}
+
 	p.Override(string(asciidoc.ConstDeclarationTemplate), templateConstAssignment)
 	p.Override(string(asciidoc.ConstDeclarationsTemplate), templateConstAssignments)
 	p.Override(string(asciidoc.FunctionTemplate), templateFunction)
 	p.Override(string(asciidoc.FunctionsTemplate), templateFunctions)
 	p.Override(string(asciidoc.ImportTemplate), templateImports)
-	// Append module template to index template so it can be used with ExecuteTemplate
-	p.Override(string(asciidoc.IndexTemplate), templateIndex+"\n"+templateModule)
+	p.Override(string(asciidoc.IndexTemplate), templateIndex)
+	// The module template is rendered from the index template using ExecuteTemplate
+	p.Partial("module", templateModule)
+	// The implementation of functions and methods is shared by the function and receivers templates
+	p.Partial("implementation", templateImplementation)
 	p.Override(string(asciidoc.InterfaceTemplate), templateInterface)
 	p.Override(string(asciidoc.InterfacesTemplate), templateInterfaces)
 	p.Override(string(asciidoc.PackageTemplate), templatePackage)
 	p.Override(string(asciidoc.PackageRefTemplate), templatePackageRef)
 	p.Override(string(asciidoc.PackageRefsTemplate), templatePackageRefs)
 	p.Override(string(asciidoc.ReceiversTemplate), templateReceivers)
+	p.Override(string(asciidoc.TypesTemplate), templateTypes)
+	p.Override(string(asciidoc.MembersTemplate), templateMembers)
+	p.Override(string(asciidoc.TestReportTemplate), templateTestReport)
+	p.Override(string(asciidoc.DiagnosticsTemplate), templateDiagnostics)
 	p.Override(string(asciidoc.StructTemplate), templateStruct)
 	p.Override(string(asciidoc.StructsTemplate), templateStructs)
 	p.Override(string(asciidoc.CustomFuncTypeDefTemplate), templateCustomFuncDefintion)
@@ -294,6 +425,9 @@ func runner(args args) {
 	if args.Test {
 		p.IncludeTest()
 	}
+	if args.IncludeCode {
+		p.IncludeMethodCode()
+	}
 	if args.NoIndex {
 		p.NoIndex()
 	}
@@ -313,6 +447,11 @@ func runner(args args) {
 			}
 
 			name := baseName(file.Name())
+			if strings.HasPrefix(name, "_") {
+				p.PartialFilePath(strings.TrimPrefix(name, "_"), filepath.Join(args.TemplateDir, file.Name()))
+				continue
+			}
+
 			p.OverrideFilePath(name, filepath.Join(args.TemplateDir, file.Name()))
 		}
 	}
@@ -335,11 +474,25 @@ func runner(args args) {
 		p.PackageDoc(args.PackageDoc...)
 	}
 
+	if args.CheckTemplates {
+
+		issues := p.CheckTemplates()
+		for _, issue := range issues {
+			fmt.Fprintln(os.Stderr, issue.String())
+		}
+
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+
+		fmt.Println("All templates are valid")
+		return
+	}
+
 	if args.ListTemplates {
 
-		t := p.CreateTemplateWithOverrides()
-		for k := range t.Templates {
-			fmt.Println(k)
+		for _, name := range templateNames(p) {
+			fmt.Println(name)
 		}
 
 		return
@@ -347,8 +500,8 @@ func runner(args args) {
 
 	if args.OutputTemplate != "" {
 
-		if t, ok := p.CreateTemplateWithOverrides().Templates[args.OutputTemplate]; ok {
-			fmt.Printf(`"%s"`+"\n", t.Text)
+		if text, ok := templateText(p, args.OutputTemplate); ok {
+			fmt.Printf(`"%s"`+"\n", text)
 			return
 		}
 
@@ -372,7 +525,61 @@ func runner(args args) {
 		p.IgnoreMarkdownHeadings(true)
 	}
 
+	if args.Lint {
+		os.Exit(lint(p, args))
+	}
+
 	p.Generate()
+
+	diagnostics := p.Diagnostics()
+	if wd, err := os.Getwd(); err == nil {
+		diagnostics = asciidoc.RelativeDiagnostics(wd, diagnostics)
+	}
+
+	for _, d := range diagnostics {
+		fmt.Fprintln(os.Stderr, d.String())
+	}
+
+	if summary := asciidoc.SummarizeDiagnostics(diagnostics); summary != "" {
+		fmt.Fprintf(os.Stderr, "goasciidoc: %s\n", summary)
+		if args.Strict {
+			os.Exit(1)
+		}
+	}
+}
+
+// templateNames returns the names of the templates, sorted, followed by the partials prefixed
+// with an underscore as their files in a --templatedir.
+func templateNames(p *asciidoc.Producer) []string {
+	var names []string
+	for name := range p.CreateTemplateWithOverrides().Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var partials []string
+	for name := range p.Partials() {
+		partials = append(partials, "_"+name)
+	}
+	sort.Strings(partials)
+
+	return append(names, partials...)
+}
+
+// templateText returns the text of the template, or partial with or without the underscore
+// prefix. The index template includes the module partial it renders.
+func templateText(p *asciidoc.Producer, name string) (string, bool) {
+	partials := p.Partials()
+	if t, ok := p.CreateTemplateWithOverrides().Templates[name]; ok {
+		if name == asciidoc.IndexTemplate.String() && partials["module"] != "" {
+			return t.Text + "\n" + partials["module"], true
+		}
+
+		return t.Text, true
+	}
+
+	text, ok := partials[strings.TrimPrefix(name, "_")]
+	return text, ok
 }
 
 func baseName(s string) string {
@@ -419,6 +626,165 @@ func parsePackageMode(value string) (asciidoc.PackageMode, error) {
 	}
 }
 
+func parseLayout(value string) (asciidoc.LayoutMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "kind", "":
+		return asciidoc.LayoutKind, nil
+	case "godoc":
+		return asciidoc.LayoutGodoc, nil
+	default:
+		return asciidoc.LayoutKind, fmt.Errorf(
+			"unknown --layout %q (valid: kind, godoc)",
+			value,
+		)
+	}
+}
+
+func parseSortOrder(value string) (asciidoc.SortOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "source", "":
+		return asciidoc.SortSource, nil
+	case "alphabetical":
+		return asciidoc.SortAlphabetical, nil
+	case "grouped":
+		return asciidoc.SortGrouped, nil
+	default:
+		return asciidoc.SortSource, fmt.Errorf(
+			"unknown --sort %q (valid: source, alphabetical, grouped)",
+			value,
+		)
+	}
+}
+
+func parseGenerated(value string) (goparser.GeneratedPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "include", "":
+		return goparser.GeneratedInclude, nil
+	case "exclude":
+		return goparser.GeneratedExclude, nil
+	case "include-with-badge", "badge":
+		return goparser.GeneratedBadge, nil
+	default:
+		return goparser.GeneratedInclude, fmt.Errorf(
+			"unknown --generated policy %q (valid: include, exclude, include-with-badge)",
+			value,
+		)
+	}
+}
+
+func parseUsedBy(value string) (asciidoc.UsedByMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "none", "disabled", "":
+		return asciidoc.UsedByDisabled, nil
+	case "module":
+		return asciidoc.UsedByModule, nil
+	case "workspace":
+		return asciidoc.UsedByWorkspace, nil
+	default:
+		return asciidoc.UsedByDisabled, fmt.Errorf(
+			"unknown --used-by mode %q (valid: none, module, workspace)",
+			value,
+		)
+	}
+}
+
+// lint lints the documentation, writes the diagnostics to stdout and returns the exit status.
+func lint(p *asciidoc.Producer, args args) int {
+	format, err := parseLintFormat(args.LintFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	for _, rule := range args.LintRule {
+		name, severity, err := parseLintRule(rule)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		p.LintSeverity(name, severity)
+	}
+
+	diagnostics, err := p.Lint()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	// Paths relative the working directory, as expected by e.g. code scanning.
+	if wd, err := os.Getwd(); err == nil {
+		diagnostics = asciidoc.RelativeDiagnostics(wd, diagnostics)
+	}
+
+	if err := asciidoc.WriteDiagnostics(os.Stdout, format, diagnostics); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	for _, d := range diagnostics {
+		if d.Severity == asciidoc.SeverityError {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+func parseLintFormat(value string) (asciidoc.DiagnosticFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "text", "":
+		return asciidoc.DiagnosticsText, nil
+	case "json":
+		return asciidoc.DiagnosticsJSON, nil
+	case "sarif":
+		return asciidoc.DiagnosticsSARIF, nil
+	default:
+		return asciidoc.DiagnosticsText, fmt.Errorf(
+			"unknown --lint-format %q (valid: text, json, sarif)",
+			value,
+		)
+	}
+}
+
+func parseLintRule(value string) (string, asciidoc.Severity, error) {
+	name, level, ok := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+
+	known := false
+	for _, rule := range asciidoc.LintRules() {
+		known = known || rule.Name == name
+	}
+	if !ok || !known {
+		return "", "", fmt.Errorf(
+			"invalid --lint-rule %q (expected rule=severity, rules: missing-doc, doc-prefix, unresolved-reference, missing-overview, unbalanced-delimiter)",
+			value,
+		)
+	}
+
+	switch severity := asciidoc.Severity(strings.ToLower(strings.TrimSpace(level))); severity {
+	case asciidoc.SeverityError, asciidoc.SeverityWarning, asciidoc.SeverityInfo, asciidoc.SeverityOff:
+		return name, severity, nil
+	}
+
+	return "", "", fmt.Errorf(
+		"unknown severity in --lint-rule %q (valid: error, warning, info, off)",
+		value,
+	)
+}
+
+func parseTemplateData(value string) (string, string, error) {
+	name, data, ok := strings.Cut(value, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf(
+			"invalid --template-data %q (expected name=value)",
+			value,
+		)
+	}
+
+	return name, data, nil
+}
+
 func parseTypeLinks(value string) (asciidoc.TypeLinkMode, error) {
 	if value == "" {
 		return asciidoc.TypeLinksDisabled, nil
@@ -484,9 +850,15 @@ func parseRenderOptions(values []string) (map[string]bool, error) {
 			renderOpts["struct-json"] = true
 		case "struct-yaml":
 			renderOpts["struct-yaml"] = true
+		case "struct-xml":
+			renderOpts["struct-xml"] = true
+		case "struct-toml":
+			renderOpts["struct-toml"] = true
+		case "struct-jsonschema":
+			renderOpts["struct-jsonschema"] = true
 		default:
 			return nil, fmt.Errorf(
-				"unknown --render option %q (valid: struct-json, struct-yaml)",
+				"unknown --render option %q (valid: struct-json, struct-yaml, struct-xml, struct-toml, struct-jsonschema)",
 				v,
 			)
 		}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/mariotoffia/goasciidoc/asciidoc"
+	"github.com/mariotoffia/goasciidoc/goparser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -197,3 +198,165 @@ func TestPackageTemplatesAreLoaded(t *testing.T) {
 		assert.NotEmpty(t, pkgRefs.Text, "package-refs template text should be populated")
 	}
 }
+
+func TestParseGenerated(t *testing.T) {
+	tests := []struct {
+		input      string
+		expect     goparser.GeneratedPolicy
+		shouldFail bool
+	}{
+		{input: "", expect: goparser.GeneratedInclude},
+		{input: "include", expect: goparser.GeneratedInclude},
+		{input: "Exclude", expect: goparser.GeneratedExclude},
+		{input: " include-with-badge ", expect: goparser.GeneratedBadge},
+		{input: "badge", expect: goparser.GeneratedBadge},
+		{input: "skip", shouldFail: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseGenerated(tc.input)
+			if tc.shouldFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestParseUsedBy(t *testing.T) {
+	tests := []struct {
+		input      string
+		expect     asciidoc.UsedByMode
+		shouldFail bool
+	}{
+		{input: "", expect: asciidoc.UsedByDisabled},
+		{input: "none", expect: asciidoc.UsedByDisabled},
+		{input: "Module", expect: asciidoc.UsedByModule},
+		{input: " workspace ", expect: asciidoc.UsedByWorkspace},
+		{input: "all", shouldFail: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseUsedBy(tc.input)
+			if tc.shouldFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestParseTemplateData(t *testing.T) {
+	tests := []struct {
+		input      string
+		name       string
+		value      string
+		shouldFail bool
+	}{
+		{input: "company=ACME", name: "company", value: "ACME"},
+		{input: " url =https://acme.io/?a=b", name: "url", value: "https://acme.io/?a=b"},
+		{input: "empty=", name: "empty", value: ""},
+		{input: "company", shouldFail: true},
+		{input: "=ACME", shouldFail: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			name, value, err := parseTemplateData(tc.input)
+			if tc.shouldFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.name, name)
+			assert.Equal(t, tc.value, value)
+		})
+	}
+}
+
+func TestParseLintFormat(t *testing.T) {
+	tests := []struct {
+		input      string
+		expect     asciidoc.DiagnosticFormat
+		shouldFail bool
+	}{
+		{input: "", expect: asciidoc.DiagnosticsText},
+		{input: "text", expect: asciidoc.DiagnosticsText},
+		{input: " JSON ", expect: asciidoc.DiagnosticsJSON},
+		{input: "sarif", expect: asciidoc.DiagnosticsSARIF},
+		{input: "xml", shouldFail: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseLintFormat(tc.input)
+			if tc.shouldFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, got)
+		})
+	}
+}
+
+func TestParseLintRule(t *testing.T) {
+	tests := []struct {
+		input      string
+		name       string
+		severity   asciidoc.Severity
+		shouldFail bool
+	}{
+		{input: "missing-doc=error", name: "missing-doc", severity: asciidoc.SeverityError},
+		{input: " doc-prefix = Off", name: "doc-prefix", severity: asciidoc.SeverityOff},
+		{input: "unbalanced-delimiter=info", name: "unbalanced-delimiter", severity: asciidoc.SeverityInfo},
+		{input: "missing-doc", shouldFail: true},
+		{input: "spelling=error", shouldFail: true},
+		{input: "missing-doc=fatal", shouldFail: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			name, severity, err := parseLintRule(tc.input)
+			if tc.shouldFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.name, name)
+			assert.Equal(t, tc.severity, severity)
+		})
+	}
+}
+
+func TestTemplateNamesAndTextIncludePartials(t *testing.T) {
+	p := asciidoc.NewProducer()
+	p.Override(string(asciidoc.IndexTemplate), templateIndex)
+	p.Partial("module", templateModule)
+	p.Partial("implementation", templateImplementation)
+
+	names := templateNames(p)
+	assert.Contains(t, names, asciidoc.IndexTemplate.String())
+	assert.Equal(t, []string{"_implementation", "_module"}, names[len(names)-2:])
+
+	text, ok := templateText(p, asciidoc.IndexTemplate.String())
+	assert.True(t, ok)
+	assert.Equal(t, templateIndex+"\n"+templateModule, text)
+
+	text, ok = templateText(p, "module")
+	assert.True(t, ok)
+	assert.Equal(t, templateModule, text)
+
+	text, ok = templateText(p, "_implementation")
+	assert.True(t, ok)
+	assert.Equal(t, templateImplementation, text)
+
+	_, ok = templateText(p, "nope")
+	assert.False(t, ok)
+}